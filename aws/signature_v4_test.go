@@ -0,0 +1,63 @@
+package aws
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/ex"
+)
+
+// testSignatureV4Config is the config used by the aws signature v4 test suite.
+var testSignatureV4Config = Config{
+	Region:          "us-east-1",
+	AccessKeyID:     "AKIDEXAMPLE",
+	SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+}
+
+func TestSignV4(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Date(2015, 8, 30, 12, 36, 00, 00, time.UTC)
+	req := &http.Request{
+		Method: http.MethodGet,
+		Host:   "example.amazonaws.com",
+		URL:    &url.URL{Scheme: "https", Host: "example.amazonaws.com", Path: "/"},
+	}
+	assert.Nil(SignV4(req, nil, testSignatureV4Config, "service", now))
+	assert.Equal("20150830T123600Z", req.Header.Get(HeaderAmzDate))
+	assert.Equal("AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=host;x-amz-date, Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31", req.Header.Get(HeaderAuthorization))
+}
+
+func TestSignV4QueryOrder(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Date(2015, 8, 30, 12, 36, 00, 00, time.UTC)
+	req := &http.Request{
+		Method: http.MethodGet,
+		URL:    &url.URL{Scheme: "https", Host: "example.amazonaws.com", Path: "/", RawQuery: "Param2=value2&Param1=value1"},
+	}
+	assert.Nil(SignV4(req, nil, testSignatureV4Config, "service", now))
+	assert.Equal("AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=host;x-amz-date, Signature=b97d918cfa904a5beff61c982a1b6f458b799221646efd99d3219ec94cdf2500", req.Header.Get(HeaderAuthorization))
+}
+
+func TestSignV4SecurityToken(t *testing.T) {
+	assert := assert.New(t)
+
+	cfg := testSignatureV4Config
+	cfg.SecurityToken = "session-token"
+	req := &http.Request{Method: http.MethodPut, URL: &url.URL{Scheme: "https", Host: "bucket.s3.amazonaws.com", Path: "/foo bar"}}
+	assert.Nil(SignV4(req, []byte("contents"), cfg, "s3", time.Now()))
+	assert.Equal("session-token", req.Header.Get(HeaderAmzSecurityToken))
+	assert.Equal("d1b2a59fbea7e20077af9f91b27e95e865061b270be03ff539ab3b73587882e8", req.Header.Get(HeaderAmzContentSHA256))
+	assert.Contains(req.Header.Get(HeaderAuthorization), "SignedHeaders=host;x-amz-content-sha256;x-amz-date;x-amz-security-token,")
+}
+
+func TestSignV4CredentialsUnset(t *testing.T) {
+	assert := assert.New(t)
+
+	req := &http.Request{Method: http.MethodGet, URL: &url.URL{Scheme: "https", Host: "example.amazonaws.com"}}
+	assert.True(ex.Is(SignV4(req, nil, Config{}, "service", time.Now()), ErrSignatureV4CredentialsUnset))
+}
@@ -0,0 +1,90 @@
+package bufferutil
+
+import (
+	"bytes"
+	"sync"
+)
+
+// NewBuffer returns a new buffer with the given initial contents.
+func NewBuffer(contents []byte) *Buffer {
+	b := new(Buffer)
+	b.buffer.Write(contents)
+	return b
+}
+
+// NewBufferString returns a new buffer with the given initial contents.
+func NewBufferString(contents string) *Buffer {
+	b := new(Buffer)
+	b.buffer.WriteString(contents)
+	return b
+}
+
+// Buffer is a bytes buffer that is safe to write to and read from concurrently,
+// e.g. to read the output of a running command while it is written.
+//
+// Reads return copies of the contents, and do not consume them, so they can be
+// read from an offset as more contents are written.
+type Buffer struct {
+	sync.Mutex
+	buffer bytes.Buffer
+}
+
+// Write implements io.Writer.
+func (b *Buffer) Write(contents []byte) (int, error) {
+	b.Lock()
+	defer b.Unlock()
+	return b.buffer.Write(contents)
+}
+
+// WriteString writes a string to the buffer.
+func (b *Buffer) WriteString(contents string) (int, error) {
+	b.Lock()
+	defer b.Unlock()
+	return b.buffer.WriteString(contents)
+}
+
+// Len returns the length of the contents.
+func (b *Buffer) Len() int {
+	b.Lock()
+	defer b.Unlock()
+	return b.buffer.Len()
+}
+
+// Bytes returns a copy of the contents.
+func (b *Buffer) Bytes() []byte {
+	return b.BytesFrom(0)
+}
+
+// BytesFrom returns a copy of the contents after an offset.
+// It returns nil if the offset is past the end of the contents.
+func (b *Buffer) BytesFrom(offset int) []byte {
+	b.Lock()
+	defer b.Unlock()
+	contents := b.buffer.Bytes()
+	if offset < 0 {
+		offset = 0
+	}
+	if offset >= len(contents) {
+		return nil
+	}
+	output := make([]byte, len(contents)-offset)
+	copy(output, contents[offset:])
+	return output
+}
+
+// String returns the contents as a string.
+func (b *Buffer) String() string {
+	if b == nil {
+		return "<nil>"
+	}
+	b.Lock()
+	defer b.Unlock()
+	return b.buffer.String()
+}
+
+// Reset empties the buffer.
+func (b *Buffer) Reset() {
+	b.Lock()
+	defer b.Unlock()
+	b.buffer.Reset()
+}
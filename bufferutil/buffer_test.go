@@ -0,0 +1,57 @@
+package bufferutil
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestBuffer(t *testing.T) {
+	assert := assert.New(t)
+
+	buffer := NewBufferString("foo")
+	_, err := buffer.Write([]byte("bar"))
+	assert.Nil(err)
+	_, err = buffer.WriteString("baz")
+	assert.Nil(err)
+
+	assert.Equal(9, buffer.Len())
+	assert.Equal("foobarbaz", buffer.String())
+	assert.Equal("foobarbaz", string(buffer.Bytes()))
+	assert.Equal("barbaz", string(buffer.BytesFrom(3)))
+	assert.Nil(buffer.BytesFrom(9))
+	assert.Equal("foobarbaz", string(buffer.BytesFrom(-1)))
+
+	contents := buffer.Bytes()
+	contents[0] = 'g'
+	assert.Equal("foobarbaz", buffer.String(), "reads should return copies")
+
+	buffer.Reset()
+	assert.Zero(buffer.Len())
+	assert.Equal("bar", NewBuffer([]byte("bar")).String())
+}
+
+func TestBufferConcurrent(t *testing.T) {
+	assert := assert.New(t)
+
+	buffer := new(Buffer)
+	wg := sync.WaitGroup{}
+	for x := 0; x < 4; x++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for y := 0; y < 100; y++ {
+				buffer.WriteString("a")
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for y := 0; y < 100; y++ {
+				buffer.BytesFrom(y)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(400, buffer.Len())
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"runtime"
+
+	"github.com/blend/go-sdk/openapi"
+)
+
+// linker metadata block
+// this block must be present
+// it is used by goreleaser
+var (
+	version = "dev"
+	commit  = "none"
+	date    = "unknown"
+)
+
+func main() {
+	var specFile string
+	flag.StringVar(&specFile, "f", "", "The OpenAPI 3 spec file to generate a client for (json or yaml)")
+
+	var packageName string
+	flag.StringVar(&packageName, "package", "client", "The package name of the generated client")
+
+	var outFile string
+	flag.StringVar(&outFile, "o", "", "Output file; if unset, the client is written to os.Stdout")
+
+	var help bool
+	flag.BoolVar(&help, "help", false, "Shows this usage message")
+
+	var versionFlag bool
+	flag.BoolVar(&versionFlag, "version", false, "Shows the app version")
+
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "%s version %s\n\n", os.Args[0], version)
+		fmt.Fprintf(os.Stderr, "Generates typed r2 api clients from OpenAPI 3 specs.\n\n")
+		fmt.Fprintf(os.Stderr, "Usage:\n")
+		flag.PrintDefaults()
+		fmt.Fprintf(os.Stderr, "\nExample Usage:\n")
+		fmt.Fprintf(os.Stderr, "Generate a client: \"apiclient -f openapi.yml -package users -o users/client.go\"\n")
+	}
+
+	flag.Parse()
+
+	if help {
+		flag.Usage()
+		os.Exit(0)
+	}
+
+	if versionFlag {
+		fmt.Fprintf(os.Stdout, "%s version %s %s/%s\n", os.Args[0], version, runtime.GOOS, runtime.GOARCH)
+		os.Exit(0)
+	}
+
+	if specFile == "" {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	spec, err := openapi.ReadSpec(specFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	contents, err := openapi.Generate(spec, packageName)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if outFile == "" {
+		os.Stdout.Write(contents)
+		return
+	}
+	if err := ioutil.WriteFile(outFile, contents, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
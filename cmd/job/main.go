@@ -181,7 +181,12 @@ func run(cmd *cobra.Command, args []string) error {
 		log.Infof("adding airbrake notifications")
 	}
 
-	jobs := cron.New(cron.OptConfig(cfg.Config.Cron), cron.OptLog(log))
+	jobsOptions := []cron.JobManagerOption{cron.OptConfig(cfg.Config.Cron), cron.OptLog(log)}
+	if cfg.HistoryPath != "" {
+		jobsOptions = append(jobsOptions, cron.OptHistoryStore(cron.NewFileHistoryStore(cfg.HistoryPath)))
+		log.Infof("using job history path `%s`", cfg.HistoryPath)
+	}
+	jobs := cron.New(jobsOptions...)
 
 	for _, jobCfg := range cfg.Jobs {
 		job, err := createJobFromConfig(jobCfg)
@@ -190,7 +195,9 @@ func run(cmd *cobra.Command, args []string) error {
 		}
 		job.WithLogger(log).WithEmailClient(emailClient).WithSlackClient(slackClient).WithStatsClient(statsClient).WithErrorClient(errorClient)
 		log.Infof("loading job `%s` with schedule `%s`", jobCfg.Name, jobCfg.ScheduleOrDefault())
-		jobs.LoadJobs(job)
+		if err := jobs.LoadJobs(job); err != nil {
+			return err
+		}
 	}
 
 	hosted := []graceful.Graceful{jobs}
@@ -0,0 +1,14 @@
+package cron
+
+// ConcurrencyPolicy is how a job handles a run that starts while a previous run is still running.
+type ConcurrencyPolicy string
+
+// Concurrency policies.
+const (
+	// ConcurrencyPolicyAllow lets runs overlap.
+	ConcurrencyPolicyAllow ConcurrencyPolicy = "allow"
+	// ConcurrencyPolicyForbid skips a run if a previous run is still running.
+	ConcurrencyPolicyForbid ConcurrencyPolicy = "forbid"
+	// ConcurrencyPolicyReplace cancels the running run, waits for it to finish, then starts the new run.
+	ConcurrencyPolicyReplace ConcurrencyPolicy = "replace"
+)
@@ -0,0 +1,88 @@
+package cron
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestJobSchedulerConcurrencyPolicy(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(ConcurrencyPolicyAllow, NewJobScheduler(NewJob("test", noop)).ConcurrencyPolicy())
+	assert.Equal(ConcurrencyPolicyForbid, NewJobScheduler(serialJob{NewJob("test", noop)}).ConcurrencyPolicy())
+	assert.Equal(ConcurrencyPolicyReplace, NewJobScheduler(NewJob("test", noop, OptJobBuilderConcurrencyPolicy(ConcurrencyPolicyReplace))).ConcurrencyPolicy())
+}
+
+type serialJob struct {
+	*JobBuilder
+}
+
+func (sj serialJob) Serial() bool { return true }
+
+// blockingJob returns a job that signals when it starts, and blocks until it is released or cancelled.
+func blockingJob(policy ConcurrencyPolicy, started chan struct{}, release chan struct{}, runs *int32) Job {
+	return NewJob("blocking", func(ctx context.Context) error {
+		atomic.AddInt32(runs, 1)
+		started <- struct{}{}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-release:
+			return nil
+		}
+	}, OptJobBuilderConcurrencyPolicy(policy))
+}
+
+func TestJobSchedulerConcurrencyPolicyForbid(t *testing.T) {
+	assert := assert.New(t)
+
+	var runs int32
+	started, release, done := make(chan struct{}, 2), make(chan struct{}), make(chan struct{})
+	js := NewJobScheduler(blockingJob(ConcurrencyPolicyForbid, started, release, &runs))
+	go func() {
+		defer close(done)
+		js.Run()
+	}()
+	<-started
+
+	js.Run()
+	assert.Equal(int32(1), atomic.LoadInt32(&runs))
+	close(release)
+	<-done
+}
+
+func TestJobSchedulerConcurrencyPolicyReplace(t *testing.T) {
+	assert := assert.New(t)
+
+	var runs int32
+	started, release, done := make(chan struct{}, 2), make(chan struct{}), make(chan struct{})
+	js := NewJobScheduler(
+		blockingJob(ConcurrencyPolicyReplace, started, release, &runs),
+		OptJobSchedulerLocker(NewLocalLocker()),
+		OptJobSchedulerConfig(Config{LockMinHold: time.Minute}),
+	)
+	go func() {
+		defer close(done)
+		js.Run()
+	}()
+	<-started
+
+	replaced := make(chan struct{})
+	go func() {
+		defer close(replaced)
+		js.Run()
+	}()
+	<-done
+	<-started
+	close(release)
+	<-replaced
+
+	assert.Equal(int32(2), atomic.LoadInt32(&runs))
+	assert.Len(js.History, 2)
+	assert.Equal(JobStatusCancelled, js.History[0].Status)
+	assert.Equal(JobStatusComplete, js.History[1].Status)
+}
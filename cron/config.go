@@ -6,10 +6,12 @@ import (
 	"github.com/blend/go-sdk/configutil"
 )
 
-// Config governs job history retention in memory.
+// Config governs job history retention and locking.
 type Config struct {
 	HistoryMaxCount int           `json:"historyMaxCount" yaml:"historyMaxCount" env:"CRON_HISTORY_MAX_COUNT"`
 	HistoryMaxAge   time.Duration `json:"historyMaxAge" yaml:"historyMaxAge" env:"CRON_HISTORY_MAX_AGE"`
+	LockTTL         time.Duration `json:"lockTTL" yaml:"lockTTL" env:"CRON_LOCK_TTL"`
+	LockMinHold     time.Duration `json:"lockMinHold" yaml:"lockMinHold" env:"CRON_LOCK_MIN_HOLD"`
 }
 
 // Resolve adds extra resolution steps when reading the config.
@@ -17,6 +19,8 @@ func (hc Config) Resolve() error {
 	return configutil.AnyError(
 		configutil.SetInt(&hc.HistoryMaxCount, configutil.Int(hc.HistoryMaxCount), configutil.Parse(configutil.Env("CRON_HISTORY_MAX_COUNT")), configutil.Int(DefaultHistoryMaxCount)),
 		configutil.SetDuration(&hc.HistoryMaxAge, configutil.Duration(hc.HistoryMaxAge), configutil.Parse(configutil.Env("CRON_HISTORY_MAX_AGE")), configutil.Duration(DefaultHistoryMaxAge)),
+		configutil.SetDuration(&hc.LockTTL, configutil.Duration(hc.LockTTL), configutil.Parse(configutil.Env("CRON_LOCK_TTL")), configutil.Duration(DefaultLockTTL)),
+		configutil.SetDuration(&hc.LockMinHold, configutil.Duration(hc.LockMinHold), configutil.Parse(configutil.Env("CRON_LOCK_MIN_HOLD")), configutil.Duration(DefaultLockMinHold)),
 	)
 }
 
@@ -35,3 +39,19 @@ func (hc Config) HistoryMaxAgeOrDefault() time.Duration {
 	}
 	return DefaultHistoryMaxAge
 }
+
+// LockTTLOrDefault returns the lock ttl or a default.
+func (hc Config) LockTTLOrDefault() time.Duration {
+	if hc.LockTTL > 0 {
+		return hc.LockTTL
+	}
+	return DefaultLockTTL
+}
+
+// LockMinHoldOrDefault returns the lock minimum hold or a default.
+func (hc Config) LockMinHoldOrDefault() time.Duration {
+	if hc.LockMinHold > 0 {
+		return hc.LockMinHold
+	}
+	return DefaultLockMinHold
+}
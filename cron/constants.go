@@ -13,6 +13,32 @@ const (
 const (
 	DefaultHistoryMaxCount = 10
 	DefaultHistoryMaxAge   = 6 * time.Hour
+	// DefaultHistoryCullInterval is the minimum time between culls of a job's history in the history store.
+	DefaultHistoryCullInterval = time.Minute
+)
+
+// Lock defaults
+const (
+	// DefaultLockTTL is the default time a job lease is held for before it must be renewed.
+	DefaultLockTTL = 30 * time.Second
+	// DefaultLockMinHold is the default minimum time a job lease is held for, even if the job finishes sooner,
+	// so replicas whose clocks are slightly behind do not run the same scheduled invocation.
+	DefaultLockMinHold = 5 * time.Second
+)
+
+// Misfire defaults
+const (
+	// DefaultMisfireMaxRuns is the default maximum number of missed runs to run.
+	DefaultMisfireMaxRuns = 10
+	// DefaultMisfireMaxWalk is the maximum number of scheduled runtimes walked to find missed runs,
+	// which bounds the walk over a long downtime for a frequent schedule.
+	DefaultMisfireMaxWalk = 10000
+)
+
+// Retry defaults
+const (
+	// DefaultRetryBackoffMultiplier is the default factor the delay between retries grows by.
+	DefaultRetryBackoffMultiplier = 2.0
 )
 
 const (
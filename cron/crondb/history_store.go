@@ -0,0 +1,245 @@
+package crondb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/blend/go-sdk/cron"
+	"github.com/blend/go-sdk/db"
+	"github.com/blend/go-sdk/db/migration"
+	"github.com/blend/go-sdk/ex"
+)
+
+var (
+	_ cron.HistoryStore = (*HistoryStore)(nil)
+)
+
+// DefaultHistoryTable is the default history table name.
+const DefaultHistoryTable = "cron_history"
+
+// NewHistoryStore returns a new history store.
+func NewHistoryStore(conn *db.Connection, options ...HistoryStoreOption) *HistoryStore {
+	hs := &HistoryStore{
+		Conn:  conn,
+		Table: DefaultHistoryTable,
+	}
+	for _, option := range options {
+		option(hs)
+	}
+	return hs
+}
+
+// HistoryStoreOption is an option for history stores.
+type HistoryStoreOption func(*HistoryStore)
+
+// OptHistoryTable sets the history table name.
+func OptHistoryTable(table string) HistoryStoreOption {
+	return func(hs *HistoryStore) { hs.Table = table }
+}
+
+// HistoryStore is a job history store backed by a database table.
+type HistoryStore struct {
+	Conn  *db.Connection
+	Table string
+}
+
+// Migrations returns the migrations to create the history table.
+func (hs *HistoryStore) Migrations() *migration.Group {
+	return migration.NewGroupWithActions(
+		migration.NewStep(
+			migration.TableNotExists(hs.Table),
+			migration.Statements(
+				fmt.Sprintf(`CREATE TABLE %s (
+					id varchar(255) not null primary key,
+					job_name varchar(1024) not null,
+					started timestamp with time zone not null,
+					finished timestamp with time zone,
+					cancelled timestamp with time zone,
+					timeout timestamp with time zone,
+					err text,
+					elapsed bigint not null default 0,
+					status varchar(255) not null,
+					state jsonb
+				)`, hs.Table),
+				fmt.Sprintf(`CREATE INDEX ix_%s_job_name_started ON %s (job_name, started)`, hs.Table, hs.Table),
+			),
+		),
+		migration.NewStep(
+			migration.ColumnNotExists(hs.Table, "attempt"),
+			migration.Statements(
+				fmt.Sprintf(`ALTER TABLE %s ADD COLUMN attempt int not null default 0`, hs.Table),
+				fmt.Sprintf(`ALTER TABLE %s ADD COLUMN retry_of varchar(255)`, hs.Table),
+			),
+		),
+		migration.NewStep(
+			migration.ColumnNotExists(hs.Table, "parameters"),
+			migration.Statements(
+				fmt.Sprintf(`ALTER TABLE %s ADD COLUMN parameters jsonb`, hs.Table),
+			),
+		),
+	)
+}
+
+// Add implements cron.HistoryStore.
+func (hs *HistoryStore) Add(ctx context.Context, ji cron.JobInvocation) error {
+	record, err := cron.NewHistoryRecord(ji)
+	if err != nil {
+		return err
+	}
+	var state *string
+	if len(record.State) > 0 {
+		value := string(record.State)
+		state = &value
+	}
+	var parameters *string
+	if len(record.Parameters) > 0 {
+		contents, err := json.Marshal(record.Parameters)
+		if err != nil {
+			return ex.New(err)
+		}
+		value := string(contents)
+		parameters = &value
+	}
+	var recordErr, retryOf *string
+	if record.Err != "" {
+		recordErr = &record.Err
+	}
+	if record.RetryOf != "" {
+		retryOf = &record.RetryOf
+	}
+	return db.IgnoreExecResult(hs.Conn.Invoke(db.OptContext(ctx)).Exec(
+		fmt.Sprintf(`INSERT INTO %s (id, job_name, started, finished, cancelled, timeout, err, elapsed, status, state, attempt, retry_of, parameters)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+			ON CONFLICT (id) DO NOTHING`, hs.Table),
+		record.ID, record.JobName, record.Started, optionalTime(record.Finished), optionalTime(record.Cancelled), optionalTime(record.Timeout),
+		recordErr, int64(record.Elapsed), string(record.Status), state, record.Attempt, retryOf, parameters,
+	))
+}
+
+// Get implements cron.HistoryStore.
+func (hs *HistoryStore) Get(ctx context.Context, jobName, id string) (*cron.JobInvocation, error) {
+	var output *cron.JobInvocation
+	err := hs.Conn.Invoke(db.OptContext(ctx)).Query(
+		fmt.Sprintf("SELECT %s FROM %s WHERE job_name = $1 AND id = $2", historyColumns, hs.Table),
+		jobName, id,
+	).First(func(r db.Rows) error {
+		ji, err := scanJobInvocation(r)
+		if err != nil {
+			return err
+		}
+		output = &ji
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+// List implements cron.HistoryStore.
+func (hs *HistoryStore) List(ctx context.Context, jobName string, offset, limit int) ([]cron.JobInvocation, error) {
+	statement := fmt.Sprintf("SELECT %s FROM %s WHERE job_name = $1 ORDER BY started DESC OFFSET $2", historyColumns, hs.Table)
+	args := []interface{}{jobName, offset}
+	if limit > 0 {
+		statement = statement + " LIMIT $3"
+		args = append(args, limit)
+	}
+	var output []cron.JobInvocation
+	err := hs.Conn.Invoke(db.OptContext(ctx)).Query(statement, args...).Each(func(r db.Rows) error {
+		ji, err := scanJobInvocation(r)
+		if err != nil {
+			return err
+		}
+		output = append(output, ji)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+// Count implements cron.HistoryStore.
+func (hs *HistoryStore) Count(ctx context.Context, jobName string) (count int, err error) {
+	_, err = hs.Conn.Invoke(db.OptContext(ctx)).Query(
+		fmt.Sprintf("SELECT count(*) FROM %s WHERE job_name = $1", hs.Table), jobName,
+	).Scan(&count)
+	return
+}
+
+// Cull implements cron.HistoryStore.
+func (hs *HistoryStore) Cull(ctx context.Context, jobName string, retention cron.HistoryRetention, now time.Time) error {
+	if retention.MaxAge > 0 {
+		err := db.IgnoreExecResult(hs.Conn.Invoke(db.OptContext(ctx)).Exec(
+			fmt.Sprintf("DELETE FROM %s WHERE job_name = $1 AND started < $2", hs.Table),
+			jobName, now.Add(-retention.MaxAge),
+		))
+		if err != nil {
+			return err
+		}
+	}
+	if retention.MaxCount > 0 {
+		err := db.IgnoreExecResult(hs.Conn.Invoke(db.OptContext(ctx)).Exec(
+			fmt.Sprintf(`DELETE FROM %s WHERE job_name = $1 AND id NOT IN (
+				SELECT id FROM %s WHERE job_name = $1 ORDER BY started DESC LIMIT $2
+			)`, hs.Table, hs.Table),
+			jobName, retention.MaxCount,
+		))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//
+// internal helpers
+//
+
+const historyColumns = "id, job_name, started, finished, cancelled, timeout, err, elapsed, status, state, attempt, retry_of, parameters"
+
+func scanJobInvocation(r db.Rows) (cron.JobInvocation, error) {
+	var record cron.HistoryRecord
+	var finished, cancelled, timeout *time.Time
+	var recordErr, state, retryOf, parameters *string
+	var elapsed int64
+	var status string
+	if err := r.Scan(&record.ID, &record.JobName, &record.Started, &finished, &cancelled, &timeout, &recordErr, &elapsed, &status, &state, &record.Attempt, &retryOf, &parameters); err != nil {
+		return cron.JobInvocation{}, ex.New(err)
+	}
+	if finished != nil {
+		record.Finished = *finished
+	}
+	if cancelled != nil {
+		record.Cancelled = *cancelled
+	}
+	if timeout != nil {
+		record.Timeout = *timeout
+	}
+	if recordErr != nil {
+		record.Err = *recordErr
+	}
+	if state != nil {
+		record.State = json.RawMessage(*state)
+	}
+	if retryOf != nil {
+		record.RetryOf = *retryOf
+	}
+	if parameters != nil {
+		if err := json.Unmarshal([]byte(*parameters), &record.Parameters); err != nil {
+			return cron.JobInvocation{}, ex.New(err)
+		}
+	}
+	record.Elapsed = time.Duration(elapsed)
+	record.Status = cron.JobStatus(status)
+	return record.JobInvocation(), nil
+}
+
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
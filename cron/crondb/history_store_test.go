@@ -0,0 +1,79 @@
+package crondb
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/cron"
+	"github.com/blend/go-sdk/ex"
+)
+
+func TestHistoryStore(t *testing.T) {
+	assert := assert.New(t)
+
+	store := NewHistoryStore(defaultDB(), OptHistoryTable(buildTestTableName()))
+	defer dropTestTable(store.Table)
+	assert.Nil(store.Migrations().Action(context.Background(), defaultDB()))
+
+	now := time.Date(2019, 10, 10, 12, 00, 00, 00, time.UTC)
+	for x := 0; x < 5; x++ {
+		ji := cron.JobInvocation{
+			ID:       cron.NewJobInvocationID(),
+			JobName:  "test-job",
+			Started:  now.Add(time.Duration(x) * time.Minute),
+			Finished: now.Add(time.Duration(x)*time.Minute + time.Second),
+			Elapsed:  time.Second,
+			Status:   cron.JobStatusComplete,
+			State:    map[string]interface{}{"index": x},
+		}
+		if x == 4 {
+			ji.Status = cron.JobStatusFailed
+			ji.Err = ex.New("test failure")
+			ji.Attempt = 2
+			ji.RetryOf = "previous-attempt"
+		}
+		assert.Nil(store.Add(context.Background(), ji))
+	}
+
+	count, err := store.Count(context.Background(), "test-job")
+	assert.Nil(err)
+	assert.Equal(5, count)
+
+	page, err := store.List(context.Background(), "test-job", 0, 2)
+	assert.Nil(err)
+	assert.Len(page, 2)
+	assert.Equal(cron.JobStatusFailed, page[0].Status)
+	assert.Equal("test failure", page[0].Err.Error())
+	assert.Equal(now.Add(4*time.Minute), page[0].Started)
+	assert.True(page[0].Cancelled.IsZero())
+	assert.Equal(2, page[0].Attempt)
+	assert.Equal("previous-attempt", page[0].RetryOf)
+	assert.Empty(page[1].RetryOf)
+
+	var state map[string]interface{}
+	assert.Nil(json.Unmarshal(page[1].State.(json.RawMessage), &state))
+	assert.Equal(3.0, state["index"])
+
+	fetched, err := store.Get(context.Background(), "test-job", page[1].ID)
+	assert.Nil(err)
+	assert.NotNil(fetched)
+	assert.Equal(page[1].ID, fetched.ID)
+
+	fetched, err = store.Get(context.Background(), "test-job", "not-found")
+	assert.Nil(err)
+	assert.Nil(fetched)
+
+	assert.Nil(store.Cull(context.Background(), "test-job", cron.HistoryRetention{MaxCount: 3}, now.Add(time.Hour)))
+	count, err = store.Count(context.Background(), "test-job")
+	assert.Nil(err)
+	assert.Equal(3, count)
+
+	assert.Nil(store.Cull(context.Background(), "test-job", cron.HistoryRetention{MaxAge: 90 * time.Second}, now.Add(5*time.Minute)))
+	page, err = store.List(context.Background(), "test-job", 0, 0)
+	assert.Nil(err)
+	assert.Len(page, 1)
+	assert.Equal(now.Add(4*time.Minute), page[0].Started)
+}
@@ -0,0 +1,121 @@
+package crondb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/blend/go-sdk/cron"
+	"github.com/blend/go-sdk/db"
+	"github.com/blend/go-sdk/db/migration"
+	"github.com/blend/go-sdk/ex"
+)
+
+var (
+	_ cron.Locker = (*Locker)(nil)
+)
+
+// DefaultLeaseTable is the default lease table name.
+const DefaultLeaseTable = "cron_lease"
+
+// NewLocker returns a new locker.
+func NewLocker(conn *db.Connection, options ...LockerOption) *Locker {
+	l := &Locker{
+		Conn:  conn,
+		Table: DefaultLeaseTable,
+	}
+	for _, option := range options {
+		option(l)
+	}
+	return l
+}
+
+// LockerOption is an option for lockers.
+type LockerOption func(*Locker)
+
+// OptLeaseTable sets the lease table name.
+func OptLeaseTable(table string) LockerOption {
+	return func(l *Locker) { l.Table = table }
+}
+
+// Locker is a locker backed by a lease table.
+//
+// Leases are rows with an expiry rather than session advisory locks, so they do not depend on
+// which pooled connection a statement runs on, and survive connection resets. Expiry is
+// computed with the database clock so replicas do not need synchronized clocks to agree on it.
+type Locker struct {
+	Conn  *db.Connection
+	Table string
+}
+
+// Migrations returns the migrations to create the lease table.
+func (l *Locker) Migrations() *migration.Group {
+	return migration.NewGroupWithActions(
+		migration.NewStep(
+			migration.TableNotExists(l.Table),
+			migration.Statements(
+				fmt.Sprintf(`CREATE TABLE %s (
+					key varchar(1024) not null primary key,
+					holder varchar(255) not null,
+					token bigint not null,
+					expires timestamp with time zone not null
+				)`, l.Table),
+			),
+		),
+	)
+}
+
+// Acquire implements cron.Locker.
+func (l *Locker) Acquire(ctx context.Context, key, holder string, ttl time.Duration) (*cron.Lease, error) {
+	lease := cron.Lease{
+		Key:    key,
+		Holder: holder,
+	}
+	found, err := l.Conn.Invoke(db.OptContext(ctx)).Query(
+		fmt.Sprintf(`INSERT INTO %s AS lease (key, holder, token, expires)
+			VALUES ($1, $2, 1, now() + $3 * interval '1 microsecond')
+			ON CONFLICT (key) DO UPDATE SET
+				holder = excluded.holder,
+				token = lease.token + 1,
+				expires = excluded.expires
+			WHERE lease.expires <= now()
+			RETURNING token, expires`, l.Table),
+		key, holder, ttl.Nanoseconds()/int64(time.Microsecond),
+	).Scan(&lease.Token, &lease.Expires)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+	lease.Expires = lease.Expires.UTC()
+	return &lease, nil
+}
+
+// Renew implements cron.Locker.
+func (l *Locker) Renew(ctx context.Context, lease *cron.Lease, ttl time.Duration) error {
+	var expires time.Time
+	found, err := l.Conn.Invoke(db.OptContext(ctx)).Query(
+		fmt.Sprintf(`UPDATE %s SET expires = now() + $4 * interval '1 microsecond'
+			WHERE key = $1 AND holder = $2 AND token = $3
+			RETURNING expires`, l.Table),
+		lease.Key, lease.Holder, lease.Token, ttl.Nanoseconds()/int64(time.Microsecond),
+	).Scan(&expires)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return ex.New(cron.ErrLeaseLost, ex.OptMessagef("key: %s", lease.Key))
+	}
+	lease.Expires = expires.UTC()
+	return nil
+}
+
+// Release implements cron.Locker.
+// The lease is expired rather than deleted so fencing tokens keep increasing.
+func (l *Locker) Release(ctx context.Context, lease *cron.Lease) error {
+	return db.IgnoreExecResult(l.Conn.Invoke(db.OptContext(ctx)).Exec(
+		fmt.Sprintf("UPDATE %s SET expires = now() WHERE key = $1 AND holder = $2 AND token = $3", l.Table),
+		lease.Key, lease.Holder, lease.Token,
+	))
+}
@@ -0,0 +1,47 @@
+package crondb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/cron"
+)
+
+func TestLocker(t *testing.T) {
+	assert := assert.New(t)
+
+	locker := NewLocker(defaultDB(), OptLeaseTable(buildTestTableName()))
+	defer dropTestTable(locker.Table)
+	assert.Nil(locker.Migrations().Action(context.Background(), defaultDB()))
+
+	lease, err := locker.Acquire(context.Background(), "test-job", "replica-a", time.Minute)
+	assert.Nil(err)
+	assert.NotNil(lease)
+	assert.Equal(int64(1), lease.Token)
+
+	held, err := locker.Acquire(context.Background(), "test-job", "replica-b", time.Minute)
+	assert.Nil(err)
+	assert.Nil(held)
+
+	assert.Nil(locker.Renew(context.Background(), lease, time.Minute))
+	assert.Nil(locker.Release(context.Background(), lease))
+
+	next, err := locker.Acquire(context.Background(), "test-job", "replica-b", time.Minute)
+	assert.Nil(err)
+	assert.NotNil(next)
+	assert.Equal(int64(2), next.Token)
+
+	// the released lease can no longer be renewed.
+	assert.True(cron.IsLeaseLost(locker.Renew(context.Background(), lease, time.Minute)))
+
+	expired, err := locker.Acquire(context.Background(), "expired-job", "replica-a", time.Microsecond)
+	assert.Nil(err)
+	assert.NotNil(expired)
+	time.Sleep(time.Millisecond)
+	taken, err := locker.Acquire(context.Background(), "expired-job", "replica-b", time.Minute)
+	assert.Nil(err)
+	assert.NotNil(taken)
+	assert.Equal(int64(2), taken.Token)
+}
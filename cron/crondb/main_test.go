@@ -0,0 +1,44 @@
+package crondb
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	// tests use postgres
+	_ "github.com/lib/pq"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/db"
+	"github.com/blend/go-sdk/logger"
+	"github.com/blend/go-sdk/stringutil"
+)
+
+// TestMain is the testing entrypoint.
+func TestMain(m *testing.M) {
+	conn, err := db.New(db.OptConfigFromEnv())
+	if err != nil {
+		logger.FatalExit(err)
+	}
+	if err = conn.Open(); err != nil {
+		logger.FatalExit(err)
+	}
+	defaultConnection = conn
+	assert.Main(m)
+}
+
+var (
+	defaultConnection *db.Connection
+)
+
+func defaultDB() *db.Connection {
+	return defaultConnection
+}
+
+func buildTestTableName() string {
+	return fmt.Sprintf("test_cron_%s", stringutil.Random(stringutil.LowerLetters, 10))
+}
+
+func dropTestTable(table string) error {
+	return db.IgnoreExecResult(defaultDB().Invoke(db.OptContext(context.Background())).Exec(fmt.Sprintf("DROP TABLE IF EXISTS %s", table)))
+}
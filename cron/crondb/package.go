@@ -0,0 +1,6 @@
+/*
+Package crondb provides `db.Connection` backed implementations of cron stores,
+allowing job history to survive restarts, and jobs to be locked so they run once
+across the replicas of a job manager.
+*/
+package crondb
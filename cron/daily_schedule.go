@@ -36,10 +36,48 @@ func WeekendsAtUTC(hour, minute, second int) Schedule {
 	return &DailySchedule{DayOfWeekMask: WeekendDaysMask, TimeOfDayUTC: time.Date(0, 0, 0, hour, minute, second, 0, time.UTC)}
 }
 
+// WeeklyAt returns a schedule that fires on every of the given days at the given time by hour, minute and second in a location.
+func WeeklyAt(hour, minute, second int, location *time.Location, days ...time.Weekday) Schedule {
+	dayOfWeekMask := uint(0)
+	for _, day := range days {
+		dayOfWeekMask = dayOfWeekMask | 1<<uint(day)
+	}
+
+	return &DailySchedule{DayOfWeekMask: dayOfWeekMask, TimeOfDayUTC: time.Date(0, 0, 0, hour, minute, second, 0, time.UTC), Location: location}
+}
+
+// DailyAt returns a schedule that fires every day at the given hour, minute and second in a location.
+func DailyAt(hour, minute, second int, location *time.Location) Schedule {
+	return &DailySchedule{DayOfWeekMask: AllDaysMask, TimeOfDayUTC: time.Date(0, 0, 0, hour, minute, second, 0, time.UTC), Location: location}
+}
+
+// WeekdaysAt returns a schedule that fires every week day at the given hour, minute and second in a location.
+func WeekdaysAt(hour, minute, second int, location *time.Location) Schedule {
+	return &DailySchedule{DayOfWeekMask: WeekDaysMask, TimeOfDayUTC: time.Date(0, 0, 0, hour, minute, second, 0, time.UTC), Location: location}
+}
+
+// WeekendsAt returns a schedule that fires every weekend day at the given hour, minute and second in a location.
+func WeekendsAt(hour, minute, second int, location *time.Location) Schedule {
+	return &DailySchedule{DayOfWeekMask: WeekendDaysMask, TimeOfDayUTC: time.Date(0, 0, 0, hour, minute, second, 0, time.UTC), Location: location}
+}
+
 // DailySchedule is a schedule that fires every day that satisfies the DayOfWeekMask at the given TimeOfDayUTC.
+//
+// If Location is set, the clock of TimeOfDayUTC is the wall clock time in that location,
+// and days of the week are days in that location; see `InLocation` for how daylight saving
+// time transitions are handled.
 type DailySchedule struct {
 	DayOfWeekMask uint
 	TimeOfDayUTC  time.Time
+	Location      *time.Location
+}
+
+// LocationOrDefault returns the schedule location or UTC.
+func (ds DailySchedule) LocationOrDefault() *time.Location {
+	if ds.Location != nil {
+		return ds.Location
+	}
+	return time.UTC
 }
 
 func (ds DailySchedule) String() string {
@@ -50,9 +88,16 @@ func (ds DailySchedule) String() string {
 				days = append(days, d.String())
 			}
 		}
-		return fmt.Sprintf("%s on %s each week", ds.TimeOfDayUTC.Format(time.RFC3339), strings.Join(days, ", "))
+		return fmt.Sprintf("%s on %s each week%s", ds.TimeOfDayUTC.Format(time.RFC3339), strings.Join(days, ", "), ds.locationSuffix())
 	}
-	return fmt.Sprintf("%s every day", ds.TimeOfDayUTC.Format(time.RFC3339))
+	return fmt.Sprintf("%s every day%s", ds.TimeOfDayUTC.Format(time.RFC3339), ds.locationSuffix())
+}
+
+func (ds DailySchedule) locationSuffix() string {
+	if ds.Location != nil {
+		return " in " + ds.Location.String()
+	}
+	return ""
 }
 
 func (ds DailySchedule) checkDayOfWeekMask(day time.Weekday) bool {
@@ -67,11 +112,15 @@ func (ds DailySchedule) Next(after time.Time) time.Time {
 		after = Now()
 	}
 
-	todayInstance := time.Date(after.Year(), after.Month(), after.Day(), ds.TimeOfDayUTC.Hour(), ds.TimeOfDayUTC.Minute(), ds.TimeOfDayUTC.Second(), 0, time.UTC)
+	location := ds.LocationOrDefault()
+	today := wallClock(after, location)
+	todayInstance := time.Date(today.Year(), today.Month(), today.Day(), ds.TimeOfDayUTC.Hour(), ds.TimeOfDayUTC.Minute(), ds.TimeOfDayUTC.Second(), 0, time.UTC)
 	for day := 0; day < 8; day++ {
-		next := todayInstance.AddDate(0, 0, day) //the first run here it should be adding nothing, i.e. returning todayInstance ...
-
-		if ds.checkDayOfWeekMask(next.Weekday()) && next.After(after) { //we're on a day ...
+		nextWall := todayInstance.AddDate(0, 0, day) //the first run here it should be adding nothing, i.e. returning todayInstance ...
+		if !ds.checkDayOfWeekMask(nextWall.Weekday()) {
+			continue
+		}
+		if next := InLocation(nextWall, location); next.After(after) { //we're on a day ...
 			return next
 		}
 	}
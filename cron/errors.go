@@ -14,6 +14,24 @@ const (
 
 	// ErrJobCancelled is a common error.
 	ErrJobCancelled ex.Class = "job cancelled"
+
+	// ErrJobDependencyCycle is returned when job dependencies form a cycle.
+	ErrJobDependencyCycle ex.Class = "job dependency cycle"
+
+	// ErrJobDependencyInvalid is returned when a job lists an empty or duplicate dependency.
+	ErrJobDependencyInvalid ex.Class = "job dependency invalid"
+
+	// ErrUpstreamFailed is the error of invocations skipped because a job they depend on failed.
+	ErrUpstreamFailed ex.Class = "upstream job failed"
+
+	// ErrUpstreamCancelled is the error of invocations skipped because a job they depend on was cancelled.
+	ErrUpstreamCancelled ex.Class = "upstream job cancelled"
+
+	// ErrParametersInvalid is returned when the parameter values a job is invoked with do not match its parameter schema.
+	ErrParametersInvalid ex.Class = "job parameters invalid"
+
+	// ErrLeaseLost is returned when a lease expires and is acquired by another holder before it is renewed.
+	ErrLeaseLost ex.Class = "lease lost"
 )
 
 // IsJobNotLoaded returns if the error is a job not loaded error.
@@ -35,3 +53,33 @@ func IsJobNotFound(err error) bool {
 func IsJobCancelled(err error) bool {
 	return ex.Is(err, ErrJobCancelled)
 }
+
+// IsLeaseLost returns if the error is a lease lost error.
+func IsLeaseLost(err error) bool {
+	return ex.Is(err, ErrLeaseLost)
+}
+
+// IsJobDependencyCycle returns if the error is a job dependency cycle error.
+func IsJobDependencyCycle(err error) bool {
+	return ex.Is(err, ErrJobDependencyCycle)
+}
+
+// IsJobDependencyInvalid returns if the error is a job dependency invalid error.
+func IsJobDependencyInvalid(err error) bool {
+	return ex.Is(err, ErrJobDependencyInvalid)
+}
+
+// IsUpstreamFailed returns if the error is an upstream failed error.
+func IsUpstreamFailed(err error) bool {
+	return ex.Is(err, ErrUpstreamFailed)
+}
+
+// IsUpstreamCancelled returns if the error is an upstream cancelled error.
+func IsUpstreamCancelled(err error) bool {
+	return ex.Is(err, ErrUpstreamCancelled)
+}
+
+// IsParametersInvalid returns if the error is a parameters invalid error.
+func IsParametersInvalid(err error) bool {
+	return ex.Is(err, ErrParametersInvalid)
+}
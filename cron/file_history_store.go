@@ -0,0 +1,192 @@
+package cron
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/blend/go-sdk/ex"
+)
+
+var (
+	_ HistoryStore = (*FileHistoryStore)(nil)
+)
+
+// NewFileHistoryStore returns a new history store that keeps a file
+// of json lines per job in a given directory.
+func NewFileHistoryStore(path string) *FileHistoryStore {
+	return &FileHistoryStore{
+		Path: path,
+	}
+}
+
+// FileHistoryStore is a history store that keeps a file of json lines per job.
+// Invocations are appended as they finish, and the file is rewritten when it is culled.
+//
+// It is meant for a single process; use a database backed store to share history across processes.
+type FileHistoryStore struct {
+	sync.Mutex
+	Path string
+}
+
+// Add implements HistoryStore.
+func (fhs *FileHistoryStore) Add(_ context.Context, ji JobInvocation) error {
+	record, err := NewHistoryRecord(ji)
+	if err != nil {
+		return err
+	}
+	line, err := json.Marshal(record)
+	if err != nil {
+		return ex.New(err)
+	}
+
+	fhs.Lock()
+	defer fhs.Unlock()
+
+	if err := os.MkdirAll(fhs.Path, 0755); err != nil {
+		return ex.New(err)
+	}
+	f, err := os.OpenFile(fhs.jobPath(ji.JobName), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return ex.New(err)
+	}
+	defer f.Close()
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return ex.New(err)
+	}
+	return nil
+}
+
+// Get implements HistoryStore.
+func (fhs *FileHistoryStore) Get(_ context.Context, jobName, id string) (*JobInvocation, error) {
+	fhs.Lock()
+	defer fhs.Unlock()
+
+	records, err := fhs.read(jobName)
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		if record.ID == id {
+			ji := record.JobInvocation()
+			return &ji, nil
+		}
+	}
+	return nil, nil
+}
+
+// List implements HistoryStore.
+func (fhs *FileHistoryStore) List(_ context.Context, jobName string, offset, limit int) ([]JobInvocation, error) {
+	fhs.Lock()
+	defer fhs.Unlock()
+
+	records, err := fhs.read(jobName)
+	if err != nil {
+		return nil, err
+	}
+	history := make([]JobInvocation, len(records))
+	for index, record := range records {
+		history[len(records)-1-index] = record.JobInvocation()
+	}
+	return HistoryPage(history, offset, limit), nil
+}
+
+// Count implements HistoryStore.
+func (fhs *FileHistoryStore) Count(_ context.Context, jobName string) (int, error) {
+	fhs.Lock()
+	defer fhs.Unlock()
+
+	records, err := fhs.read(jobName)
+	if err != nil {
+		return 0, err
+	}
+	return len(records), nil
+}
+
+// Cull implements HistoryStore.
+func (fhs *FileHistoryStore) Cull(_ context.Context, jobName string, retention HistoryRetention, now time.Time) error {
+	fhs.Lock()
+	defer fhs.Unlock()
+
+	records, err := fhs.read(jobName)
+	if err != nil {
+		return err
+	}
+	var kept []HistoryRecord
+	for index, record := range records {
+		if retention.Keep(index, len(records), record.Started, now) {
+			kept = append(kept, record)
+		}
+	}
+	if len(kept) == len(records) {
+		return nil
+	}
+	return fhs.write(jobName, kept)
+}
+
+//
+// internal helpers
+//
+
+// jobPath returns the path of the history file for a job, escaping the job name
+// so it is a valid file name.
+func (fhs *FileHistoryStore) jobPath(jobName string) string {
+	return filepath.Join(fhs.Path, url.PathEscape(jobName)+".jsonl")
+}
+
+// read reads the records for a job in the order they were added.
+// Lines that cannot be decoded, e.g. a partial line from an interrupted write, are skipped.
+func (fhs *FileHistoryStore) read(jobName string) ([]HistoryRecord, error) {
+	f, err := os.Open(fhs.jobPath(jobName))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, ex.New(err)
+	}
+	defer f.Close()
+
+	var records []HistoryRecord
+	reader := bufio.NewReader(f)
+	for {
+		line, readErr := reader.ReadBytes('\n')
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			var record HistoryRecord
+			if err := json.Unmarshal(line, &record); err == nil {
+				records = append(records, record)
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return nil, ex.New(readErr)
+		}
+	}
+	return records, nil
+}
+
+// write replaces the records for a job, writing to a temporary file first
+// so the history is not lost if the write is interrupted.
+func (fhs *FileHistoryStore) write(jobName string, records []HistoryRecord) error {
+	contents := new(bytes.Buffer)
+	encoder := json.NewEncoder(contents)
+	for _, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			return ex.New(err)
+		}
+	}
+	path := fhs.jobPath(jobName)
+	if err := ioutil.WriteFile(path+".tmp", contents.Bytes(), 0644); err != nil {
+		return ex.New(err)
+	}
+	return ex.New(os.Rename(path+".tmp", path))
+}
@@ -0,0 +1,114 @@
+package cron
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/ex"
+)
+
+func TestFileHistoryStore(t *testing.T) {
+	assert := assert.New(t)
+
+	path, err := ioutil.TempDir("", "cron-history")
+	assert.Nil(err)
+	defer os.RemoveAll(path)
+
+	store := NewFileHistoryStore(path)
+	now := time.Date(2019, 10, 10, 12, 00, 00, 00, time.UTC)
+	for x := 0; x < 5; x++ {
+		ji := JobInvocation{
+			ID:       NewJobInvocationID(),
+			JobName:  "test/job",
+			Started:  now.Add(time.Duration(x) * time.Minute),
+			Finished: now.Add(time.Duration(x)*time.Minute + time.Second),
+			Elapsed:  time.Second,
+			Status:   JobStatusComplete,
+			State:    map[string]interface{}{"index": x},
+		}
+		if x == 4 {
+			ji.Status = JobStatusFailed
+			ji.Err = ex.New("test failure", ex.OptMessage("details"))
+		}
+		assert.Nil(store.Add(context.Background(), ji))
+	}
+	assert.Nil(store.Add(context.Background(), JobInvocation{ID: NewJobInvocationID(), JobName: "other", Started: now}))
+
+	// job names are escaped.
+	_, err = os.Stat(filepath.Join(path, "test%2Fjob.jsonl"))
+	assert.Nil(err)
+
+	count, err := store.Count(context.Background(), "test/job")
+	assert.Nil(err)
+	assert.Equal(5, count)
+
+	page, err := store.List(context.Background(), "test/job", 0, 2)
+	assert.Nil(err)
+	assert.Len(page, 2)
+	assert.Equal(JobStatusFailed, page[0].Status)
+	assert.Equal("test failure; details", page[0].Err.Error())
+	assert.Equal(now.Add(4*time.Minute), page[0].Started)
+
+	var state map[string]interface{}
+	assert.Nil(json.Unmarshal(page[1].State.(json.RawMessage), &state))
+	assert.Equal(3.0, state["index"])
+
+	page, err = store.List(context.Background(), "test/job", 4, 2)
+	assert.Nil(err)
+	assert.Len(page, 1)
+	assert.Equal(now, page[0].Started)
+
+	fetched, err := store.Get(context.Background(), "test/job", page[0].ID)
+	assert.Nil(err)
+	assert.NotNil(fetched)
+	fetched, err = store.Get(context.Background(), "test/job", "not-found")
+	assert.Nil(err)
+	assert.Nil(fetched)
+
+	assert.Nil(store.Cull(context.Background(), "test/job", HistoryRetention{MaxCount: 3}, now.Add(time.Hour)))
+	count, err = store.Count(context.Background(), "test/job")
+	assert.Nil(err)
+	assert.Equal(3, count)
+
+	assert.Nil(store.Cull(context.Background(), "test/job", HistoryRetention{MaxAge: 90 * time.Second}, now.Add(5*time.Minute)))
+	page, err = store.List(context.Background(), "test/job", 0, 0)
+	assert.Nil(err)
+	assert.Len(page, 1)
+	assert.Equal(now.Add(4*time.Minute), page[0].Started)
+
+	// other jobs are not culled.
+	count, err = store.Count(context.Background(), "other")
+	assert.Nil(err)
+	assert.Equal(1, count)
+}
+
+func TestFileHistoryStoreSkipsPartialLines(t *testing.T) {
+	assert := assert.New(t)
+
+	path, err := ioutil.TempDir("", "cron-history")
+	assert.Nil(err)
+	defer os.RemoveAll(path)
+
+	store := NewFileHistoryStore(path)
+	assert.Nil(store.Add(context.Background(), JobInvocation{ID: NewJobInvocationID(), JobName: "test", Started: Now()}))
+
+	f, err := os.OpenFile(filepath.Join(path, "test.jsonl"), os.O_APPEND|os.O_WRONLY, 0644)
+	assert.Nil(err)
+	_, err = f.WriteString(`{"id":"partial","jobNa`)
+	assert.Nil(err)
+	assert.Nil(f.Close())
+
+	count, err := store.Count(context.Background(), "test")
+	assert.Nil(err)
+	assert.Equal(1, count)
+
+	count, err = store.Count(context.Background(), "not-found")
+	assert.Nil(err)
+	assert.Zero(count)
+}
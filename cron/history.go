@@ -0,0 +1,132 @@
+package cron
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/blend/go-sdk/ex"
+)
+
+// HistoryStore persists job invocation history so it survives restarts.
+type HistoryStore interface {
+	// Add adds a finished invocation.
+	Add(ctx context.Context, ji JobInvocation) error
+	// Get returns an invocation for a job by id, or nil if it is not found.
+	Get(ctx context.Context, jobName, id string) (*JobInvocation, error)
+	// List returns a page of invocations for a job, most recent first.
+	// A limit less than or equal to zero returns all the invocations after the offset.
+	List(ctx context.Context, jobName string, offset, limit int) ([]JobInvocation, error)
+	// Count returns the number of invocations stored for a job.
+	Count(ctx context.Context, jobName string) (int, error)
+	// Cull removes invocations for a job that fall outside a retention policy.
+	Cull(ctx context.Context, jobName string, retention HistoryRetention, now time.Time) error
+}
+
+// HistoryRetention is a retention policy for job invocation history.
+// Values that are not positive are unlimited. When returned by a `HistoryRetentionProvider`,
+// zero values fall back to the `Config` values, and negative values are unlimited.
+type HistoryRetention struct {
+	MaxCount int           `json:"maxCount,omitempty" yaml:"maxCount,omitempty"`
+	MaxAge   time.Duration `json:"maxAge,omitempty" yaml:"maxAge,omitempty"`
+}
+
+// Keep returns if an invocation should be kept given its index in a chronological list of a given count,
+// when it started, and the current time.
+func (hr HistoryRetention) Keep(index, count int, started, now time.Time) bool {
+	if hr.MaxCount > 0 && index < (count-hr.MaxCount) {
+		return false
+	}
+	if hr.MaxAge > 0 && now.Sub(started) > hr.MaxAge {
+		return false
+	}
+	return true
+}
+
+// NewHistoryRecord returns the serialized form of an invocation.
+func NewHistoryRecord(ji JobInvocation) (HistoryRecord, error) {
+	record := HistoryRecord{
+		ID:         ji.ID,
+		JobName:    ji.JobName,
+		Started:    ji.Started,
+		Finished:   ji.Finished,
+		Cancelled:  ji.Cancelled,
+		Timeout:    ji.Timeout,
+		Elapsed:    ji.Elapsed,
+		Status:     ji.Status,
+		Attempt:    ji.Attempt,
+		RetryOf:    ji.RetryOf,
+		Parameters: ji.Parameters,
+	}
+	if ji.Err != nil {
+		record.Err = fmt.Sprintf("%v", ji.Err)
+	}
+	if ji.State != nil {
+		state, err := json.Marshal(ji.State)
+		if err != nil {
+			return record, ex.New(err)
+		}
+		record.State = state
+	}
+	return record, nil
+}
+
+// HistoryRecord is the serialized form of a job invocation kept by history stores.
+type HistoryRecord struct {
+	ID         string          `json:"id"`
+	JobName    string          `json:"jobName"`
+	Started    time.Time       `json:"started"`
+	Finished   time.Time       `json:"finished,omitempty"`
+	Cancelled  time.Time       `json:"cancelled,omitempty"`
+	Timeout    time.Time       `json:"timeout,omitempty"`
+	Err        string          `json:"err,omitempty"`
+	Elapsed    time.Duration   `json:"elapsed"`
+	Status     JobStatus       `json:"status"`
+	State      json.RawMessage `json:"state,omitempty"`
+	Attempt    int             `json:"attempt,omitempty"`
+	RetryOf    string          `json:"retryOf,omitempty"`
+	Parameters ParameterValues `json:"parameters,omitempty"`
+}
+
+// JobInvocation returns the invocation for the record.
+//
+// The error is restored as an `ex.Class` of the original error message, and the state
+// as a `json.RawMessage`, which job schedulers pass to jobs that implement `InvocationStateUnmarshaler`.
+func (hr HistoryRecord) JobInvocation() JobInvocation {
+	ji := JobInvocation{
+		ID:         hr.ID,
+		JobName:    hr.JobName,
+		Started:    hr.Started.UTC(),
+		Finished:   hr.Finished.UTC(),
+		Cancelled:  hr.Cancelled.UTC(),
+		Timeout:    hr.Timeout.UTC(),
+		Elapsed:    hr.Elapsed,
+		Status:     hr.Status,
+		Attempt:    hr.Attempt,
+		RetryOf:    hr.RetryOf,
+		Parameters: hr.Parameters,
+	}
+	if hr.Err != "" {
+		ji.Err = ex.Class(hr.Err)
+	}
+	if len(hr.State) > 0 && string(hr.State) != "null" {
+		ji.State = hr.State
+	}
+	return ji
+}
+
+// HistoryPage returns a page of a list of invocations ordered most recent first.
+func HistoryPage(history []JobInvocation, offset, limit int) []JobInvocation {
+	if offset < 0 {
+		offset = 0
+	}
+	if offset >= len(history) {
+		return nil
+	}
+	history = history[offset:]
+	if limit > 0 && limit < len(history) {
+		history = history[:limit]
+	}
+	return history
+}
@@ -0,0 +1,184 @@
+package cron
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestHistoryRetentionKeep(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Date(2019, 10, 10, 12, 00, 00, 00, time.UTC)
+	assert.True(HistoryRetention{}.Keep(0, 100, now.Add(-24*time.Hour), now))
+	assert.False(HistoryRetention{MaxCount: 5}.Keep(4, 10, now, now))
+	assert.True(HistoryRetention{MaxCount: 5}.Keep(5, 10, now, now))
+	assert.False(HistoryRetention{MaxAge: time.Hour}.Keep(0, 1, now.Add(-2*time.Hour), now))
+	assert.True(HistoryRetention{MaxAge: time.Hour}.Keep(0, 1, now.Add(-time.Minute), now))
+}
+
+func TestHistoryPage(t *testing.T) {
+	assert := assert.New(t)
+
+	history := []JobInvocation{{ID: "3"}, {ID: "2"}, {ID: "1"}}
+	assert.Len(HistoryPage(history, 0, 0), 3)
+	assert.Len(HistoryPage(history, 1, 1), 1)
+	assert.Equal("2", HistoryPage(history, 1, 1)[0].ID)
+	assert.Len(HistoryPage(history, 2, 5), 1)
+	assert.Empty(HistoryPage(history, 3, 5))
+	assert.Len(HistoryPage(history, -1, 2), 2)
+}
+
+type historyStateJob struct {
+	*JobBuilder
+}
+
+func (hsj historyStateJob) UnmarshalInvocationState(contents []byte) (interface{}, error) {
+	var state historyState
+	if err := json.Unmarshal(contents, &state); err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+type historyState struct {
+	Output string `json:"output"`
+}
+
+// cullCountingHistoryStore counts the culls of a history store.
+type cullCountingHistoryStore struct {
+	*FileHistoryStore
+	Culls int
+}
+
+func (cchs *cullCountingHistoryStore) Cull(ctx context.Context, jobName string, retention HistoryRetention, now time.Time) error {
+	cchs.Culls++
+	return cchs.FileHistoryStore.Cull(ctx, jobName, retention, now)
+}
+
+func TestJobSchedulerHistoryStoreCull(t *testing.T) {
+	assert := assert.New(t)
+
+	path, err := ioutil.TempDir("", "cron-history")
+	assert.Nil(err)
+	defer os.RemoveAll(path)
+
+	store := &cullCountingHistoryStore{FileHistoryStore: NewFileHistoryStore(path)}
+	js := NewJobScheduler(NewJob("cull-test", noop, OptJobBuilderHistoryRetention(HistoryRetention{MaxCount: 3})),
+		OptJobSchedulerHistoryStore(store),
+	)
+
+	// the store is not culled until the retention policy is exceeded.
+	for x := 0; x < 3; x++ {
+		js.Run()
+	}
+	assert.Zero(store.Culls)
+
+	js.Run()
+	assert.Equal(1, store.Culls)
+	count, err := js.CountHistory(context.Background())
+	assert.Nil(err)
+	assert.Equal(3, count)
+
+	// and then at most once per cull interval.
+	js.Run()
+	js.Run()
+	assert.Equal(1, store.Culls)
+
+	js.historyCulled = Now().Add(-DefaultHistoryCullInterval)
+	js.Run()
+	assert.Equal(2, store.Culls)
+	count, err = js.CountHistory(context.Background())
+	assert.Nil(err)
+	assert.Equal(3, count)
+}
+
+func TestJobManagerHistoryStore(t *testing.T) {
+	assert := assert.New(t)
+
+	path, err := ioutil.TempDir("", "cron-history")
+	assert.Nil(err)
+	defer os.RemoveAll(path)
+
+	newJob := func() Job {
+		return historyStateJob{NewJob("history-test", func(ctx context.Context) error {
+			GetJobInvocation(ctx).State = &historyState{Output: "test output"}
+			return nil
+		}, OptJobBuilderHistoryRetention(HistoryRetention{MaxCount: 2}))}
+	}
+
+	jm := New(OptHistoryStore(NewFileHistoryStore(path)))
+	assert.Nil(jm.LoadJobs(newJob()))
+	js, err := jm.Job("history-test")
+	assert.Nil(err)
+	assert.Equal(2, js.HistoryRetention().MaxCount)
+	assert.Equal(DefaultHistoryMaxAge, js.HistoryRetention().MaxAge)
+	for x := 0; x < 3; x++ {
+		js.Run()
+	}
+	lastID := js.Last.ID
+
+	count, err := js.CountHistory(context.Background())
+	assert.Nil(err)
+	assert.Equal(2, count)
+
+	// a new job manager (e.g. after a restart) loads the history from the store.
+	jm = New(OptHistoryStore(NewFileHistoryStore(path)))
+	assert.Nil(jm.LoadJobs(newJob()))
+	js, err = jm.Job("history-test")
+	assert.Nil(err)
+	assert.Len(js.History, 2)
+	assert.NotNil(js.Last)
+	assert.Equal(lastID, js.Last.ID)
+	assert.Equal(JobStatusComplete, js.Last.Status)
+	state, ok := js.Last.State.(*historyState)
+	assert.True(ok)
+	assert.Equal("test output", state.Output)
+
+	page, err := js.ListHistory(context.Background(), 0, 1)
+	assert.Nil(err)
+	assert.Len(page, 1)
+	assert.Equal(lastID, page[0].ID)
+	_, ok = page[0].State.(*historyState)
+	assert.True(ok)
+
+	js.History = nil
+	ji := js.GetInvocationByID(lastID)
+	assert.NotNil(ji)
+	_, ok = ji.State.(*historyState)
+	assert.True(ok)
+}
+
+func TestJobSchedulerListHistoryInMemory(t *testing.T) {
+	assert := assert.New(t)
+
+	js := NewJobScheduler(NewJob("foo", noop))
+	js.History = []JobInvocation{{ID: "1"}, {ID: "2"}, {ID: "3"}}
+
+	page, err := js.ListHistory(context.Background(), 0, 2)
+	assert.Nil(err)
+	assert.Len(page, 2)
+	assert.Equal("3", page[0].ID)
+
+	count, err := js.CountHistory(context.Background())
+	assert.Nil(err)
+	assert.Equal(3, count)
+}
+
+func TestJobSchedulerHistoryRetentionUnlimited(t *testing.T) {
+	assert := assert.New(t)
+
+	js := NewJobScheduler(NewJob("unlimited-test", noop, OptJobBuilderHistoryRetention(HistoryRetention{MaxCount: -1, MaxAge: -1})))
+	assert.Equal(-1, js.HistoryRetention().MaxCount)
+	assert.Equal(-1, js.HistoryRetention().MaxAge)
+
+	for x := 0; x < DefaultHistoryMaxCount+2; x++ {
+		js.Run()
+	}
+	assert.Len(js.History, DefaultHistoryMaxCount+2)
+}
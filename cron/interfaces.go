@@ -39,10 +39,24 @@ type StatusProvider interface {
 
 // SerialProvider is an optional interface that prohibits
 // a task from running if another instance of the task is currently running.
+// It is equivalent to a `ConcurrencyPolicyForbid` concurrency policy.
 type SerialProvider interface {
 	Serial() bool
 }
 
+// ConcurrencyPolicyProvider is an optional interface that sets how a job handles
+// a run that starts while a previous run is still running.
+// It takes precedence over `SerialProvider` unless it returns an empty policy.
+type ConcurrencyPolicyProvider interface {
+	ConcurrencyPolicy() ConcurrencyPolicy
+}
+
+// MisfirePolicyProvider is an optional interface that sets how a job handles
+// scheduled runs that were missed while it was not scheduled.
+type MisfirePolicyProvider interface {
+	MisfirePolicy() MisfirePolicy
+}
+
 // ShouldTriggerListenersProvider is a type that enables or disables logger listeners.
 type ShouldTriggerListenersProvider interface {
 	ShouldTriggerListeners() bool
@@ -99,3 +113,40 @@ type OnDisabledReceiver interface {
 type OnEnabledReceiver interface {
 	OnEnabled(context.Context)
 }
+
+// HistoryRetentionProvider is an optional interface that lets a job set its own history retention policy.
+// Values that are unset fall back to the job manager config, and negative values are unlimited.
+type HistoryRetentionProvider interface {
+	HistoryRetention() HistoryRetention
+}
+
+// InvocationStateUnmarshaler is an optional interface for jobs that restores
+// invocation state from the json kept by a history store.
+type InvocationStateUnmarshaler interface {
+	UnmarshalInvocationState([]byte) (interface{}, error)
+}
+
+// DependenciesProvider is an optional interface that makes a job run after the jobs it depends on.
+// The job runs once every job it depends on has completed successfully since it last ran, and is
+// skipped if one of them fails or is cancelled. Dependencies must be loaded in the same job manager.
+type DependenciesProvider interface {
+	Dependencies() []string
+}
+
+// ParameterSchemaProvider is an optional interface that declares the parameters a job can be invoked with.
+// Invocations read the values they were invoked with from `GetJobInvocation(ctx).Parameters`.
+type ParameterSchemaProvider interface {
+	ParameterSchema() ParameterSchema
+}
+
+// RetryPolicyProvider is an optional interface that lets a job re-invoke failed invocations.
+type RetryPolicyProvider interface {
+	RetryPolicy() RetryPolicy
+}
+
+// LockerProvider is an optional interface that lets a job set the locker used to acquire
+// a lease before it runs, overriding the job manager locker.
+// Returning nil lets the job run on every replica.
+type LockerProvider interface {
+	Locker() Locker
+}
@@ -13,6 +13,12 @@ var (
 	_ EnabledProvider                = (*JobBuilder)(nil)
 	_ ShouldWriteOutputProvider      = (*JobBuilder)(nil)
 	_ ShouldTriggerListenersProvider = (*JobBuilder)(nil)
+	_ HistoryRetentionProvider       = (*JobBuilder)(nil)
+	_ RetryPolicyProvider            = (*JobBuilder)(nil)
+	_ DependenciesProvider           = (*JobBuilder)(nil)
+	_ ParameterSchemaProvider        = (*JobBuilder)(nil)
+	_ ConcurrencyPolicyProvider      = (*JobBuilder)(nil)
+	_ MisfirePolicyProvider          = (*JobBuilder)(nil)
 	_ OnStartReceiver                = (*JobBuilder)(nil)
 	_ OnCancellationReceiver         = (*JobBuilder)(nil)
 	_ OnCompleteReceiver             = (*JobBuilder)(nil)
@@ -53,6 +59,36 @@ func OptJobBuilderEnabledProvider(provider func() bool) JobBuilderOption {
 	return func(jb *JobBuilder) { jb.EnabledProvider = provider }
 }
 
+// OptJobBuilderHistoryRetention sets the job builder history retention policy.
+func OptJobBuilderHistoryRetention(retention HistoryRetention) JobBuilderOption {
+	return func(jb *JobBuilder) { jb.HistoryRetentionProvider = func() HistoryRetention { return retention } }
+}
+
+// OptJobBuilderRetryPolicy sets the job builder retry policy.
+func OptJobBuilderRetryPolicy(policy RetryPolicy) JobBuilderOption {
+	return func(jb *JobBuilder) { jb.RetryPolicyProvider = func() RetryPolicy { return policy } }
+}
+
+// OptJobBuilderDependencies sets the names of the jobs the job runs after.
+func OptJobBuilderDependencies(jobNames ...string) JobBuilderOption {
+	return func(jb *JobBuilder) { jb.DependenciesProvider = func() []string { return jobNames } }
+}
+
+// OptJobBuilderParameterSchema sets the parameters the job can be invoked with.
+func OptJobBuilderParameterSchema(parameters ...Parameter) JobBuilderOption {
+	return func(jb *JobBuilder) { jb.ParameterSchemaProvider = func() ParameterSchema { return parameters } }
+}
+
+// OptJobBuilderConcurrencyPolicy sets the job builder concurrency policy.
+func OptJobBuilderConcurrencyPolicy(policy ConcurrencyPolicy) JobBuilderOption {
+	return func(jb *JobBuilder) { jb.ConcurrencyPolicyProvider = func() ConcurrencyPolicy { return policy } }
+}
+
+// OptJobBuilderMisfirePolicy sets the job builder misfire policy.
+func OptJobBuilderMisfirePolicy(policy MisfirePolicy) JobBuilderOption {
+	return func(jb *JobBuilder) { jb.MisfirePolicyProvider = func() MisfirePolicy { return policy } }
+}
+
 // OptJobBuilderOnStart is a job builder option implementation.
 func OptJobBuilderOnStart(handler func(*JobInvocation)) JobBuilderOption {
 	return func(jb *JobBuilder) { jb.OnStartHandler = handler }
@@ -103,6 +139,12 @@ type JobBuilder struct {
 	EnabledProvider                func() bool
 	ShouldTriggerListenersProvider func() bool
 	ShouldWriteOutputProvider      func() bool
+	HistoryRetentionProvider       func() HistoryRetention
+	RetryPolicyProvider            func() RetryPolicy
+	DependenciesProvider           func() []string
+	ParameterSchemaProvider        func() ParameterSchema
+	ConcurrencyPolicyProvider      func() ConcurrencyPolicy
+	MisfirePolicyProvider          func() MisfirePolicy
 
 	OnStartHandler        func(*JobInvocation)
 	OnCancellationHandler func(*JobInvocation)
@@ -163,6 +205,54 @@ func (jb *JobBuilder) ShouldTriggerListeners() bool {
 	return true
 }
 
+// HistoryRetention implements the history retention provider.
+func (jb *JobBuilder) HistoryRetention() (retention HistoryRetention) {
+	if jb.HistoryRetentionProvider != nil {
+		return jb.HistoryRetentionProvider()
+	}
+	return
+}
+
+// RetryPolicy implements the retry policy provider.
+func (jb *JobBuilder) RetryPolicy() (policy RetryPolicy) {
+	if jb.RetryPolicyProvider != nil {
+		return jb.RetryPolicyProvider()
+	}
+	return
+}
+
+// Dependencies implements the dependencies provider.
+func (jb *JobBuilder) Dependencies() []string {
+	if jb.DependenciesProvider != nil {
+		return jb.DependenciesProvider()
+	}
+	return nil
+}
+
+// ParameterSchema implements the parameter schema provider.
+func (jb *JobBuilder) ParameterSchema() ParameterSchema {
+	if jb.ParameterSchemaProvider != nil {
+		return jb.ParameterSchemaProvider()
+	}
+	return nil
+}
+
+// ConcurrencyPolicy implements the concurrency policy provider.
+func (jb *JobBuilder) ConcurrencyPolicy() (policy ConcurrencyPolicy) {
+	if jb.ConcurrencyPolicyProvider != nil {
+		return jb.ConcurrencyPolicyProvider()
+	}
+	return
+}
+
+// MisfirePolicy implements the misfire policy provider.
+func (jb *JobBuilder) MisfirePolicy() (policy MisfirePolicy) {
+	if jb.MisfirePolicyProvider != nil {
+		return jb.MisfirePolicyProvider()
+	}
+	return
+}
+
 // OnStart is a lifecycle hook.
 func (jb *JobBuilder) OnStart(ctx context.Context) {
 	if jb.OnStartHandler != nil {
@@ -0,0 +1,159 @@
+package cron
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/blend/go-sdk/ex"
+)
+
+// JobGraph is the graph of dependencies between the jobs of a job manager.
+type JobGraph struct {
+	Nodes []JobGraphNode `json:"nodes"`
+}
+
+// JobGraphNode is a job in a job graph.
+type JobGraphNode struct {
+	Name         string    `json:"name"`
+	Dependencies []string  `json:"dependencies,omitempty"`
+	Dependents   []string  `json:"dependents,omitempty"`
+	Level        int       `json:"level"`
+	Disabled     bool      `json:"disabled"`
+	Status       JobStatus `json:"status,omitempty"`
+}
+
+// Levels returns the nodes grouped by level, where each job is one level
+// after the latest of the jobs it depends on, and jobs without dependencies are level zero.
+func (jg JobGraph) Levels() (levels [][]JobGraphNode) {
+	for _, node := range jg.Nodes {
+		for len(levels) <= node.Level {
+			levels = append(levels, nil)
+		}
+		levels[node.Level] = append(levels[node.Level], node)
+	}
+	return
+}
+
+// Graph returns the job dependency graph.
+func (jm *JobManager) Graph() JobGraph {
+	jm.Lock()
+	defer jm.Unlock()
+
+	dependents := jobDependents(jm.Jobs)
+	levels := map[string]int{}
+	var graph JobGraph
+	for _, jobName := range sortedJobNames(jm.Jobs) {
+		job := jm.Jobs[jobName]
+		node := JobGraphNode{
+			Name:         jobName,
+			Dependencies: job.Dependencies,
+			Dependents:   dependents[jobName],
+			Level:        jobLevel(jm.Jobs, jobName, levels),
+			Disabled:     job.Disabled,
+		}
+		if job.Last != nil {
+			node.Status = job.Last.Status
+		}
+		graph.Nodes = append(graph.Nodes, node)
+	}
+	sort.SliceStable(graph.Nodes, func(i, j int) bool {
+		return graph.Nodes[i].Level < graph.Nodes[j].Level
+	})
+	return graph
+}
+
+//
+// internal helpers
+//
+
+// validateDependencies returns an error if a job has an empty or duplicate dependency,
+// depends on a job that is not loaded, or if the dependencies form a cycle.
+func validateDependencies(jobs map[string]*JobScheduler) error {
+	for _, jobName := range sortedJobNames(jobs) {
+		seen := map[string]bool{}
+		for _, dependency := range jobs[jobName].Dependencies {
+			if dependency == "" {
+				return ex.New(ErrJobDependencyInvalid, ex.OptMessagef("job: %s, dependency is empty", jobName))
+			}
+			if seen[dependency] {
+				return ex.New(ErrJobDependencyInvalid, ex.OptMessagef("job: %s, dependency: %s is listed more than once", jobName, dependency))
+			}
+			seen[dependency] = true
+			if _, ok := jobs[dependency]; !ok {
+				return ex.New(ErrJobNotLoaded, ex.OptMessagef("job: %s, dependency: %s", jobName, dependency))
+			}
+		}
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := map[string]int{}
+	var path []string
+	var visit func(string) error
+	visit = func(jobName string) error {
+		switch state[jobName] {
+		case visited:
+			return nil
+		case visiting:
+			for index := range path {
+				if path[index] == jobName {
+					return ex.New(ErrJobDependencyCycle, ex.OptMessagef("cycle: %s", strings.Join(append(path[index:], jobName), " -> ")))
+				}
+			}
+		}
+		state[jobName] = visiting
+		path = append(path, jobName)
+		for _, dependency := range jobs[jobName].Dependencies {
+			if err := visit(dependency); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[jobName] = visited
+		return nil
+	}
+	for _, jobName := range sortedJobNames(jobs) {
+		if err := visit(jobName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// jobDependents returns the names of the jobs that depend on each job.
+func jobDependents(jobs map[string]*JobScheduler) map[string][]string {
+	dependents := map[string][]string{}
+	for _, jobName := range sortedJobNames(jobs) {
+		for _, dependency := range jobs[jobName].Dependencies {
+			dependents[dependency] = append(dependents[dependency], jobName)
+		}
+	}
+	return dependents
+}
+
+// jobLevel returns the level of a job in a validated graph, memoized in levels.
+func jobLevel(jobs map[string]*JobScheduler, jobName string, levels map[string]int) int {
+	if level, ok := levels[jobName]; ok {
+		return level
+	}
+	var level int
+	for _, dependency := range jobs[jobName].Dependencies {
+		if dependencyLevel := jobLevel(jobs, dependency, levels) + 1; dependencyLevel > level {
+			level = dependencyLevel
+		}
+	}
+	levels[jobName] = level
+	return level
+}
+
+func sortedJobNames(jobs map[string]*JobScheduler) []string {
+	names := make([]string, 0, len(jobs))
+	for jobName := range jobs {
+		names = append(names, jobName)
+	}
+	sort.Strings(names)
+	return names
+}
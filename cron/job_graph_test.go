@@ -0,0 +1,161 @@
+package cron
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/ex"
+)
+
+func TestJobManagerLoadJobsDependencies(t *testing.T) {
+	assert := assert.New(t)
+
+	jm := New()
+	err := jm.LoadJobs(
+		NewJob("a", noop),
+		NewJob("b", noop, OptJobBuilderDependencies("a", "missing")),
+	)
+	assert.True(IsJobNotLoaded(err))
+	assert.False(jm.HasJob("a"))
+
+	err = jm.LoadJobs(
+		NewJob("a", noop, OptJobBuilderDependencies("c")),
+		NewJob("b", noop, OptJobBuilderDependencies("a")),
+		NewJob("c", noop, OptJobBuilderDependencies("b")),
+	)
+	assert.True(IsJobDependencyCycle(err))
+	assert.Contains(ex.ErrMessage(err), "a -> c -> b -> a")
+	assert.Empty(jm.Jobs)
+
+	assert.True(IsJobDependencyCycle(jm.LoadJobs(NewJob("self", noop, OptJobBuilderDependencies("self")))))
+
+	err = jm.LoadJobs(
+		NewJob("a", noop),
+		NewJob("b", noop, OptJobBuilderDependencies("a", "a")),
+	)
+	assert.True(IsJobDependencyInvalid(err))
+	assert.Empty(jm.Jobs)
+	assert.True(IsJobDependencyInvalid(jm.LoadJobs(NewJob("a", noop), NewJob("b", noop, OptJobBuilderDependencies("a", "")))))
+	assert.Empty(jm.Jobs)
+
+	// dependencies can be loaded before the jobs that depend on them.
+	assert.Nil(jm.LoadJobs(NewJob("a", noop)))
+	assert.Nil(jm.LoadJobs(NewJob("b", noop, OptJobBuilderDependencies("a"))))
+}
+
+func TestJobManagerDependencies(t *testing.T) {
+	assert := assert.New(t)
+
+	var runsLock sync.Mutex
+	var runs []string
+	record := func(name string) Action {
+		return func(_ context.Context) error {
+			runsLock.Lock()
+			defer runsLock.Unlock()
+			runs = append(runs, name)
+			return nil
+		}
+	}
+	finished := make(chan struct{}, 1)
+
+	jm := New()
+	assert.Nil(jm.LoadJobs(
+		NewJob("a", record("a")),
+		NewJob("b", record("b"), OptJobBuilderDependencies("a")),
+		NewJob("c", record("c"), OptJobBuilderDependencies("a")),
+		NewJob("d", record("d"), OptJobBuilderDependencies("b", "c"), OptJobBuilderOnComplete(func(_ *JobInvocation) {
+			finished <- struct{}{}
+		})),
+	))
+
+	// d runs once both b and c complete.
+	assert.Nil(jm.RunJobs("b"))
+	d, err := jm.Job("d")
+	assert.Nil(err)
+	assert.Nil(d.Last)
+	assert.Nil(jm.RunJobs("c"))
+	waitFinished := func() {
+		select {
+		case <-finished:
+		case <-time.After(5 * time.Second):
+			assert.FailNow("d should have run after b and c")
+		}
+	}
+	waitFinished()
+
+	// a fans out to b and c, which fan in to d.
+	assert.Nil(jm.RunJobs("a"))
+	waitFinished()
+
+	runsLock.Lock()
+	assert.Len(runs, 7)
+	assert.Equal([]string{"b", "c", "d", "a"}, runs[:4])
+	assert.Equal("d", runs[6])
+	runsLock.Unlock()
+}
+
+func TestJobManagerGraph(t *testing.T) {
+	assert := assert.New(t)
+
+	jm := New()
+	assert.Nil(jm.LoadJobs(
+		NewJob("d", noop, OptJobBuilderDependencies("b", "c")),
+		NewJob("c", noop, OptJobBuilderDependencies("a")),
+		NewJob("b", noop, OptJobBuilderDependencies("a")),
+		NewJob("a", noop),
+	))
+
+	graph := jm.Graph()
+	assert.Len(graph.Nodes, 4)
+	levels := graph.Levels()
+	assert.Len(levels, 3)
+	assert.Equal("a", levels[0][0].Name)
+	assert.Equal([]string{"b", "c"}, levels[0][0].Dependents)
+	assert.Len(levels[1], 2)
+	assert.Equal("d", levels[2][0].Name)
+	assert.Equal([]string{"b", "c"}, levels[2][0].Dependencies)
+}
+
+func TestJobManagerDependenciesUpstreamFailed(t *testing.T) {
+	assert := assert.New(t)
+
+	var ran bool
+	jm := New()
+	assert.Nil(jm.LoadJobs(
+		NewJob("a", func(_ context.Context) error { return ex.New("failure") }),
+		NewJob("b", noop, OptJobBuilderDependencies("a")),
+		NewJob("c", noop, OptJobBuilderDependencies("a")),
+		NewJob("d", func(_ context.Context) error { ran = true; return nil }, OptJobBuilderDependencies("b", "c")),
+	))
+	assert.Nil(jm.RunJobs("a"))
+
+	for _, jobName := range []string{"b", "c", "d"} {
+		js, err := jm.Job(jobName)
+		assert.Nil(err)
+		assert.Len(js.History, 1, jobName)
+		assert.Equal(JobStatusCancelled, js.Last.Status)
+		assert.True(IsUpstreamFailed(js.Last.Err), jobName)
+	}
+	assert.False(ran)
+}
+
+func TestJobManagerDependenciesUpstreamCancelled(t *testing.T) {
+	assert := assert.New(t)
+
+	jm := New()
+	assert.Nil(jm.LoadJobs(
+		NewJob("a", func(_ context.Context) error { return ErrJobCancelled }),
+		NewJob("b", noop, OptJobBuilderDependencies("a")),
+		NewJob("c", noop, OptJobBuilderDependencies("b")),
+	))
+	assert.Nil(jm.RunJobs("a"))
+
+	for _, jobName := range []string{"b", "c"} {
+		js, err := jm.Job(jobName)
+		assert.Nil(err)
+		assert.True(IsUpstreamCancelled(js.Last.Err), jobName)
+	}
+}
@@ -12,21 +12,59 @@ func NewJobInvocation(jobName string) *JobInvocation {
 		Started: Now(),
 		Status:  JobStatusRunning,
 		JobName: jobName,
+		Attempt: 1,
+		done:    make(chan struct{}),
 	}
 }
 
+// NewJobInvocationRetry returns a new job invocation that retries a failed invocation.
+func NewJobInvocationRetry(previous *JobInvocation) *JobInvocation {
+	ji := NewJobInvocation(previous.JobName)
+	ji.Attempt = previous.Attempt + 1
+	ji.RetryOf = previous.ID
+	ji.Parameters = previous.Parameters
+	return ji
+}
+
 // JobInvocation is metadata for a job invocation (or instance of a job running).
+//
+// Attempt counts the attempts of a scheduled run, starting at one, and RetryOf is the id
+// of the failed invocation a retry re-invokes; see `RetryPolicy`. Parameters are the values
+// the invocation was invoked with, validated against the job's `ParameterSchema`.
 type JobInvocation struct {
-	ID        string             `json:"id"`
-	JobName   string             `json:"jobName"`
-	Started   time.Time          `json:"started"`
-	Finished  time.Time          `json:"finished,omitempty"`
-	Cancelled time.Time          `json:"cancelled,omitempty"`
-	Timeout   time.Time          `json:"timeout,omitempty"`
-	Err       error              `json:"err,omitempty"`
-	Elapsed   time.Duration      `json:"elapsed"`
-	Status    JobStatus          `json:"status"`
-	State     interface{}        `json:"state,omitempty"`
-	Context   context.Context    `json:"-"`
-	Cancel    context.CancelFunc `json:"-"`
+	ID         string             `json:"id"`
+	JobName    string             `json:"jobName"`
+	Started    time.Time          `json:"started"`
+	Finished   time.Time          `json:"finished,omitempty"`
+	Cancelled  time.Time          `json:"cancelled,omitempty"`
+	Timeout    time.Time          `json:"timeout,omitempty"`
+	Err        error              `json:"err,omitempty"`
+	Elapsed    time.Duration      `json:"elapsed"`
+	Status     JobStatus          `json:"status"`
+	State      interface{}        `json:"state,omitempty"`
+	Attempt    int                `json:"attempt,omitempty"`
+	RetryOf    string             `json:"retryOf,omitempty"`
+	Parameters ParameterValues    `json:"parameters,omitempty"`
+	Context    context.Context    `json:"-"`
+	Cancel     context.CancelFunc `json:"-"`
+
+	// done is closed when the invocation finishes.
+	done chan struct{}
 }
+
+// Done returns a channel that is closed when the invocation's run finishes.
+//
+// Invocations not created with `NewJobInvocation`, e.g. invocations restored from a history store, are finished.
+func (ji *JobInvocation) Done() <-chan struct{} {
+	if ji.done == nil {
+		return closedDone
+	}
+	return ji.done
+}
+
+// closedDone is the done channel of finished invocations.
+var closedDone = func() chan struct{} {
+	c := make(chan struct{})
+	close(c)
+	return c
+}()
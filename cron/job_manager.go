@@ -3,6 +3,7 @@ package cron
 // NOTE: ALL TIMES ARE IN UTC. JUST USE UTC.
 
 import (
+	"context"
 	"fmt"
 	"sort"
 	"sync"
@@ -29,10 +30,19 @@ type JobManager struct {
 	sync.Mutex
 	*async.Latch
 
-	Config Config
-	Tracer Tracer
-	Log    logger.Log
-	Jobs   map[string]*JobScheduler
+	Config       Config
+	Tracer       Tracer
+	Log          logger.Log
+	HistoryStore HistoryStore
+	Locker       Locker
+	Jobs         map[string]*JobScheduler
+
+	// dependencyLock guards the dependency state, which is updated as jobs finish,
+	// separately from the job manager, which is held while `RunJobs` runs jobs.
+	dependencyLock sync.Mutex
+	dependents     map[string][]*JobScheduler
+	satisfied      map[string]map[string]bool
+	skipped        map[string]bool
 }
 
 // --------------------------------------------------------------------------------
@@ -40,21 +50,41 @@ type JobManager struct {
 // --------------------------------------------------------------------------------
 
 // LoadJobs loads a variadic list of jobs.
+// If the job manager has a history store, the history for each job is loaded from it.
+// Job dependencies are validated against the jobs already loaded and the given jobs,
+// and if any job depends on a job that is not loaded, or the dependencies form a cycle,
+// none of the given jobs are loaded.
 func (jm *JobManager) LoadJobs(jobs ...Job) error {
 	jm.Lock()
 	defer jm.Unlock()
 
+	loaded := map[string]*JobScheduler{}
+	for jobName, js := range jm.Jobs {
+		loaded[jobName] = js
+	}
 	for _, job := range jobs {
 		jobName := job.Name()
-		if _, hasJob := jm.Jobs[jobName]; hasJob {
+		if _, hasJob := loaded[jobName]; hasJob {
 			return ex.New(ErrJobAlreadyLoaded, ex.OptMessagef("job: %s", job.Name()))
 		}
-		jm.Jobs[jobName] = NewJobScheduler(job,
+		js := NewJobScheduler(job,
 			OptJobSchedulerTracer(jm.Tracer),
 			OptJobSchedulerLog(jm.Log),
 			OptJobSchedulerConfig(jm.Config),
+			OptJobSchedulerHistoryStore(jm.HistoryStore),
+			OptJobSchedulerLocker(jm.Locker),
 		)
+		if err := js.LoadHistory(context.Background()); err != nil {
+			return ex.New(err, ex.OptMessagef("job: %s", jobName))
+		}
+		js.onFinished = jm.onJobFinished
+		loaded[jobName] = js
+	}
+	if err := validateDependencies(loaded); err != nil {
+		return err
 	}
+	jm.Jobs = loaded
+	jm.loadDependents()
 	return nil
 }
 
@@ -161,6 +191,24 @@ func (jm *JobManager) RunJob(jobName string) error {
 	return nil
 }
 
+// RunJobWithParameters runs a job by jobName on demand with parameter values.
+// It returns an error of class `ErrParametersInvalid` if the values do not match the job's parameter schema.
+func (jm *JobManager) RunJobWithParameters(jobName string, values ParameterValues) error {
+	jm.Lock()
+	defer jm.Unlock()
+
+	job, ok := jm.Jobs[jobName]
+	if !ok {
+		return ex.New(ErrJobNotLoaded, ex.OptMessagef("job: %s", jobName))
+	}
+	values, err := job.ParameterSchema.Validate(values)
+	if err != nil {
+		return err
+	}
+	go job.run(values)
+	return nil
+}
+
 // RunAllJobs runs every job that has been loaded in the JobManager at once.
 func (jm *JobManager) RunAllJobs() {
 	jm.Lock()
@@ -248,3 +296,63 @@ func (jm *JobManager) Stop() error {
 	jm.Stopped()
 	return nil
 }
+
+//
+// Dependencies
+//
+
+// loadDependents indexes the jobs that depend on each job.
+// It must be called while holding the job manager lock.
+func (jm *JobManager) loadDependents() {
+	jm.dependencyLock.Lock()
+	defer jm.dependencyLock.Unlock()
+
+	jm.dependents = map[string][]*JobScheduler{}
+	for jobName, dependents := range jobDependents(jm.Jobs) {
+		for _, dependent := range dependents {
+			jm.dependents[jobName] = append(jm.dependents[jobName], jm.Jobs[dependent])
+		}
+	}
+}
+
+// onJobFinished runs the jobs that depend on a job once all their dependencies have completed,
+// and skips them if the job failed or was cancelled. A job that depends on several jobs that fail
+// is skipped once, until one of them completes again.
+func (jm *JobManager) onJobFinished(ji *JobInvocation) {
+	var run, skip []*JobScheduler
+	func() {
+		jm.dependencyLock.Lock()
+		defer jm.dependencyLock.Unlock()
+
+		if jm.satisfied == nil {
+			jm.satisfied = map[string]map[string]bool{}
+			jm.skipped = map[string]bool{}
+		}
+		for _, dependent := range jm.dependents[ji.JobName] {
+			if ji.Status != JobStatusComplete {
+				delete(jm.satisfied, dependent.Name)
+				if !jm.skipped[dependent.Name] {
+					jm.skipped[dependent.Name] = true
+					skip = append(skip, dependent)
+				}
+				continue
+			}
+			delete(jm.skipped, dependent.Name)
+			if jm.satisfied[dependent.Name] == nil {
+				jm.satisfied[dependent.Name] = map[string]bool{}
+			}
+			jm.satisfied[dependent.Name][ji.JobName] = true
+			if len(jm.satisfied[dependent.Name]) == len(dependent.Dependencies) {
+				delete(jm.satisfied, dependent.Name)
+				run = append(run, dependent)
+			}
+		}
+	}()
+
+	for _, dependent := range run {
+		go dependent.Run()
+	}
+	for _, dependent := range skip {
+		dependent.skip(ji)
+	}
+}
@@ -19,3 +19,14 @@ func OptLog(log logger.Log) JobManagerOption {
 func OptTracer(tracer Tracer) JobManagerOption {
 	return func(jm *JobManager) { jm.Tracer = tracer }
 }
+
+// OptHistoryStore sets the job manager history store, which persists job history across restarts.
+func OptHistoryStore(store HistoryStore) JobManagerOption {
+	return func(jm *JobManager) { jm.HistoryStore = store }
+}
+
+// OptLocker sets the job manager locker, which jobs acquire a lease from before they run
+// so they run on one replica at a time.
+func OptLocker(locker Locker) JobManagerOption {
+	return func(jm *JobManager) { jm.Locker = locker }
+}
@@ -2,6 +2,7 @@ package cron
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"sync"
 	"time"
@@ -27,6 +28,14 @@ func NewJobScheduler(job Job, options ...JobSchedulerOption) *JobScheduler {
 		js.Schedule = typed.Schedule()
 	}
 
+	if typed, ok := job.(DependenciesProvider); ok {
+		js.Dependencies = typed.Dependencies()
+	}
+
+	if typed, ok := job.(ParameterSchemaProvider); ok {
+		js.ParameterSchema = typed.ParameterSchema()
+	}
+
 	if typed, ok := job.(TimeoutProvider); ok {
 		js.TimeoutProvider = typed.Timeout
 	} else {
@@ -45,6 +54,18 @@ func NewJobScheduler(job Job, options ...JobSchedulerOption) *JobScheduler {
 		js.SerialProvider = func() bool { return DefaultSerial }
 	}
 
+	if typed, ok := job.(ConcurrencyPolicyProvider); ok {
+		js.ConcurrencyPolicyProvider = typed.ConcurrencyPolicy
+	} else {
+		js.ConcurrencyPolicyProvider = func() ConcurrencyPolicy { return "" }
+	}
+
+	if typed, ok := job.(MisfirePolicyProvider); ok {
+		js.MisfirePolicyProvider = typed.MisfirePolicy
+	} else {
+		js.MisfirePolicyProvider = func() MisfirePolicy { return MisfirePolicy{} }
+	}
+
 	if typed, ok := job.(ShouldTriggerListenersProvider); ok {
 		js.ShouldTriggerListenersProvider = typed.ShouldTriggerListeners
 	} else {
@@ -57,6 +78,24 @@ func NewJobScheduler(job Job, options ...JobSchedulerOption) *JobScheduler {
 		js.ShouldWriteOutputProvider = func() bool { return DefaultShouldWriteOutput }
 	}
 
+	if typed, ok := job.(HistoryRetentionProvider); ok {
+		js.HistoryRetentionProvider = typed.HistoryRetention
+	} else {
+		js.HistoryRetentionProvider = func() HistoryRetention { return HistoryRetention{} }
+	}
+
+	if typed, ok := job.(RetryPolicyProvider); ok {
+		js.RetryPolicyProvider = typed.RetryPolicy
+	} else {
+		js.RetryPolicyProvider = func() RetryPolicy { return RetryPolicy{} }
+	}
+
+	if typed, ok := job.(LockerProvider); ok {
+		js.LockerProvider = typed.Locker
+	} else {
+		js.LockerProvider = func() Locker { return js.Locker }
+	}
+
 	for _, option := range options {
 		option(js)
 	}
@@ -69,13 +108,17 @@ type JobScheduler struct {
 	sync.Mutex   `json:"-"`
 	*async.Latch `json:"-"`
 
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	Job         Job    `json:"-"`
+	Name            string          `json:"name"`
+	Description     string          `json:"description"`
+	Dependencies    []string        `json:"dependencies,omitempty"`
+	ParameterSchema ParameterSchema `json:"parameterSchema,omitempty"`
+	Job             Job             `json:"-"`
 
-	Config Config     `json:"-"`
-	Tracer Tracer     `json:"-"`
-	Log    logger.Log `json:"-"`
+	Config       Config       `json:"-"`
+	Tracer       Tracer       `json:"-"`
+	Log          logger.Log   `json:"-"`
+	HistoryStore HistoryStore `json:"-"`
+	Locker       Locker       `json:"-"`
 
 	// Meta Fields
 	Disabled    bool            `json:"disabled"`
@@ -84,12 +127,24 @@ type JobScheduler struct {
 	Last        *JobInvocation  `json:"last"`
 	History     []JobInvocation `json:"history"`
 
-	Schedule                       Schedule             `json:"-"`
-	EnabledProvider                func() bool          `json:"-"`
-	SerialProvider                 func() bool          `json:"-"`
-	TimeoutProvider                func() time.Duration `json:"-"`
-	ShouldTriggerListenersProvider func() bool          `json:"-"`
-	ShouldWriteOutputProvider      func() bool          `json:"-"`
+	Schedule                       Schedule                 `json:"-"`
+	EnabledProvider                func() bool              `json:"-"`
+	SerialProvider                 func() bool              `json:"-"`
+	ConcurrencyPolicyProvider      func() ConcurrencyPolicy `json:"-"`
+	MisfirePolicyProvider          func() MisfirePolicy     `json:"-"`
+	TimeoutProvider                func() time.Duration     `json:"-"`
+	ShouldTriggerListenersProvider func() bool              `json:"-"`
+	ShouldWriteOutputProvider      func() bool              `json:"-"`
+	HistoryRetentionProvider       func() HistoryRetention  `json:"-"`
+	RetryPolicyProvider            func() RetryPolicy       `json:"-"`
+	LockerProvider                 func() Locker            `json:"-"`
+
+	// onFinished is called with the final invocation of each run, and is set by the job manager.
+	onFinished func(*JobInvocation)
+	// historyCulled is when the history store was last culled.
+	historyCulled time.Time
+	// lastRun is the final invocation of the most recent run, which broken and fixed transitions compare against.
+	lastRun *JobInvocation
 }
 
 // Start starts the scheduler.
@@ -170,6 +225,8 @@ func (js *JobScheduler) RunLoop() {
 	}()
 
 	if js.Schedule != nil {
+		// catch up on missed runs before the schedule resumes, so they do not race the first scheduled run.
+		js.runMisfires()
 		js.NextRuntime = js.Schedule.Next(js.NextRuntime)
 	}
 	if js.NextRuntime.IsZero() {
@@ -201,18 +258,355 @@ func (js *JobScheduler) RunLoop() {
 // Run forces the job to run.
 // It checks if the job should be allowed to execute.
 // It blocks on the job execution to enforce or clear timeouts.
+//
+// If the job has a locker, it acquires a lease on the job name first, and does not run
+// if the lease is held elsewhere (e.g. by another replica). The lease is renewed while
+// the job runs, and the job is cancelled if the lease is lost.
+//
+// If the job has a retry policy, failed invocations are re-invoked after a backoff until an
+// attempt succeeds or the policy is exhausted, and the lease is held across the attempts.
+//
+// If the job has a `ConcurrencyPolicyReplace` concurrency policy, the current invocation
+// is cancelled, and Run waits for it to finish before it starts a new invocation.
+//
+// If the job has a parameter schema, it is invoked with the parameter defaults, and does not
+// run if a required parameter has no default.
 func (js *JobScheduler) Run() {
+	values, err := js.ParameterSchema.Validate(nil)
+	if err != nil {
+		logger.MaybeError(js.Log, err)
+		return
+	}
+	js.run(values)
+}
+
+// RunWithParameters forces the job to run with parameter values, like Run.
+// It returns an error of class `ErrParametersInvalid`, without running the job,
+// if the values do not match the job's parameter schema.
+func (js *JobScheduler) RunWithParameters(values ParameterValues) error {
+	values, err := js.ParameterSchema.Validate(values)
+	if err != nil {
+		return err
+	}
+	js.run(values)
+	return nil
+}
+
+// run runs the job with validated parameter values.
+func (js *JobScheduler) run(values ParameterValues) {
 	// check if the job can run
 	if !js.enabled() {
 		return
 	}
-
-	timeout := js.TimeoutProvider()
+	if js.ConcurrencyPolicy() == ConcurrencyPolicyReplace {
+		js.cancelCurrent()
+	}
 
 	// create a job invocation, or a record of each
 	// individual execution of a job.
 	ji := NewJobInvocation(js.Name)
-	ji.Context, ji.Cancel = js.createContextWithTimeout(timeout)
+	ji.Parameters = values
+
+	// acquire a lease for the invocation if the job is locked.
+	var locker Locker
+	if js.LockerProvider != nil {
+		locker = js.LockerProvider()
+	}
+	var lease *Lease
+	var acquired time.Time
+	if locker != nil {
+		var err error
+		acquired = time.Now()
+		lease, err = locker.Acquire(context.Background(), js.Name, ji.ID, js.Config.LockTTLOrDefault())
+		if err != nil {
+			logger.MaybeError(js.Log, err)
+			return
+		}
+		if lease == nil {
+			return
+		}
+	}
+
+	// signal the run has finished, after the lease is released, to runs waiting to replace it.
+	defer func() {
+		close(ji.done)
+	}()
+
+	// the run context spans all the attempts of the run.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var leaseLost <-chan struct{}
+	if lease != nil {
+		var leaseRenewed <-chan struct{}
+		ctx = WithLease(ctx, lease)
+		leaseLost, leaseRenewed = js.renewLease(ctx, cancel, locker, lease, acquired)
+		started := ji.Started
+		defer func() {
+			cancel()
+			<-leaseRenewed
+			// cancelled runs release the lease immediately so a replacing run can acquire it.
+			elapsed := Since(started)
+			if ji.Status == JobStatusCancelled {
+				elapsed = js.Config.LockMinHoldOrDefault()
+			}
+			js.releaseLease(locker, lease, elapsed, isClosed(leaseLost))
+		}()
+	}
+
+	// notify the job manager of the final attempt, which triggers dependent jobs.
+	defer func() {
+		if js.onFinished != nil {
+			js.onFinished(ji)
+		}
+	}()
+
+	policy := js.RetryPolicy()
+	for js.invoke(ctx, cancel, ji, policy, leaseLost) {
+		// the run stays current through the backoff, so it is not overlapped and cancelling it stops the retries.
+		select {
+		case <-ctx.Done():
+		case <-time.After(policy.BackoffFor(ji.Attempt)):
+		}
+		if ctx.Err() != nil || js.disabled() {
+			js.finishRun(ji, false)
+			js.setCurrent(nil)
+			return
+		}
+		close(ji.done)
+		ji = NewJobInvocationRetry(ji)
+	}
+}
+
+//
+// exported utility methods
+//
+
+// GetInvocationByID returns an invocation by id.
+// If it is not in the in-memory history, it is fetched from the history store if one is set.
+func (js *JobScheduler) GetInvocationByID(id string) *JobInvocation {
+	for _, ji := range js.History {
+		if ji.ID == id {
+			return &ji
+		}
+	}
+	if js.HistoryStore != nil {
+		ji, err := js.HistoryStore.Get(context.Background(), js.Name, id)
+		if err != nil {
+			logger.MaybeError(js.Log, err)
+			return nil
+		}
+		if ji != nil {
+			js.restoreState(ji)
+		}
+		return ji
+	}
+	return nil
+}
+
+// LoadHistory loads the in-memory history from the history store, if one is set.
+func (js *JobScheduler) LoadHistory(ctx context.Context) error {
+	if js.HistoryStore == nil {
+		return nil
+	}
+	retention := js.HistoryRetention()
+	history, err := js.HistoryStore.List(ctx, js.Name, 0, retention.MaxCount)
+	if err != nil {
+		return err
+	}
+	// the store lists the most recent invocations first, the in-memory history is chronological.
+	js.History = nil
+	now := Now()
+	for index := len(history) - 1; index >= 0; index-- {
+		ji := history[index]
+		if retention.Keep(len(history)-1-index, len(history), ji.Started, now) {
+			js.restoreState(&ji)
+			js.History = append(js.History, ji)
+		}
+	}
+	// restore the last invocation so broken and fixed transitions carry across restarts.
+	if len(js.History) > 0 {
+		last := js.History[len(js.History)-1]
+		js.Last = &last
+		js.lastRun = &last
+	}
+	return nil
+}
+
+// ListHistory returns a page of the job history, most recent first.
+// It reads from the history store if one is set, and the in-memory history otherwise.
+func (js *JobScheduler) ListHistory(ctx context.Context, offset, limit int) ([]JobInvocation, error) {
+	if js.HistoryStore != nil {
+		history, err := js.HistoryStore.List(ctx, js.Name, offset, limit)
+		if err != nil {
+			return nil, err
+		}
+		for index := range history {
+			js.restoreState(&history[index])
+		}
+		return history, nil
+	}
+	history := make([]JobInvocation, len(js.History))
+	for index, ji := range js.History {
+		history[len(js.History)-1-index] = ji
+	}
+	return HistoryPage(history, offset, limit), nil
+}
+
+// CountHistory returns the number of invocations in the job history.
+func (js *JobScheduler) CountHistory(ctx context.Context) (int, error) {
+	if js.HistoryStore != nil {
+		return js.HistoryStore.Count(ctx, js.Name)
+	}
+	return len(js.History), nil
+}
+
+// ConcurrencyPolicy returns the concurrency policy for the job.
+// If the job does not provide one, it is `ConcurrencyPolicyForbid` for serial jobs,
+// and `ConcurrencyPolicyAllow` otherwise.
+func (js *JobScheduler) ConcurrencyPolicy() ConcurrencyPolicy {
+	if js.ConcurrencyPolicyProvider != nil {
+		if policy := js.ConcurrencyPolicyProvider(); policy != "" {
+			return policy
+		}
+	}
+	if js.SerialProvider != nil && js.SerialProvider() {
+		return ConcurrencyPolicyForbid
+	}
+	return ConcurrencyPolicyAllow
+}
+
+// MisfirePolicy returns the misfire policy for the job.
+func (js *JobScheduler) MisfirePolicy() (policy MisfirePolicy) {
+	if js.MisfirePolicyProvider != nil {
+		policy = js.MisfirePolicyProvider()
+	}
+	return
+}
+
+// RetryPolicy returns the retry policy for the job.
+func (js *JobScheduler) RetryPolicy() (policy RetryPolicy) {
+	if js.RetryPolicyProvider != nil {
+		policy = js.RetryPolicyProvider()
+	}
+	return
+}
+
+// HistoryRetention returns the history retention policy for the job.
+// Values the job does not provide, i.e. zero values, fall back to the config,
+// and negative values are left as is, i.e. unlimited.
+func (js *JobScheduler) HistoryRetention() HistoryRetention {
+	var retention HistoryRetention
+	if js.HistoryRetentionProvider != nil {
+		retention = js.HistoryRetentionProvider()
+	}
+	if retention.MaxCount == 0 {
+		retention.MaxCount = js.Config.HistoryMaxCountOrDefault()
+	}
+	if retention.MaxAge == 0 {
+		retention.MaxAge = js.Config.HistoryMaxAgeOrDefault()
+	}
+	return retention
+}
+
+//
+// utility functions
+//
+
+func (js *JobScheduler) setCurrent(ji *JobInvocation) {
+	js.Current = ji
+}
+
+func (js *JobScheduler) setLast(ji *JobInvocation) {
+	js.Last = ji
+}
+
+// finishRun returns the final invocation of the previous run, and records the invocation as
+// the final invocation of its run if it will not be retried.
+func (js *JobScheduler) finishRun(ji *JobInvocation, retrying bool) (last *JobInvocation) {
+	js.Lock()
+	defer js.Unlock()
+	last = js.lastRun
+	if !retrying {
+		js.lastRun = ji
+	}
+	return
+}
+
+// runMisfires runs the scheduled runs missed since the last invocation started, according to the misfire policy,
+// one after the other. It returns early if the job scheduler is stopped.
+func (js *JobScheduler) runMisfires() {
+	if js.Last == nil {
+		return
+	}
+	missed := js.MisfirePolicy().Missed(js.Schedule, js.Last.Started, Now())
+	if len(missed) == 0 {
+		return
+	}
+	logger.MaybeInfof(js.Log, "job: %s, running %d missed run(s) since %s", js.Name, len(missed), FormatTime(js.Last.Started))
+	notifyStopping := js.NotifyStopping()
+	for range missed {
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			js.Run()
+		}()
+		// stopping the scheduler does not wait on the run, like scheduled runs.
+		select {
+		case <-done:
+		case <-notifyStopping:
+			return
+		}
+	}
+}
+
+// cancelCurrent cancels the current invocation, and waits for its run to finish.
+func (js *JobScheduler) cancelCurrent() {
+	current := js.Current
+	if current == nil {
+		return
+	}
+	current.Cancel()
+	if current.done != nil {
+		<-current.done
+	}
+}
+
+// skip records an invocation that did not run because a job it depends on failed or was cancelled.
+func (js *JobScheduler) skip(upstream *JobInvocation) {
+	class := ErrUpstreamFailed
+	if upstream.Status == JobStatusCancelled && !IsUpstreamFailed(upstream.Err) {
+		class = ErrUpstreamCancelled
+	}
+
+	ji := NewJobInvocation(js.Name)
+	ji.Context, ji.Cancel = context.WithCancel(context.Background())
+	ji.Cancel()
+	ji.Context = WithJobInvocation(ji.Context, ji)
+	ji.Finished = ji.Started
+	ji.Cancelled = ji.Started
+	ji.Err = ex.New(class, ex.OptMessagef("job: %s, upstream: %s, upstream invocation: %s", js.Name, upstream.JobName, upstream.ID))
+	js.onCancelled(ji.Context, ji)
+	js.finishRun(ji, false)
+	js.addHistory(*ji)
+	js.setLast(ji)
+	close(ji.done)
+
+	if js.onFinished != nil {
+		js.onFinished(ji)
+	}
+}
+
+// invoke runs an attempt of the job within a run context, and returns if the attempt failed and should be retried.
+// Cancelling the invocation cancels the run, including any retries.
+func (js *JobScheduler) invoke(ctx context.Context, cancelRun context.CancelFunc, ji *JobInvocation, policy RetryPolicy, leaseLost <-chan struct{}) (retry bool) {
+	timeout := js.TimeoutProvider()
+	var cancel context.CancelFunc
+	ji.Context, cancel = js.createContextWithTimeout(ctx, timeout)
+	ji.Cancel = func() {
+		cancel()
+		cancelRun()
+	}
 
 	if timeout > 0 {
 		ji.Timeout = ji.Started.Add(timeout)
@@ -228,13 +622,13 @@ func (js *JobScheduler) Run() {
 	// this defer runs all cleanup actions
 	// it recovers panics
 	// it cancels the timeout (if relevant)
-	// it rotates the current and last references
-	// it fires lifecycle events
+	// it rotates the current and last references, keeping the run current if it will be retried
+	// it fires lifecycle events, comparing against the final invocation of the previous run
 	defer func() {
 		if r := recover(); r != nil {
 			err = ex.New(err)
 		}
-		ji.Cancel()
+		cancel()
 		if tf != nil {
 			tf.Finish(ji.Context)
 		}
@@ -245,15 +639,19 @@ func (js *JobScheduler) Run() {
 
 		if err != nil && IsJobCancelled(err) {
 			ji.Cancelled = ji.Finished
+			js.finishRun(ji, false)
 			js.onCancelled(ji.Context, ji)
 		} else if ji.Err != nil {
-			js.onFailure(ji.Context, ji)
+			retry = policy.ShouldRetry(ji.Attempt, ji.Err)
+			js.onFailure(ji.Context, ji, js.finishRun(ji, retry), retry)
 		} else {
-			js.onComplete(ji.Context, ji)
+			js.onComplete(ji.Context, ji, js.finishRun(ji, false))
 		}
 
 		js.addHistory(*ji)
-		js.setCurrent(nil)
+		if !retry {
+			js.setCurrent(nil)
+		}
 		js.setLast(ji)
 	}()
 
@@ -268,35 +666,14 @@ func (js *JobScheduler) Run() {
 	// or if it's finished.
 	select {
 	case <-ji.Context.Done():
-		err = ErrJobCancelled
-	case err = <-js.safeAsyncExec(ji.Context):
-	}
-}
-
-//
-// exported utility methods
-//
-
-// GetInvocationByID returns an invocation by id.
-func (js *JobScheduler) GetInvocationByID(id string) *JobInvocation {
-	for _, ji := range js.History {
-		if ji.ID == id {
-			return &ji
+		if isClosed(leaseLost) {
+			err = ex.New(ErrLeaseLost, ex.OptMessagef("job: %s", js.Name))
+		} else {
+			err = ErrJobCancelled
 		}
+	case err = <-js.safeAsyncExec(ji.Context):
 	}
-	return nil
-}
-
-//
-// utility functions
-//
-
-func (js *JobScheduler) setCurrent(ji *JobInvocation) {
-	js.Current = ji
-}
-
-func (js *JobScheduler) setLast(ji *JobInvocation) {
-	js.Last = ji
+	return
 }
 
 // safeAsyncExec runs a given job's body and recovers panics.
@@ -313,26 +690,73 @@ func (js *JobScheduler) safeAsyncExec(ctx context.Context) chan error {
 	return errors
 }
 
-func (js *JobScheduler) createContextWithTimeout(timeout time.Duration) (context.Context, context.CancelFunc) {
+// renewLease renews a lease acquired at a given local time in the background until the invocation context is done.
+// If the lease is lost, the lost channel is closed and the invocation is cancelled.
+// The renewed channel is closed when renewal stops.
+//
+// The lease expiry is set by the locker's clock, so the local deadline the lease is
+// assumed lost after is tracked from when each successful acquire or renew was sent.
+func (js *JobScheduler) renewLease(ctx context.Context, cancel context.CancelFunc, locker Locker, lease *Lease, acquired time.Time) (lost, renewed <-chan struct{}) {
+	lostSignal := make(chan struct{})
+	renewedSignal := make(chan struct{})
+	ttl := js.Config.LockTTLOrDefault()
+	go func() {
+		defer close(renewedSignal)
+		ticker := time.NewTicker(ttl / 3)
+		defer ticker.Stop()
+		deadline := acquired.Add(ttl)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				sent := time.Now()
+				err := locker.Renew(context.Background(), lease, ttl)
+				if err == nil {
+					deadline = sent.Add(ttl)
+					continue
+				}
+				// if the lease could not be renewed before it expired, assume another holder has it.
+				if IsLeaseLost(err) || time.Now().After(deadline) {
+					close(lostSignal)
+					cancel()
+					return
+				}
+				logger.MaybeError(js.Log, err)
+			}
+		}
+	}()
+	return lostSignal, renewedSignal
+}
+
+// releaseLease releases a lease after an invocation finishes.
+// If the invocation finished in less than the minimum hold, the lease is held for the remainder
+// instead, so replicas with clocks that are slightly behind do not run the same scheduled invocation.
+func (js *JobScheduler) releaseLease(locker Locker, lease *Lease, elapsed time.Duration, lost bool) {
+	if lost {
+		return
+	}
+	if remaining := js.Config.LockMinHoldOrDefault() - elapsed; remaining > 0 {
+		logger.MaybeError(js.Log, locker.Renew(context.Background(), lease, remaining))
+		return
+	}
+	logger.MaybeError(js.Log, locker.Release(context.Background(), lease))
+}
+
+func (js *JobScheduler) createContextWithTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
 	if timeout > 0 {
-		return context.WithTimeout(context.Background(), timeout)
+		return context.WithTimeout(ctx, timeout)
 	}
-	return context.WithCancel(context.Background())
+	return context.WithCancel(ctx)
 }
 
 // enabled returns if a job can execute.
 func (js *JobScheduler) enabled() bool {
-	if js.Disabled {
+	if js.disabled() {
 		return false
 	}
 
-	if js.EnabledProvider != nil {
-		if !js.EnabledProvider() {
-			return false
-		}
-	}
-
-	if js.SerialProvider != nil && js.SerialProvider() {
+	if js.ConcurrencyPolicy() == ConcurrencyPolicyForbid {
 		if js.Current != nil {
 			return false
 		}
@@ -340,6 +764,17 @@ func (js *JobScheduler) enabled() bool {
 	return true
 }
 
+// disabled returns if the job is disabled, regardless of whether it is running.
+func (js *JobScheduler) disabled() bool {
+	if js.Disabled {
+		return true
+	}
+	if js.EnabledProvider != nil && !js.EnabledProvider() {
+		return true
+	}
+	return false
+}
+
 func (js *JobScheduler) onStart(ctx context.Context, ji *JobInvocation) {
 	if js.Log != nil && js.ShouldTriggerListenersProvider() {
 		event := NewEvent(FlagStarted, ji.JobName, OptEventJobInvocation(ji.ID), OptEventWritable(js.ShouldWriteOutputProvider()))
@@ -362,7 +797,7 @@ func (js *JobScheduler) onCancelled(ctx context.Context, ji *JobInvocation) {
 	}
 }
 
-func (js *JobScheduler) onComplete(ctx context.Context, ji *JobInvocation) {
+func (js *JobScheduler) onComplete(ctx context.Context, ji *JobInvocation, last *JobInvocation) {
 	ji.Status = JobStatusComplete
 
 	if js.Log != nil && js.ShouldTriggerListenersProvider() {
@@ -373,7 +808,7 @@ func (js *JobScheduler) onComplete(ctx context.Context, ji *JobInvocation) {
 		typed.OnComplete(ctx)
 	}
 
-	if js.Last != nil && js.Last.Err != nil {
+	if last != nil && last.Err != nil {
 		if js.Log != nil {
 			event := NewEvent(FlagFixed, ji.JobName, OptEventElapsed(ji.Elapsed), OptEventWritable(js.ShouldWriteOutputProvider()))
 			js.Log.Trigger(ctx, event)
@@ -385,7 +820,8 @@ func (js *JobScheduler) onComplete(ctx context.Context, ji *JobInvocation) {
 	}
 }
 
-func (js *JobScheduler) onFailure(ctx context.Context, ji *JobInvocation) {
+// onFailure fires failure events, and broken events once an invocation will not be retried.
+func (js *JobScheduler) onFailure(ctx context.Context, ji *JobInvocation, last *JobInvocation, retrying bool) {
 	ji.Status = JobStatusFailed
 
 	if js.Log != nil && js.ShouldTriggerListenersProvider() {
@@ -399,7 +835,7 @@ func (js *JobScheduler) onFailure(ctx context.Context, ji *JobInvocation) {
 	if typed, ok := js.Job.(OnFailureReceiver); ok {
 		typed.OnFailure(ctx)
 	}
-	if js.Last != nil && js.Last.Err == nil {
+	if !retrying && last != nil && last.Err == nil {
 		if js.Log != nil {
 			event := NewEvent(FlagBroken, ji.JobName, OptEventJobInvocation(ji.ID), OptEventElapsed(ji.Elapsed), OptEventWritable(js.ShouldWriteOutputProvider()))
 			js.Log.Trigger(ctx, event)
@@ -412,27 +848,54 @@ func (js *JobScheduler) onFailure(ctx context.Context, ji *JobInvocation) {
 }
 
 func (js *JobScheduler) addHistory(ji JobInvocation) {
-	js.History = append(js.cullHistory(), ji)
+	history := js.cullHistory()
+	culled := len(history) < len(js.History)
+	js.History = append(history, ji)
+	if js.HistoryStore != nil {
+		ctx := context.Background()
+		if err := js.HistoryStore.Add(ctx, ji); err != nil {
+			logger.MaybeError(js.Log, err)
+			return
+		}
+		// the in-memory history mirrors the store, so the store is only culled once the in-memory
+		// history exceeds the retention policy, and at most once per cull interval.
+		now := Now()
+		retention := js.HistoryRetention()
+		if (culled || (retention.MaxCount > 0 && len(js.History) > retention.MaxCount)) && now.Sub(js.historyCulled) >= DefaultHistoryCullInterval {
+			js.historyCulled = now
+			logger.MaybeError(js.Log, js.HistoryStore.Cull(ctx, js.Name, retention, now))
+		}
+	}
 }
 
 func (js *JobScheduler) cullHistory() []JobInvocation {
 	count := len(js.History)
-	maxCount := js.Config.HistoryMaxCountOrDefault()
-	maxAge := js.Config.HistoryMaxAgeOrDefault()
+	retention := js.HistoryRetention()
 	now := time.Now().UTC()
 	var filtered []JobInvocation
 	for index, h := range js.History {
-		if maxCount > 0 {
-			if index < (count - maxCount) {
-				continue
-			}
+		if retention.Keep(index, count, h.Started, now) {
+			filtered = append(filtered, h)
 		}
-		if maxAge > 0 {
-			if now.Sub(h.Started) > maxAge {
-				continue
-			}
-		}
-		filtered = append(filtered, h)
 	}
 	return filtered
 }
+
+// restoreState restores the state of an invocation loaded from the history store
+// if the job implements `InvocationStateUnmarshaler`.
+func (js *JobScheduler) restoreState(ji *JobInvocation) {
+	raw, ok := ji.State.(json.RawMessage)
+	if !ok {
+		return
+	}
+	typed, ok := js.Job.(InvocationStateUnmarshaler)
+	if !ok {
+		return
+	}
+	state, err := typed.UnmarshalInvocationState(raw)
+	if err != nil {
+		logger.MaybeError(js.Log, ex.New(err))
+		return
+	}
+	ji.State = state
+}
@@ -19,3 +19,13 @@ func OptJobSchedulerLog(log logger.Log) JobSchedulerOption {
 func OptJobSchedulerConfig(hc Config) JobSchedulerOption {
 	return func(js *JobScheduler) { js.Config = hc }
 }
+
+// OptJobSchedulerHistoryStore sets the job scheduler history store.
+func OptJobSchedulerHistoryStore(store HistoryStore) JobSchedulerOption {
+	return func(js *JobScheduler) { js.HistoryStore = store }
+}
+
+// OptJobSchedulerLocker sets the job scheduler locker.
+func OptJobSchedulerLocker(locker Locker) JobSchedulerOption {
+	return func(js *JobScheduler) { js.Locker = locker }
+}
@@ -0,0 +1,132 @@
+package cron
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/blend/go-sdk/ex"
+)
+
+var (
+	_ Locker = (*LocalLocker)(nil)
+)
+
+// Locker acquires leases on keys, so a job scheduled on many replicas of a job manager
+// runs on only one of them at a time.
+//
+// Leases expire after a ttl unless they are renewed, so a lease held by a replica that
+// crashes is eventually acquired by another replica.
+type Locker interface {
+	// Acquire acquires a lease on a key for a holder and a ttl.
+	// It returns a nil lease if the key is leased to another holder.
+	Acquire(ctx context.Context, key, holder string, ttl time.Duration) (*Lease, error)
+	// Renew extends a lease by a ttl from the current time.
+	// It returns an error of class `ErrLeaseLost` if the lease has expired and been acquired by another holder.
+	Renew(ctx context.Context, lease *Lease, ttl time.Duration) error
+	// Release releases a lease so the key can be acquired by other holders.
+	Release(ctx context.Context, lease *Lease) error
+}
+
+// Lease is a lease on a key.
+type Lease struct {
+	Key    string `json:"key"`
+	Holder string `json:"holder"`
+	// Token is a fencing token that increases each time the key is acquired.
+	// Jobs can pass it to the systems they write to so writes from a holder
+	// whose lease has been lost can be rejected.
+	Token   int64     `json:"token"`
+	Expires time.Time `json:"expires"`
+}
+
+type leaseKey struct{}
+
+// WithLease adds a lease to a context as a value.
+func WithLease(ctx context.Context, lease *Lease) context.Context {
+	return context.WithValue(ctx, leaseKey{}, lease)
+}
+
+// GetLease returns the lease a job invocation holds from a context.
+func GetLease(ctx context.Context) *Lease {
+	if ctx == nil {
+		return nil
+	}
+	if lease, ok := ctx.Value(leaseKey{}).(*Lease); ok {
+		return lease
+	}
+	return nil
+}
+
+// NewLocalLocker returns a new in-process locker.
+func NewLocalLocker() *LocalLocker {
+	return &LocalLocker{
+		Leases: map[string]Lease{},
+	}
+}
+
+// LocalLocker is an in-process locker.
+// It is useful for tests, or to serialize jobs across job managers in a single process.
+type LocalLocker struct {
+	sync.Mutex
+	Leases map[string]Lease
+
+	now func() time.Time
+}
+
+// Acquire implements Locker.
+func (ll *LocalLocker) Acquire(_ context.Context, key, holder string, ttl time.Duration) (*Lease, error) {
+	ll.Lock()
+	defer ll.Unlock()
+
+	now := ll.nowUTC()
+	current, ok := ll.Leases[key]
+	if ok && current.Expires.After(now) {
+		return nil, nil
+	}
+	lease := Lease{
+		Key:     key,
+		Holder:  holder,
+		Token:   current.Token + 1,
+		Expires: now.Add(ttl),
+	}
+	ll.Leases[key] = lease
+	return &lease, nil
+}
+
+// Renew implements Locker.
+func (ll *LocalLocker) Renew(_ context.Context, lease *Lease, ttl time.Duration) error {
+	ll.Lock()
+	defer ll.Unlock()
+
+	now := ll.nowUTC()
+	current, ok := ll.Leases[lease.Key]
+	if !ok || current.Holder != lease.Holder || current.Token != lease.Token {
+		return ex.New(ErrLeaseLost, ex.OptMessagef("key: %s", lease.Key))
+	}
+	current.Expires = now.Add(ttl)
+	ll.Leases[lease.Key] = current
+	lease.Expires = current.Expires
+	return nil
+}
+
+// Release implements Locker.
+// The lease is expired rather than removed so fencing tokens keep increasing.
+func (ll *LocalLocker) Release(_ context.Context, lease *Lease) error {
+	ll.Lock()
+	defer ll.Unlock()
+
+	current, ok := ll.Leases[lease.Key]
+	if !ok || current.Holder != lease.Holder || current.Token != lease.Token {
+		return nil
+	}
+	current.Expires = ll.nowUTC()
+	ll.Leases[lease.Key] = current
+	return nil
+}
+
+func (ll *LocalLocker) nowUTC() time.Time {
+	if ll.now != nil {
+		return ll.now().UTC()
+	}
+	return Now()
+}
@@ -0,0 +1,203 @@
+package cron
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestLocalLocker(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Date(2019, 10, 10, 12, 00, 00, 00, time.UTC)
+	locker := NewLocalLocker()
+	locker.now = func() time.Time { return now }
+
+	lease, err := locker.Acquire(context.Background(), "test", "a", time.Minute)
+	assert.Nil(err)
+	assert.NotNil(lease)
+	assert.Equal(int64(1), lease.Token)
+	assert.Equal(now.Add(time.Minute), lease.Expires)
+
+	held, err := locker.Acquire(context.Background(), "test", "b", time.Minute)
+	assert.Nil(err)
+	assert.Nil(held)
+
+	now = now.Add(30 * time.Second)
+	assert.Nil(locker.Renew(context.Background(), lease, time.Minute))
+	assert.Equal(now.Add(time.Minute), lease.Expires)
+
+	// the lease expires and is acquired by another holder.
+	now = now.Add(2 * time.Minute)
+	next, err := locker.Acquire(context.Background(), "test", "b", time.Minute)
+	assert.Nil(err)
+	assert.NotNil(next)
+	assert.Equal(int64(2), next.Token)
+	assert.True(IsLeaseLost(locker.Renew(context.Background(), lease, time.Minute)))
+
+	// releasing a lost lease does not release the current lease.
+	assert.Nil(locker.Release(context.Background(), lease))
+	held, err = locker.Acquire(context.Background(), "test", "c", time.Minute)
+	assert.Nil(err)
+	assert.Nil(held)
+
+	assert.Nil(locker.Release(context.Background(), next))
+	last, err := locker.Acquire(context.Background(), "test", "c", time.Minute)
+	assert.Nil(err)
+	assert.NotNil(last)
+	assert.Equal(int64(3), last.Token)
+}
+
+func TestJobManagerLockerRunsOnce(t *testing.T) {
+	assert := assert.New(t)
+
+	locker := NewLocalLocker()
+	var runs int32
+	var tokens []int64
+	var tokensLock sync.Mutex
+	started := make(chan struct{})
+	finish := make(chan struct{})
+	newJob := func() Job {
+		return NewJob("locked", func(ctx context.Context) error {
+			atomic.AddInt32(&runs, 1)
+			tokensLock.Lock()
+			tokens = append(tokens, GetLease(ctx).Token)
+			tokensLock.Unlock()
+			close(started)
+			<-finish
+			return nil
+		})
+	}
+
+	replicaA := New(OptLocker(locker), OptConfig(Config{LockMinHold: time.Millisecond}))
+	replicaB := New(OptLocker(locker), OptConfig(Config{LockMinHold: time.Millisecond}))
+	assert.Nil(replicaA.LoadJobs(newJob()))
+	assert.Nil(replicaB.LoadJobs(newJob()))
+
+	jsA, err := replicaA.Job("locked")
+	assert.Nil(err)
+	jsB, err := replicaB.Job("locked")
+	assert.Nil(err)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		jsA.Run()
+	}()
+	<-started
+
+	// the lease is held by replica a, so replica b does not run the job.
+	jsB.Run()
+	assert.Equal(int32(1), atomic.LoadInt32(&runs))
+	assert.Empty(jsB.History)
+
+	close(finish)
+	<-done
+	assert.Len(jsA.History, 1)
+	assert.Equal([]int64{1}, tokens)
+}
+
+func TestJobSchedulerLockMinHold(t *testing.T) {
+	assert := assert.New(t)
+
+	locker := NewLocalLocker()
+	var runs int32
+	js := NewJobScheduler(NewJob("min-hold", func(_ context.Context) error {
+		atomic.AddInt32(&runs, 1)
+		return nil
+	}), OptJobSchedulerLocker(locker), OptJobSchedulerConfig(Config{LockMinHold: time.Minute}))
+
+	js.Run()
+	js.Run()
+	assert.Equal(int32(1), atomic.LoadInt32(&runs))
+	assert.True(locker.Leases["min-hold"].Expires.After(Now().Add(50 * time.Second)))
+}
+
+type noLockJob struct {
+	*JobBuilder
+}
+
+func (nlj noLockJob) Locker() Locker { return nil }
+
+func TestJobSchedulerLockerProvider(t *testing.T) {
+	assert := assert.New(t)
+
+	locker := NewLocalLocker()
+	var runs int32
+	job := noLockJob{NewJob("unlocked", func(_ context.Context) error {
+		atomic.AddInt32(&runs, 1)
+		return nil
+	})}
+	js := NewJobScheduler(job, OptJobSchedulerLocker(locker), OptJobSchedulerConfig(Config{LockMinHold: time.Minute}))
+	js.Run()
+	js.Run()
+	assert.Equal(int32(2), atomic.LoadInt32(&runs))
+	assert.Empty(locker.Leases)
+}
+
+// lostLocker is a locker whose leases are lost when they are renewed.
+type lostLocker struct {
+	*LocalLocker
+}
+
+func (ll lostLocker) Renew(ctx context.Context, lease *Lease, ttl time.Duration) error {
+	return ErrLeaseLost
+}
+
+func TestJobSchedulerLeaseLost(t *testing.T) {
+	assert := assert.New(t)
+
+	js := NewJobScheduler(NewJob("lease-lost", func(ctx context.Context) error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(5 * time.Second):
+			return nil
+		}
+	}), OptJobSchedulerLocker(lostLocker{NewLocalLocker()}), OptJobSchedulerConfig(Config{LockTTL: 30 * time.Millisecond}))
+
+	js.Run()
+	assert.NotNil(js.Last)
+	assert.True(IsLeaseLost(js.Last.Err))
+	assert.Equal(JobStatusFailed, js.Last.Status)
+}
+
+// skewedLocker is a locker whose clock is behind, and whose every other renewal fails.
+type skewedLocker struct {
+	*LocalLocker
+	renewals int32
+}
+
+func (sl *skewedLocker) Renew(ctx context.Context, lease *Lease, ttl time.Duration) error {
+	if atomic.AddInt32(&sl.renewals, 1)%2 == 0 {
+		return fmt.Errorf("connection reset")
+	}
+	lease.Expires = time.Now().UTC().Add(-time.Hour)
+	return nil
+}
+
+func TestJobSchedulerLeaseSkewed(t *testing.T) {
+	assert := assert.New(t)
+
+	locker := &skewedLocker{LocalLocker: NewLocalLocker()}
+	js := NewJobScheduler(NewJob("lease-skewed", func(ctx context.Context) error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(200 * time.Millisecond):
+			return nil
+		}
+	}), OptJobSchedulerLocker(locker), OptJobSchedulerConfig(Config{LockTTL: 60 * time.Millisecond}))
+
+	// a failed renewal is not taken as a lost lease while the last successful renewal is within the ttl,
+	// regardless of the expiry set by the locker's clock.
+	js.Run()
+	assert.NotNil(js.Last)
+	assert.Nil(js.Last.Err)
+	assert.True(atomic.LoadInt32(&locker.renewals) > 1)
+}
@@ -0,0 +1,68 @@
+package cron
+
+import "time"
+
+// MisfireMode is how a job handles scheduled runs that were missed.
+type MisfireMode string
+
+// Misfire modes.
+const (
+	// MisfireModeSkip skips missed runs, and waits for the next scheduled run.
+	MisfireModeSkip MisfireMode = "skip"
+	// MisfireModeRunOnce runs the job once if any runs were missed.
+	MisfireModeRunOnce MisfireMode = "runOnce"
+	// MisfireModeRunAll runs the job for each missed run, up to a maximum.
+	MisfireModeRunAll MisfireMode = "runAll"
+)
+
+// MisfirePolicy is a policy for scheduled runs that were missed while a job was not scheduled,
+// for example because the process was down.
+//
+// Missed runs are found by walking the job schedule forward from when the last invocation started,
+// which is persisted across restarts if the job manager has a history store, for at most
+// `DefaultMisfireMaxWalk` scheduled runtimes. The catch-up runs run when the job scheduler starts,
+// one after the other, before the schedule resumes.
+type MisfirePolicy struct {
+	Mode MisfireMode `json:"mode,omitempty" yaml:"mode,omitempty"`
+	// MaxRuns is the maximum number of missed runs to run in `MisfireModeRunAll`, which are the most recent.
+	// It defaults to `DefaultMisfireMaxRuns`.
+	MaxRuns int `json:"maxRuns,omitempty" yaml:"maxRuns,omitempty"`
+}
+
+// MaxRunsOrDefault returns the maximum number of missed runs to run or a default.
+func (mp MisfirePolicy) MaxRunsOrDefault() int {
+	if mp.MaxRuns > 0 {
+		return mp.MaxRuns
+	}
+	return DefaultMisfireMaxRuns
+}
+
+// Missed returns the scheduled runtimes missed between the last run and now that should be run.
+//
+// If more than `DefaultMisfireMaxWalk` runtimes were missed, the walk stops there, and the runtimes
+// returned are the latest it reached rather than the latest before now.
+func (mp MisfirePolicy) Missed(schedule Schedule, last, now time.Time) []time.Time {
+	var maxRuns int
+	switch mp.Mode {
+	case MisfireModeRunOnce:
+		maxRuns = 1
+	case MisfireModeRunAll:
+		maxRuns = mp.MaxRunsOrDefault()
+	default:
+		return nil
+	}
+	if schedule == nil || last.IsZero() {
+		return nil
+	}
+
+	var missed []time.Time
+	previous := last
+	for walked, next := 0, schedule.Next(last); walked < DefaultMisfireMaxWalk && !next.IsZero() && next.After(previous) && !next.After(now); walked, next = walked+1, schedule.Next(next) {
+		missed = append(missed, next)
+		if len(missed) > maxRuns {
+			missed = missed[1:]
+		}
+		previous = next
+	}
+	return missed
+}
@@ -0,0 +1,147 @@
+package cron
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestMisfirePolicyMissed(t *testing.T) {
+	assert := assert.New(t)
+
+	last := time.Date(2019, 10, 10, 0, 0, 0, 0, time.UTC)
+	now := last.Add(5*time.Hour + 30*time.Minute)
+	schedule := Every(time.Hour)
+
+	assert.Empty(MisfirePolicy{}.Missed(schedule, last, now))
+	assert.Empty(MisfirePolicy{Mode: MisfireModeSkip}.Missed(schedule, last, now))
+	assert.Equal([]time.Time{last.Add(5 * time.Hour)}, MisfirePolicy{Mode: MisfireModeRunOnce}.Missed(schedule, last, now))
+	assert.Equal([]time.Time{
+		last.Add(3 * time.Hour),
+		last.Add(4 * time.Hour),
+		last.Add(5 * time.Hour),
+	}, MisfirePolicy{Mode: MisfireModeRunAll, MaxRuns: 3}.Missed(schedule, last, now))
+	assert.Len(MisfirePolicy{Mode: MisfireModeRunAll}.Missed(schedule, last, now), 5)
+
+	assert.Empty(MisfirePolicy{Mode: MisfireModeRunAll}.Missed(schedule, last, last.Add(30*time.Minute)))
+	assert.Empty(MisfirePolicy{Mode: MisfireModeRunAll}.Missed(schedule, time.Time{}, now))
+	assert.Empty(MisfirePolicy{Mode: MisfireModeRunAll}.Missed(OnceAtUTC(last), last, now))
+}
+
+func TestJobManagerMisfires(t *testing.T) {
+	assert := assert.New(t)
+
+	path, err := ioutil.TempDir("", "cron-misfire")
+	assert.Nil(err)
+	defer os.RemoveAll(path)
+
+	// the last run was persisted by a previous process two and a half hours ago.
+	store := NewFileHistoryStore(path)
+	assert.Nil(store.Add(context.Background(), JobInvocation{
+		ID:       NewJobInvocationID(),
+		JobName:  "misfire",
+		Started:  Now().Add(-150 * time.Minute),
+		Finished: Now().Add(-149 * time.Minute),
+		Status:   JobStatusComplete,
+	}))
+
+	runs := make(chan struct{}, 10)
+	jm := New(OptHistoryStore(store))
+	assert.Nil(jm.LoadJobs(NewJob("misfire", func(_ context.Context) error {
+		runs <- struct{}{}
+		return nil
+	},
+		OptJobBuilderSchedule(Every(time.Hour)),
+		OptJobBuilderMisfirePolicy(MisfirePolicy{Mode: MisfireModeRunAll}),
+	)))
+	assert.Nil(jm.StartAsync())
+	defer jm.Stop()
+
+	for x := 0; x < 2; x++ {
+		select {
+		case <-runs:
+		case <-time.After(5 * time.Second):
+			assert.FailNow("missed runs should have run")
+		}
+	}
+	select {
+	case <-runs:
+		assert.FailNow("only the two missed runs should have run")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+type countingSchedule struct {
+	Schedule
+	calls *int
+}
+
+func (cs countingSchedule) Next(after time.Time) time.Time {
+	*cs.calls++
+	return cs.Schedule.Next(after)
+}
+
+func TestMisfirePolicyMissedBoundsWalk(t *testing.T) {
+	assert := assert.New(t)
+
+	// a year of missed runs every second.
+	last := time.Date(2019, 10, 10, 0, 0, 0, 0, time.UTC)
+	now := last.AddDate(1, 0, 0)
+	var calls int
+	schedule := countingSchedule{Schedule: EverySecond(), calls: &calls}
+
+	assert.Len(MisfirePolicy{Mode: MisfireModeRunAll, MaxRuns: 3}.Missed(schedule, last, now), 3)
+	assert.True(calls <= DefaultMisfireMaxWalk+1, "the walk should be bounded")
+
+	calls = 0
+	assert.Len(MisfirePolicy{Mode: MisfireModeRunOnce}.Missed(schedule, last, now), 1)
+	assert.True(calls <= DefaultMisfireMaxWalk+1, "the walk should be bounded")
+}
+
+// soonSchedule first fires shortly after it is scheduled, then every hour.
+type soonSchedule struct{}
+
+func (soonSchedule) Next(after time.Time) time.Time {
+	if after.IsZero() {
+		return Now().Add(10 * time.Millisecond)
+	}
+	return after.Add(time.Hour)
+}
+
+func TestJobSchedulerMisfiresBeforeSchedule(t *testing.T) {
+	assert := assert.New(t)
+
+	var runs int32
+	ran := make(chan struct{}, 10)
+	js := NewJobScheduler(NewJob("misfire", func(_ context.Context) error {
+		atomic.AddInt32(&runs, 1)
+		time.Sleep(50 * time.Millisecond)
+		ran <- struct{}{}
+		return nil
+	},
+		OptJobBuilderSchedule(soonSchedule{}),
+		OptJobBuilderConcurrencyPolicy(ConcurrencyPolicyForbid),
+		OptJobBuilderMisfirePolicy(MisfirePolicy{Mode: MisfireModeRunAll}),
+	))
+	last := JobInvocation{ID: NewJobInvocationID(), JobName: "misfire", Started: Now().Add(-150 * time.Minute), Status: JobStatusComplete}
+	js.Last = &last
+
+	go js.Start()
+	<-js.NotifyStarted()
+	defer js.Stop()
+
+	// the two catch-up runs finish before the first scheduled run, so forbid does not skip it.
+	for x := 0; x < 3; x++ {
+		select {
+		case <-ran:
+		case <-time.After(5 * time.Second):
+			assert.FailNow("the catch-up and scheduled runs should have run")
+		}
+	}
+	assert.Equal(3, atomic.LoadInt32(&runs))
+}
@@ -20,31 +20,52 @@ func EveryHourAtUTC(minute, second int) Schedule {
 	return OnTheHourAtUTCSchedule{Minute: minute, Second: second}
 }
 
+// EveryHourAt returns a schedule that fires every hour at a given minute past the hour in a location.
+func EveryHourAt(minute, second int, location *time.Location) Schedule {
+	return OnTheHourAtUTCSchedule{Minute: minute, Second: second, Location: location}
+}
+
 // OnTheHourAtUTCSchedule is a schedule that fires every hour on the given minute.
+//
+// If Location is set, the minute is past the hour in that location, which differs from UTC
+// for locations with fractional hour offsets. The schedule fires every elapsed hour, so hours
+// repeated when clocks are set back fire twice, and hours skipped when clocks are set forward
+// do not fire.
 type OnTheHourAtUTCSchedule struct {
-	Minute int
-	Second int
+	Minute   int
+	Second   int
+	Location *time.Location
+}
+
+// LocationOrDefault returns the schedule location or UTC.
+func (o OnTheHourAtUTCSchedule) LocationOrDefault() *time.Location {
+	if o.Location != nil {
+		return o.Location
+	}
+	return time.UTC
 }
 
 // String returns a string representation of the schedule.
 func (o OnTheHourAtUTCSchedule) String() string {
+	if o.Location != nil {
+		return fmt.Sprintf("on the hour at %v:%v in %s", o.Minute, o.Second, o.Location.String())
+	}
 	return fmt.Sprintf("on the hour at %v:%v", o.Minute, o.Second)
 }
 
 // Next implements the chronometer Schedule api.
 func (o OnTheHourAtUTCSchedule) Next(after time.Time) time.Time {
-	var returnValue time.Time
-	now := Now()
 	if after.IsZero() {
-		returnValue = time.Date(now.Year(), now.Month(), now.Day(), now.Hour(), o.Minute, o.Second, 0, time.UTC)
-		if returnValue.Before(now) {
-			returnValue = returnValue.Add(time.Hour)
-		}
-	} else {
-		returnValue = time.Date(after.Year(), after.Month(), after.Day(), after.Hour(), o.Minute, o.Second, 0, time.UTC)
-		if returnValue.Before(after) {
-			returnValue = returnValue.Add(time.Hour)
-		}
+		after = Now()
+	}
+
+	// the hour is truncated by elapsed time, rather than by wall clock, so daylight
+	// saving time transitions neither skip nor repeat runs.
+	local := after.In(o.LocationOrDefault())
+	sinceHour := time.Duration(local.Minute())*time.Minute + time.Duration(local.Second())*time.Second + time.Duration(local.Nanosecond())
+	returnValue := local.Add(-sinceHour).Add(time.Duration(o.Minute)*time.Minute + time.Duration(o.Second)*time.Second)
+	if returnValue.Before(after) {
+		returnValue = returnValue.Add(time.Hour)
 	}
 	return returnValue
 }
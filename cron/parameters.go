@@ -0,0 +1,131 @@
+package cron
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/blend/go-sdk/ex"
+)
+
+// ParameterType is the type of a job parameter's values.
+type ParameterType string
+
+// ParameterType values.
+const (
+	ParameterTypeString   ParameterType = "string"
+	ParameterTypeInt      ParameterType = "int"
+	ParameterTypeFloat    ParameterType = "float"
+	ParameterTypeBool     ParameterType = "bool"
+	ParameterTypeDuration ParameterType = "duration"
+)
+
+// ParameterValues are the parameter values a job is invoked with, by parameter name.
+//
+// Values are strings, so they can be read from forms and query strings as well as json,
+// and are validated against the type declared in the job's `ParameterSchema`.
+type ParameterValues map[string]string
+
+// ParameterSchema declares the parameters a job can be invoked with.
+type ParameterSchema []Parameter
+
+// Parameter declares a job parameter.
+type Parameter struct {
+	// Name is the parameter name.
+	Name string `json:"name" yaml:"name"`
+	// Description is shown next to the parameter in the jobkit ui.
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	// Type is the type of the parameter's values; it defaults to `ParameterTypeString`.
+	Type ParameterType `json:"type,omitempty" yaml:"type,omitempty"`
+	// Required parameters must be given a value unless they have a default.
+	Required bool `json:"required,omitempty" yaml:"required,omitempty"`
+	// Default is the value used if the parameter is not given a value.
+	Default string `json:"default,omitempty" yaml:"default,omitempty"`
+	// Values are the allowed values of the parameter if set.
+	Values []string `json:"values,omitempty" yaml:"values,omitempty"`
+}
+
+// TypeOrDefault returns the parameter type or a default.
+func (p Parameter) TypeOrDefault() ParameterType {
+	if p.Type != "" {
+		return p.Type
+	}
+	return ParameterTypeString
+}
+
+// Validate returns an error of class `ErrParametersInvalid` if a value is not valid for the parameter.
+func (p Parameter) Validate(value string) error {
+	if len(p.Values) > 0 {
+		var allowed bool
+		for _, option := range p.Values {
+			if value == option {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return ex.New(ErrParametersInvalid, ex.OptMessagef("parameter: %s; value must be one of: %v", p.Name, p.Values))
+		}
+	}
+
+	var err error
+	switch p.TypeOrDefault() {
+	case ParameterTypeString:
+	case ParameterTypeInt:
+		_, err = strconv.ParseInt(value, 10, 64)
+	case ParameterTypeFloat:
+		_, err = strconv.ParseFloat(value, 64)
+	case ParameterTypeBool:
+		_, err = strconv.ParseBool(value)
+	case ParameterTypeDuration:
+		_, err = time.ParseDuration(value)
+	default:
+		return ex.New(ErrParametersInvalid, ex.OptMessagef("parameter: %s; unknown type: %s", p.Name, p.Type))
+	}
+	if err != nil {
+		return ex.New(ErrParametersInvalid, ex.OptMessagef("parameter: %s; value must be a %s", p.Name, p.TypeOrDefault()))
+	}
+	return nil
+}
+
+// Validate validates parameter values against the schema, and returns them with defaults applied.
+//
+// Empty values are treated as unset. It returns an error of class `ErrParametersInvalid` if a
+// required parameter is unset, a value is invalid, or a value is given for an undeclared parameter.
+func (ps ParameterSchema) Validate(values ParameterValues) (ParameterValues, error) {
+	declared := make(map[string]bool, len(ps))
+	output := ParameterValues{}
+	for _, parameter := range ps {
+		declared[parameter.Name] = true
+
+		value := values[parameter.Name]
+		if value == "" {
+			value = parameter.Default
+		}
+		if value == "" {
+			if parameter.Required {
+				return nil, ex.New(ErrParametersInvalid, ex.OptMessagef("parameter: %s; a value is required", parameter.Name))
+			}
+			continue
+		}
+		if err := parameter.Validate(value); err != nil {
+			return nil, err
+		}
+		output[parameter.Name] = value
+	}
+
+	var undeclared []string
+	for name, value := range values {
+		if !declared[name] && value != "" {
+			undeclared = append(undeclared, name)
+		}
+	}
+	if len(undeclared) > 0 {
+		sort.Strings(undeclared)
+		return nil, ex.New(ErrParametersInvalid, ex.OptMessagef("undeclared parameters: %v", undeclared))
+	}
+	if len(output) == 0 {
+		return nil, nil
+	}
+	return output, nil
+}
@@ -0,0 +1,91 @@
+package cron
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestParameterSchemaValidate(t *testing.T) {
+	assert := assert.New(t)
+
+	schema := ParameterSchema{
+		{Name: "table", Required: true},
+		{Name: "limit", Type: ParameterTypeInt, Default: "100"},
+		{Name: "dryRun", Type: ParameterTypeBool},
+		{Name: "window", Type: ParameterTypeDuration},
+		{Name: "mode", Values: []string{"full", "incremental"}},
+	}
+
+	values, err := schema.Validate(ParameterValues{"table": "users", "dryRun": "true"})
+	assert.Nil(err)
+	assert.Equal(ParameterValues{"table": "users", "limit": "100", "dryRun": "true"}, values)
+
+	values, err = schema.Validate(ParameterValues{"table": "users", "limit": "5", "window": "1h", "mode": "full", "dryRun": ""})
+	assert.Nil(err)
+	assert.Equal(ParameterValues{"table": "users", "limit": "5", "window": "1h", "mode": "full"}, values)
+
+	_, err = schema.Validate(nil)
+	assert.True(IsParametersInvalid(err))
+	_, err = schema.Validate(ParameterValues{"table": "users", "limit": "five"})
+	assert.True(IsParametersInvalid(err))
+	_, err = schema.Validate(ParameterValues{"table": "users", "window": "an hour"})
+	assert.True(IsParametersInvalid(err))
+	_, err = schema.Validate(ParameterValues{"table": "users", "mode": "partial"})
+	assert.True(IsParametersInvalid(err))
+	_, err = schema.Validate(ParameterValues{"table": "users", "unknown": "value"})
+	assert.True(IsParametersInvalid(err))
+
+	values, err = ParameterSchema(nil).Validate(nil)
+	assert.Nil(err)
+	assert.Nil(values)
+	_, err = ParameterSchema(nil).Validate(ParameterValues{"unknown": "value"})
+	assert.True(IsParametersInvalid(err))
+}
+
+func TestJobManagerRunJobWithParameters(t *testing.T) {
+	assert := assert.New(t)
+
+	invoked := make(chan ParameterValues, 1)
+	jm := New()
+	assert.Nil(jm.LoadJobs(NewJob("backfill", func(ctx context.Context) error {
+		invoked <- GetJobInvocation(ctx).Parameters
+		return nil
+	}, OptJobBuilderParameterSchema(
+		Parameter{Name: "table", Required: true},
+		Parameter{Name: "limit", Type: ParameterTypeInt, Default: "100"},
+	))))
+
+	assert.True(IsParametersInvalid(jm.RunJobWithParameters("backfill", ParameterValues{"limit": "10"})))
+	assert.True(IsJobNotLoaded(jm.RunJobWithParameters("not-loaded", nil)))
+
+	assert.Nil(jm.RunJobWithParameters("backfill", ParameterValues{"table": "users"}))
+	select {
+	case values := <-invoked:
+		assert.Equal(ParameterValues{"table": "users", "limit": "100"}, values)
+	case <-time.After(5 * time.Second):
+		assert.FailNow("the job should have run")
+	}
+
+	// scheduled runs use the defaults, and do not run without a required value.
+	job, err := jm.Job("backfill")
+	assert.Nil(err)
+	job.Run()
+	select {
+	case <-invoked:
+		assert.FailNow("the job should not run without a required parameter")
+	default:
+	}
+}
+
+func TestHistoryRecordParameters(t *testing.T) {
+	assert := assert.New(t)
+
+	ji := NewJobInvocation("test")
+	ji.Parameters = ParameterValues{"table": "users"}
+	record, err := NewHistoryRecord(*ji)
+	assert.Nil(err)
+	assert.Equal(ParameterValues{"table": "users"}, record.JobInvocation().Parameters)
+}
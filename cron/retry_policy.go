@@ -0,0 +1,74 @@
+package cron
+
+import (
+	"time"
+
+	"github.com/blend/go-sdk/ex"
+)
+
+// RetryPolicy is a policy for re-invoking failed jobs.
+//
+// Retries run as new invocations linked to the invocation they retry, and wait for an exponential
+// backoff between attempts. Cancelled invocations and invocations that lose their lease are not retried.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts, including the first invocation.
+	// Values less than or equal to one disable retries.
+	MaxAttempts int `json:"maxAttempts,omitempty" yaml:"maxAttempts,omitempty"`
+	// Backoff is the delay before the first retry.
+	Backoff time.Duration `json:"backoff,omitempty" yaml:"backoff,omitempty"`
+	// BackoffMultiplier is the factor the delay grows by for each further retry.
+	// It defaults to `DefaultRetryBackoffMultiplier`.
+	BackoffMultiplier float64 `json:"backoffMultiplier,omitempty" yaml:"backoffMultiplier,omitempty"`
+	// MaxBackoff caps the delay between retries if set.
+	MaxBackoff time.Duration `json:"maxBackoff,omitempty" yaml:"maxBackoff,omitempty"`
+	// RetryableErrors are error classes, compared with `ex.Is`, that are retried.
+	// If unset, all errors are retried.
+	RetryableErrors []error `json:"-" yaml:"-"`
+}
+
+// BackoffMultiplierOrDefault returns the backoff multiplier or a default.
+func (rp RetryPolicy) BackoffMultiplierOrDefault() float64 {
+	if rp.BackoffMultiplier > 0 {
+		return rp.BackoffMultiplier
+	}
+	return DefaultRetryBackoffMultiplier
+}
+
+// ShouldRetry returns if an invocation that failed with a given error on a given attempt, starting at one, should be retried.
+func (rp RetryPolicy) ShouldRetry(attempt int, err error) bool {
+	if err == nil || attempt >= rp.MaxAttempts {
+		return false
+	}
+	if IsJobCancelled(err) || IsLeaseLost(err) {
+		return false
+	}
+	return rp.IsRetryable(err)
+}
+
+// IsRetryable returns if an error matches the retryable error classes.
+func (rp RetryPolicy) IsRetryable(err error) bool {
+	if len(rp.RetryableErrors) == 0 {
+		return true
+	}
+	for _, class := range rp.RetryableErrors {
+		if ex.Is(err, class) {
+			return true
+		}
+	}
+	return false
+}
+
+// BackoffFor returns the delay before retrying a given failed attempt, starting at one.
+func (rp RetryPolicy) BackoffFor(attempt int) time.Duration {
+	backoff := float64(rp.Backoff)
+	for x := 1; x < attempt; x++ {
+		backoff = backoff * rp.BackoffMultiplierOrDefault()
+		if rp.MaxBackoff > 0 && backoff >= float64(rp.MaxBackoff) {
+			return rp.MaxBackoff
+		}
+	}
+	if rp.MaxBackoff > 0 && backoff > float64(rp.MaxBackoff) {
+		return rp.MaxBackoff
+	}
+	return time.Duration(backoff)
+}
@@ -0,0 +1,208 @@
+package cron
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/ex"
+)
+
+func TestRetryPolicyShouldRetry(t *testing.T) {
+	assert := assert.New(t)
+
+	policy := RetryPolicy{MaxAttempts: 3}
+	assert.True(policy.ShouldRetry(1, ex.New("test")))
+	assert.True(policy.ShouldRetry(2, ex.New("test")))
+	assert.False(policy.ShouldRetry(3, ex.New("test")))
+	assert.False(policy.ShouldRetry(1, nil))
+	assert.False(policy.ShouldRetry(1, ErrJobCancelled))
+	assert.False(policy.ShouldRetry(1, ex.New(ErrLeaseLost)))
+	assert.False(RetryPolicy{}.ShouldRetry(1, ex.New("test")))
+
+	retryable := ex.Class("retryable")
+	policy.RetryableErrors = []error{retryable}
+	assert.True(policy.ShouldRetry(1, ex.New(retryable, ex.OptMessage("with a message"))))
+	assert.False(policy.ShouldRetry(1, ex.New("not retryable")))
+}
+
+func TestRetryPolicyBackoffFor(t *testing.T) {
+	assert := assert.New(t)
+
+	policy := RetryPolicy{Backoff: time.Second}
+	assert.Equal(time.Second, policy.BackoffFor(1))
+	assert.Equal(2*time.Second, policy.BackoffFor(2))
+	assert.Equal(4*time.Second, policy.BackoffFor(3))
+
+	policy.BackoffMultiplier = 3
+	assert.Equal(9*time.Second, policy.BackoffFor(3))
+
+	policy.MaxBackoff = 5 * time.Second
+	assert.Equal(time.Second, policy.BackoffFor(1))
+	assert.Equal(5*time.Second, policy.BackoffFor(3))
+	assert.Equal(5*time.Second, policy.BackoffFor(100))
+}
+
+func TestJobSchedulerRetry(t *testing.T) {
+	assert := assert.New(t)
+
+	var runs, broken, fixed, failures int
+	js := NewJobScheduler(NewJob("retry", func(_ context.Context) error {
+		runs++
+		// the first run succeeds, then the second run fails twice before succeeding.
+		if runs == 2 || runs == 3 {
+			return ex.New("flaky")
+		}
+		return nil
+	},
+		OptJobBuilderRetryPolicy(RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond}),
+		OptJobBuilderOnBroken(func(_ *JobInvocation) { broken++ }),
+		OptJobBuilderOnFixed(func(_ *JobInvocation) { fixed++ }),
+		OptJobBuilderOnFailure(func(_ *JobInvocation) { failures++ }),
+	))
+
+	js.Run()
+	js.Run()
+	assert.Equal(4, runs)
+	assert.Equal(2, failures)
+	assert.Zero(broken)
+	assert.Zero(fixed)
+
+	assert.Len(js.History, 4)
+	assert.Equal(1, js.History[1].Attempt)
+	assert.Empty(js.History[1].RetryOf)
+	assert.Equal(JobStatusFailed, js.History[1].Status)
+	assert.Equal(2, js.History[2].Attempt)
+	assert.Equal(js.History[1].ID, js.History[2].RetryOf)
+	assert.Equal(3, js.History[3].Attempt)
+	assert.Equal(js.History[2].ID, js.History[3].RetryOf)
+	assert.Equal(JobStatusComplete, js.Last.Status)
+}
+
+func TestJobSchedulerRetryExhausted(t *testing.T) {
+	assert := assert.New(t)
+
+	var runs, broken int
+	retryable := ex.Class("retryable")
+	js := NewJobScheduler(NewJob("retry", func(_ context.Context) error {
+		runs++
+		if runs == 1 {
+			return nil
+		}
+		if runs == 5 {
+			return ex.New("not retryable")
+		}
+		return ex.New(retryable)
+	},
+		OptJobBuilderRetryPolicy(RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond, RetryableErrors: []error{retryable}}),
+		OptJobBuilderOnBroken(func(_ *JobInvocation) { broken++ }),
+	))
+
+	js.Run()
+	js.Run()
+	assert.Equal(4, runs)
+	assert.Equal(1, broken, "broken should only fire once retries are exhausted")
+	assert.Equal(3, js.Last.Attempt)
+	assert.Equal(JobStatusFailed, js.Last.Status)
+
+	// errors that are not retryable are not retried.
+	js.Run()
+	assert.Equal(5, runs)
+	assert.Equal(1, js.Last.Attempt)
+}
+
+func TestJobSchedulerRetryDisabled(t *testing.T) {
+	assert := assert.New(t)
+
+	var runs int
+	var js *JobScheduler
+	js = NewJobScheduler(NewJob("retry", func(_ context.Context) error {
+		runs++
+		js.Disabled = true
+		return ex.New("failure")
+	}, OptJobBuilderRetryPolicy(RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond})))
+
+	js.Run()
+	assert.Equal(1, runs)
+}
+
+func TestJobSchedulerRetryHistoryStore(t *testing.T) {
+	assert := assert.New(t)
+
+	path, err := ioutil.TempDir("", "cron-retry")
+	assert.Nil(err)
+	defer os.RemoveAll(path)
+
+	store := NewFileHistoryStore(path)
+	js := NewJobScheduler(NewJob("retry", func(_ context.Context) error {
+		return ex.New("failure")
+	}, OptJobBuilderRetryPolicy(RetryPolicy{MaxAttempts: 2})), OptJobSchedulerHistoryStore(store))
+	js.Run()
+
+	history, err := store.List(context.Background(), "retry", 0, 0)
+	assert.Nil(err)
+	assert.Len(history, 2)
+	assert.Equal(2, history[0].Attempt)
+	assert.Equal(history[1].ID, history[0].RetryOf)
+}
+
+func TestJobSchedulerRetryCurrent(t *testing.T) {
+	assert := assert.New(t)
+
+	var runs int32
+	failed := make(chan struct{})
+	js := NewJobScheduler(NewJob("retry", func(_ context.Context) error {
+		atomic.AddInt32(&runs, 1)
+		return ex.New("failure")
+	},
+		OptJobBuilderConcurrencyPolicy(ConcurrencyPolicyForbid),
+		OptJobBuilderRetryPolicy(RetryPolicy{MaxAttempts: 3, Backoff: time.Hour}),
+		OptJobBuilderOnFailure(func(_ *JobInvocation) { close(failed) }),
+	))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		js.Run()
+	}()
+	<-failed
+
+	assert.NotNil(js.Current, "the run should stay current while it backs off")
+	js.Run()
+	assert.Equal(1, atomic.LoadInt32(&runs), "forbid should skip runs while the previous run backs off")
+
+	// cancelling the run stops the retries.
+	js.Cancel()
+	<-done
+	assert.Nil(js.Current)
+	assert.Equal(1, atomic.LoadInt32(&runs))
+}
+
+func TestJobSchedulerRetryBrokenComparesPreviousRun(t *testing.T) {
+	assert := assert.New(t)
+
+	var runs, broken, fixed int
+	js := NewJobScheduler(NewJob("retry", func(_ context.Context) error {
+		runs++
+		// the first run fails after retrying, and the second run fails once before succeeding.
+		if runs < 4 {
+			return ex.New("failure")
+		}
+		return nil
+	},
+		OptJobBuilderRetryPolicy(RetryPolicy{MaxAttempts: 2, Backoff: time.Millisecond}),
+		OptJobBuilderOnBroken(func(_ *JobInvocation) { broken++ }),
+		OptJobBuilderOnFixed(func(_ *JobInvocation) { fixed++ }),
+	))
+
+	js.Run()
+	assert.Zero(broken)
+	js.Run()
+	assert.Equal(4, runs)
+	assert.Zero(broken)
+	assert.Equal(1, fixed, "the run should be fixed against the final attempt of the previous run")
+}
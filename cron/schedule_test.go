@@ -2,6 +2,7 @@ package cron
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -116,3 +117,60 @@ func TestOnceAt(t *testing.T) {
 	result = s.Next(after)
 	assert.True(result.IsZero())
 }
+
+func TestDailyScheduleLocation(t *testing.T) {
+	assert := assert.New(t)
+
+	newYork, err := time.LoadLocation("America/New_York")
+	assert.Nil(err)
+
+	schedule := DailyAt(9, 30, 0, newYork)
+	assert.True(strings.HasSuffix(fmt.Sprint(schedule), "each week in America/New_York"))
+	assert.Equal("2019-01-02T09:30:00-05:00", schedule.Next(time.Date(2019, 01, 02, 12, 0, 0, 0, time.UTC)).Format(time.RFC3339))
+	assert.Equal("2019-01-03T09:30:00-05:00", schedule.Next(time.Date(2019, 01, 02, 15, 0, 0, 0, time.UTC)).Format(time.RFC3339))
+	assert.Equal("2019-07-03T09:30:00-04:00", schedule.Next(time.Date(2019, 07, 02, 15, 0, 0, 0, time.UTC)).Format(time.RFC3339))
+
+	// days of the week are days in the location.
+	schedule = WeeklyAt(21, 0, 0, newYork, time.Monday)
+	assert.Equal("2019-01-07T21:00:00-05:00", schedule.Next(time.Date(2019, 01, 07, 12, 0, 0, 0, time.UTC)).Format(time.RFC3339))
+	assert.Equal("2019-01-14T21:00:00-05:00", schedule.Next(time.Date(2019, 01, 8, 2, 30, 0, 0, time.UTC)).Format(time.RFC3339))
+
+	// skipped when clocks are set forward.
+	schedule = DailyAt(2, 30, 0, newYork)
+	next := schedule.Next(time.Date(2019, 03, 10, 5, 0, 0, 0, time.UTC))
+	assert.Equal("2019-03-10T03:30:00-04:00", next.Format(time.RFC3339))
+	assert.Equal("2019-03-11T02:30:00-04:00", schedule.Next(next).Format(time.RFC3339))
+
+	// repeated when clocks are set back.
+	schedule = DailyAt(1, 30, 0, newYork)
+	next = schedule.Next(time.Date(2019, 11, 03, 4, 0, 0, 0, time.UTC))
+	assert.Equal("2019-11-03T01:30:00-04:00", next.Format(time.RFC3339))
+	assert.Equal("2019-11-04T01:30:00-05:00", schedule.Next(next).Format(time.RFC3339))
+	assert.Equal("2019-11-04T01:30:00-05:00", schedule.Next(next.Add(time.Hour)).Format(time.RFC3339))
+}
+
+func TestOnTheHourAtLocation(t *testing.T) {
+	assert := assert.New(t)
+
+	kolkata, err := time.LoadLocation("Asia/Kolkata")
+	assert.Nil(err)
+	schedule := EveryHourAt(0, 0, kolkata)
+	assert.Equal("on the hour at 0:0 in Asia/Kolkata", fmt.Sprint(schedule))
+	assert.Equal("2019-01-02T18:00:00+05:30", schedule.Next(time.Date(2019, 01, 02, 12, 0, 0, 0, time.UTC)).Format(time.RFC3339))
+
+	newYork, err := time.LoadLocation("America/New_York")
+	assert.Nil(err)
+	schedule = EveryHourAt(30, 0, newYork)
+
+	// the repeated hour fires twice when clocks are set back.
+	next := schedule.Next(time.Date(2019, 11, 03, 5, 0, 0, 0, time.UTC))
+	assert.Equal("2019-11-03T01:30:00-04:00", next.Format(time.RFC3339))
+	next = schedule.Next(next.Add(time.Second))
+	assert.Equal("2019-11-03T01:30:00-05:00", next.Format(time.RFC3339))
+	next = schedule.Next(next.Add(time.Second))
+	assert.Equal("2019-11-03T02:30:00-05:00", next.Format(time.RFC3339))
+
+	// the skipped hour does not fire when clocks are set forward.
+	next = schedule.Next(time.Date(2019, 03, 10, 6, 45, 0, 0, time.UTC))
+	assert.Equal("2019-03-10T03:30:00-04:00", next.Format(time.RFC3339))
+}
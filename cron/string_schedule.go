@@ -15,6 +15,9 @@ import (
 // The string must be at least 5 components, whitespace separated.
 // If the string has 5 components a 0 will be prepended for the seconds component, and a * appended for the year component.
 // If the string has 6 components a * appended for the year component.
+// The string can be prefixed with `CRON_TZ=<location>` (or `TZ=<location>`) to compute
+// runtimes in an IANA time zone, e.g. `CRON_TZ=America/New_York 0 30 9 * * *`; see `InLocation`
+// for how daylight saving time transitions are handled.
 // (seconds) (minutes) (hours) (day of month) (month) (day of week) (year)
 /*
 	Field name     Mandatory?   Allowed values    Allowed special characters
@@ -37,6 +40,11 @@ You can also use shorthands for the cron string:
 	@every xyz will parse the `xyz` value as a duration and return an every schedule for that
 */
 func ParseString(cronString string) (Schedule, error) {
+	location, cronString, err := parseLocation(cronString)
+	if err != nil {
+		return nil, err
+	}
+
 	// escape shorthands.
 	if shorthand, ok := StringScheduleShorthands[strings.TrimSpace(cronString)]; ok {
 		cronString = shorthand
@@ -97,8 +105,13 @@ func ParseString(cronString string) (Schedule, error) {
 		return nil, ex.New(ErrStringScheduleInvalid, ex.OptInner(err), ex.OptMessage("years invalid"))
 	}
 
+	if location != nil {
+		cronString = cronSpecialTimeZone + location.String() + " " + cronString
+	}
+
 	schedule := &StringSchedule{
 		Original:    cronString,
+		Location:    location,
 		Seconds:     seconds,
 		Minutes:     minutes,
 		Hours:       hours,
@@ -148,6 +161,9 @@ var (
 // StringSchedule is a schedule generated from a cron string.
 type StringSchedule struct {
 	Original string
+	// Location is the time zone the schedule is computed in.
+	// If unset, the schedule is computed in the location of the time passed to `Next`.
+	Location *time.Location
 
 	Seconds     []int
 	Minutes     []int
@@ -175,11 +191,35 @@ func (ss *StringSchedule) FullString() string {
 		csvOfInts(ss.DaysOfWeek, "*"),
 		csvOfInts(ss.Years, "*"),
 	}
+	if ss.Location != nil {
+		return cronSpecialTimeZone + ss.Location.String() + " " + strings.Join(fields, " ")
+	}
 	return strings.Join(fields, " ")
 }
 
 // Next implements cron.Schedule.
 func (ss *StringSchedule) Next(after time.Time) time.Time {
+	if ss.Location == nil {
+		return ss.next(after)
+	}
+	if after.IsZero() {
+		after = Now()
+	}
+
+	// compute runtimes on the wall clock of the location, which does not skip or repeat,
+	// then resolve them to instants, skipping wall clock times that already occurred.
+	wall := wallClock(after, ss.Location)
+	for {
+		nextWall := ss.next(wall)
+		next := InLocation(nextWall, ss.Location)
+		if next.After(after) || !nextWall.After(wall) {
+			return next
+		}
+		wall = nextWall
+	}
+}
+
+func (ss *StringSchedule) next(after time.Time) time.Time {
 	working := after
 	if after.IsZero() {
 		working = Now()
@@ -330,7 +370,7 @@ func (ss *StringSchedule) Next(after time.Time) time.Time {
 		}
 		if !didSet {
 			working = advanceMinute(working)
-			for _, second := range ss.Hours {
+			for _, second := range ss.Seconds {
 				if second >= working.Second() {
 					working = advanceSecondTo(working, second)
 					break
@@ -342,6 +382,28 @@ func (ss *StringSchedule) Next(after time.Time) time.Time {
 	return working
 }
 
+func parseLocation(cronString string) (*time.Location, string, error) {
+	cronString = strings.TrimSpace(cronString)
+	var name string
+	if strings.HasPrefix(cronString, cronSpecialTimeZone) {
+		name = strings.TrimPrefix(cronString, cronSpecialTimeZone)
+	} else if strings.HasPrefix(cronString, cronSpecialTimeZoneShort) {
+		name = strings.TrimPrefix(cronString, cronSpecialTimeZoneShort)
+	} else {
+		return nil, cronString, nil
+	}
+
+	var remainder string
+	if index := strings.IndexAny(name, " \t"); index >= 0 {
+		name, remainder = name[:index], strings.TrimSpace(name[index:])
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, "", ex.New(ErrStringScheduleInvalid, ex.OptInner(err), ex.OptMessagef("time zone invalid; %s", name))
+	}
+	return location, remainder, nil
+}
+
 func parsePart(values string, parser func(string) (int, error), validator func(int) bool) ([]int, error) {
 	if values == string(cronSpecialStar) {
 		return nil, nil
@@ -566,6 +628,9 @@ const (
 	cronSpecialDayOfMonth = '#' //
 
 	cronSpecialEvery = "*/"
+
+	cronSpecialTimeZone      = "CRON_TZ="
+	cronSpecialTimeZoneShort = "TZ="
 )
 
 var (
@@ -57,6 +57,96 @@ func TestParseString(t *testing.T) {
 	}
 }
 
+func TestParseStringLocation(t *testing.T) {
+	assert := assert.New(t)
+
+	schedule, err := ParseString("CRON_TZ=America/New_York 0 30 9 * * *")
+	assert.Nil(err)
+	typed, ok := schedule.(*StringSchedule)
+	assert.True(ok)
+	assert.Equal("America/New_York", typed.Location.String())
+	assert.Equal("CRON_TZ=America/New_York 0 30 9 * * *", typed.String())
+	assert.Equal("CRON_TZ=America/New_York 0 30 9 * * * *", typed.FullString())
+	assert.Equal("2019-01-02T09:30:00-05:00", schedule.Next(time.Date(2019, 01, 02, 12, 0, 0, 0, time.UTC)).Format(time.RFC3339))
+	assert.Equal("2019-07-03T09:30:00-04:00", schedule.Next(time.Date(2019, 07, 02, 15, 0, 0, 0, time.UTC)).Format(time.RFC3339))
+
+	schedule, err = ParseString("TZ=Asia/Kolkata @daily")
+	assert.Nil(err)
+	assert.Equal("CRON_TZ=Asia/Kolkata 0 0 0 * * * *", schedule.(*StringSchedule).String())
+	assert.Equal("2019-01-02T00:00:00+05:30", schedule.Next(time.Date(2019, 01, 01, 12, 0, 0, 0, time.UTC)).Format(time.RFC3339))
+
+	_, err = ParseString("CRON_TZ=Not/A_Zone 0 30 9 * * *")
+	assert.True(ex.Is(err, ErrStringScheduleInvalid))
+	_, err = ParseString("CRON_TZ=UTC")
+	assert.True(ex.Is(err, ErrStringScheduleInvalid))
+}
+
+func TestStringScheduleDaylightSaving(t *testing.T) {
+	assert := assert.New(t)
+
+	// wall clock times skipped when clocks are set forward are shifted forward.
+	schedule, err := ParseString("CRON_TZ=America/New_York 0 30 2 * * *")
+	assert.Nil(err)
+	next := schedule.Next(time.Date(2019, 03, 10, 5, 0, 0, 0, time.UTC))
+	assert.Equal("2019-03-10T03:30:00-04:00", next.Format(time.RFC3339))
+	assert.Equal("2019-03-11T02:30:00-04:00", schedule.Next(next).Format(time.RFC3339))
+
+	// wall clock times repeated when clocks are set back fire once.
+	schedule, err = ParseString("CRON_TZ=America/New_York 0 30 1 * * *")
+	assert.Nil(err)
+	next = schedule.Next(time.Date(2019, 11, 03, 4, 0, 0, 0, time.UTC))
+	assert.Equal("2019-11-03T01:30:00-04:00", next.Format(time.RFC3339))
+	assert.Equal("2019-11-04T01:30:00-05:00", schedule.Next(next).Format(time.RFC3339))
+
+	schedule, err = ParseString("CRON_TZ=America/New_York 0 */30 * * * *")
+	assert.Nil(err)
+
+	var runs []string
+	for next = time.Date(2019, 03, 10, 5, 0, 0, 0, time.UTC); len(runs) < 4; {
+		next = schedule.Next(next)
+		runs = append(runs, next.Format(time.RFC3339))
+	}
+	assert.Equal([]string{
+		"2019-03-10T00:30:00-05:00",
+		"2019-03-10T01:00:00-05:00",
+		"2019-03-10T01:30:00-05:00",
+		"2019-03-10T03:00:00-04:00",
+	}, runs)
+
+	runs = nil
+	for next = time.Date(2019, 11, 03, 4, 0, 0, 0, time.UTC); len(runs) < 5; {
+		next = schedule.Next(next)
+		runs = append(runs, next.Format(time.RFC3339))
+	}
+	assert.Equal([]string{
+		"2019-11-03T00:30:00-04:00",
+		"2019-11-03T01:00:00-04:00",
+		"2019-11-03T01:30:00-04:00",
+		"2019-11-03T02:00:00-05:00",
+		"2019-11-03T02:30:00-05:00",
+	}, runs)
+
+	// starting within the repeated hour skips wall clock times that already occurred.
+	assert.Equal("2019-11-03T02:00:00-05:00", schedule.Next(time.Date(2019, 11, 03, 6, 10, 0, 0, time.UTC)).Format(time.RFC3339))
+}
+
+func TestStringScheduleEverySeconds(t *testing.T) {
+	assert := assert.New(t)
+
+	schedule, err := ParseString("30 * * * * * *")
+	assert.Nil(err)
+	assert.Equal(time.Date(2019, 01, 02, 12, 1, 30, 0, time.UTC), schedule.Next(time.Date(2019, 01, 02, 12, 0, 45, 0, time.UTC)))
+}
+
+func TestStringScheduleSecondsNextMinute(t *testing.T) {
+	assert := assert.New(t)
+
+	// once the seconds for the current minute have passed, the first second is used in the next minute.
+	schedule, err := ParseString("5,35 * * * * * *")
+	assert.Nil(err)
+	assert.Equal(time.Date(2019, 01, 02, 12, 1, 5, 0, time.UTC), schedule.Next(time.Date(2019, 01, 02, 12, 0, 50, 0, time.UTC)))
+}
+
 func TestStringScheduleEvery(t *testing.T) {
 	assert := assert.New(t)
 
@@ -95,3 +95,65 @@ func IsWeekDay(day time.Weekday) bool {
 func IsWeekendDay(day time.Weekday) bool {
 	return day == time.Saturday || day == time.Sunday
 }
+
+// isClosed returns if a signal channel is closed; nil channels are never closed.
+func isClosed(signal <-chan struct{}) bool {
+	if signal == nil {
+		return false
+	}
+	select {
+	case <-signal:
+		return true
+	default:
+		return false
+	}
+}
+
+// InLocation returns the instant a wall clock time occurs in a location.
+//
+// The year, month, day and clock of `wall` are used, and its location is ignored.
+// Wall clock times repeated when clocks are set back for daylight saving time
+// resolve to their first occurrence, and wall clock times skipped when clocks are
+// set forward are shifted forward by the length of the skipped interval, so that
+// 02:30 on a day that skips from 02:00 to 03:00 resolves to 03:30.
+func InLocation(wall time.Time, location *time.Location) time.Time {
+	if location == nil {
+		location = time.UTC
+	}
+	wall = time.Date(wall.Year(), wall.Month(), wall.Day(), wall.Hour(), wall.Minute(), wall.Second(), wall.Nanosecond(), time.UTC)
+
+	// offsets are sampled a day either side, which spans any single transition.
+	_, offsetBefore := wall.Add(-24 * time.Hour).In(location).Zone()
+	_, offsetAfter := wall.Add(24 * time.Hour).In(location).Zone()
+
+	var output time.Time
+	for _, offset := range []int{offsetBefore, offsetAfter} {
+		candidate := wall.Add(-time.Duration(offset) * time.Second).In(location)
+		if !isSameWallClock(candidate, wall) {
+			continue
+		}
+		if output.IsZero() || candidate.Before(output) {
+			output = candidate
+		}
+	}
+	if output.IsZero() {
+		output = wall.Add(-time.Duration(offsetBefore) * time.Second).In(location)
+	}
+	return output
+}
+
+// isSameWallClock returns if two times have the same date and clock, regardless of location.
+func isSameWallClock(t1, t2 time.Time) bool {
+	y1, m1, d1 := t1.Date()
+	y2, m2, d2 := t2.Date()
+	return y1 == y2 && m1 == m2 && d1 == d2 &&
+		t1.Hour() == t2.Hour() && t1.Minute() == t2.Minute() && t1.Second() == t2.Second() &&
+		t1.Nanosecond() == t2.Nanosecond()
+}
+
+// wallClock returns the date and clock of a time in a location as a UTC time,
+// which is free of daylight saving time transitions.
+func wallClock(t time.Time, location *time.Location) time.Time {
+	local := t.In(location)
+	return time.Date(local.Year(), local.Month(), local.Day(), local.Hour(), local.Minute(), local.Second(), local.Nanosecond(), time.UTC)
+}
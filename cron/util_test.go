@@ -22,3 +22,32 @@ func TestMinMax(t *testing.T) {
 	assert.Equal(a, Max(a, b))
 	assert.Equal(a, Max(b, a))
 }
+
+func TestInLocation(t *testing.T) {
+	assert := assert.New(t)
+
+	newYork, err := time.LoadLocation("America/New_York")
+	assert.Nil(err)
+	sydney, err := time.LoadLocation("Australia/Sydney")
+	assert.Nil(err)
+
+	testCases := [...]struct {
+		Wall     time.Time
+		Location *time.Location
+		Expected string
+	}{
+		{Wall: time.Date(2019, 01, 02, 12, 30, 0, 0, time.UTC), Location: nil, Expected: "2019-01-02T12:30:00Z"},
+		{Wall: time.Date(2019, 01, 02, 12, 30, 0, 0, time.UTC), Location: newYork, Expected: "2019-01-02T12:30:00-05:00"},
+		{Wall: time.Date(2019, 07, 02, 12, 30, 0, 0, time.UTC), Location: newYork, Expected: "2019-07-02T12:30:00-04:00"},
+		// skipped when clocks are set forward.
+		{Wall: time.Date(2019, 03, 10, 2, 30, 0, 0, time.UTC), Location: newYork, Expected: "2019-03-10T03:30:00-04:00"},
+		{Wall: time.Date(2019, 10, 06, 2, 30, 0, 0, time.UTC), Location: sydney, Expected: "2019-10-06T03:30:00+11:00"},
+		// repeated when clocks are set back.
+		{Wall: time.Date(2019, 11, 03, 1, 30, 0, 0, time.UTC), Location: newYork, Expected: "2019-11-03T01:30:00-04:00"},
+		{Wall: time.Date(2019, 04, 07, 2, 30, 0, 0, time.UTC), Location: sydney, Expected: "2019-04-07T02:30:00+11:00"},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(tc.Expected, InLocation(tc.Wall, tc.Location).Format(time.RFC3339))
+	}
+}
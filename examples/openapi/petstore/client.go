@@ -0,0 +1,223 @@
+// Code generated by apiclient; DO NOT EDIT.
+
+// Package petstore is a client for the Swagger Petstore api.
+package petstore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/r2"
+)
+
+// New returns a new client for a given base url.
+// The default options are applied to every request the client makes.
+func New(baseURL string, defaults ...r2.Option) *Client {
+	return &Client{
+		BaseURL:  baseURL,
+		Defaults: defaults,
+	}
+}
+
+// Client is an api client.
+type Client struct {
+	BaseURL  string
+	Defaults r2.Defaults
+}
+
+// APIError is returned if the api responds with a non-2xx status code.
+// Value is set to the typed error response if the spec declares one for the status code.
+type APIError struct {
+	StatusCode int
+	Body       []byte
+	Value      interface{}
+}
+
+// Error implements error.
+func (e *APIError) Error() string {
+	return fmt.Sprintf("api error; status code: %d; body: %s", e.StatusCode, string(e.Body))
+}
+
+// request returns a new request with the client defaults, the given options and the per call options.
+func (c *Client) request(ctx context.Context, path string, options []r2.Option, callOptions []r2.Option) *r2.Request {
+	prefix := ""
+	if parsed, err := url.Parse(c.BaseURL); err == nil {
+		prefix = strings.TrimSuffix(parsed.EscapedPath(), "/")
+	}
+	all := append(r2.Defaults{}, c.Defaults...)
+	all = append(all, r2.OptContext(ctx), r2.OptEscapedPath(prefix+path))
+	all = append(all, options...)
+	all = append(all, callOptions...)
+	return r2.New(c.BaseURL, all...)
+}
+
+// send sends a request and decodes the response into the output, or returns an *APIError.
+func (c *Client) send(req *r2.Request, output interface{}, errorOutput func(int) interface{}) error {
+	var raw json.RawMessage
+	res, err := req.JSONWithResponse(&raw)
+	if res == nil {
+		return err
+	}
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		apiErr := &APIError{StatusCode: res.StatusCode, Body: []byte(raw)}
+		if errorOutput != nil {
+			if value := errorOutput(res.StatusCode); value != nil && len(raw) > 0 && json.Unmarshal(raw, value) == nil {
+				apiErr.Value = value
+			}
+		}
+		return apiErr
+	}
+	if err != nil && !ex.Is(err, io.EOF) {
+		return err
+	}
+	if output == nil || len(raw) == 0 {
+		return nil
+	}
+	return ex.New(json.Unmarshal(raw, output))
+}
+
+// Error is generated from the api spec.
+type Error struct {
+	Code    int32  `json:"code"`
+	Message string `json:"message"`
+}
+
+// NewPet is generated from the api spec.
+type NewPet struct {
+	Name string  `json:"name"`
+	Tag  *string `json:"tag,omitempty"`
+}
+
+// PetOwner is generated from the api spec.
+type PetOwner struct {
+	Name *string `json:"name,omitempty"`
+}
+
+// Pet is generated from the api spec.
+//
+// A pet in the store.
+type Pet struct {
+	BornUTC *time.Time `json:"bornUTC,omitempty"`
+	ID      int64      `json:"id"`
+	Name    string     `json:"name"`
+	Owner   *PetOwner  `json:"owner,omitempty"`
+	Status  *PetStatus `json:"status,omitempty"`
+	Tag     *string    `json:"tag,omitempty"`
+}
+
+// PetStatus is generated from the api spec.
+type PetStatus string
+
+// PetStatus values.
+const (
+	PetStatusAvailable PetStatus = "available"
+	PetStatusSold      PetStatus = "sold"
+)
+
+// Pets is generated from the api spec.
+type Pets []Pet
+
+// ListPetsParams are the query and header parameters for `ListPets`.
+type ListPetsParams struct {
+	// How many items to return at one time (max 100)
+	Limit      *int32
+	Tags       []string
+	XRequestID *string
+}
+
+// ShowPetByID404Error is generated from the api spec.
+type ShowPetByID404Error struct {
+	Message *string `json:"message,omitempty"`
+	PetID   *string `json:"petId,omitempty"`
+}
+
+// PutOwnersByOwnerIDPetsByPetIDTags calls `PUT /owners/{ownerId}/pets/{petId}/tags`.
+func (c *Client) PutOwnersByOwnerIDPetsByPetIDTags(ctx context.Context, ownerID string, petID int64, body map[string]string, options ...r2.Option) (map[string]string, error) {
+	path := fmt.Sprintf("/owners/%s/pets/%s/tags", url.PathEscape(fmt.Sprint(ownerID)), url.PathEscape(fmt.Sprint(petID)))
+	requestOptions := []r2.Option{r2.OptMethod("PUT")}
+	requestOptions = append(requestOptions, r2.OptJSONBody(body))
+	req := c.request(ctx, path, requestOptions, options)
+	var output map[string]string
+	if err := c.send(req, &output, nil); err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+// ListPets calls `GET /pets`.
+//
+// List all pets
+func (c *Client) ListPets(ctx context.Context, params ListPetsParams, options ...r2.Option) (Pets, error) {
+	path := "/pets"
+	requestOptions := []r2.Option{r2.OptMethod("GET")}
+	query := url.Values{}
+	if params.Limit != nil {
+		query.Add("limit", fmt.Sprint(*params.Limit))
+	}
+	for _, value := range params.Tags {
+		query.Add("tags", fmt.Sprint(value))
+	}
+	if params.XRequestID != nil {
+		requestOptions = append(requestOptions, r2.OptHeaderValue("X-Request-ID", fmt.Sprint(*params.XRequestID)))
+	}
+	requestOptions = append(requestOptions, r2.OptQuery(query))
+	req := c.request(ctx, path, requestOptions, options)
+	var output Pets
+	if err := c.send(req, &output, func(statusCode int) interface{} {
+		return new(Error)
+	}); err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+// CreatePets calls `POST /pets`.
+//
+// Create a pet
+func (c *Client) CreatePets(ctx context.Context, body NewPet, options ...r2.Option) error {
+	path := "/pets"
+	requestOptions := []r2.Option{r2.OptMethod("POST")}
+	requestOptions = append(requestOptions, r2.OptJSONBody(body))
+	req := c.request(ctx, path, requestOptions, options)
+	return c.send(req, nil, func(statusCode int) interface{} {
+		switch {
+		case statusCode >= 400 && statusCode < 500:
+			return new(Error)
+		}
+		return nil
+	})
+}
+
+// ShowPetByID calls `GET /pets/{petId}`.
+//
+// Info for a specific pet
+func (c *Client) ShowPetByID(ctx context.Context, petID string, options ...r2.Option) (*Pet, error) {
+	path := fmt.Sprintf("/pets/%s", url.PathEscape(fmt.Sprint(petID)))
+	requestOptions := []r2.Option{r2.OptMethod("GET")}
+	req := c.request(ctx, path, requestOptions, options)
+	var output Pet
+	if err := c.send(req, &output, func(statusCode int) interface{} {
+		switch {
+		case statusCode == 404:
+			return new(ShowPetByID404Error)
+		}
+		return new(Error)
+	}); err != nil {
+		return nil, err
+	}
+	return &output, nil
+}
+
+// DeletePet calls `DELETE /pets/{petId}`.
+func (c *Client) DeletePet(ctx context.Context, petID string, options ...r2.Option) error {
+	path := fmt.Sprintf("/pets/%s", url.PathEscape(fmt.Sprint(petID)))
+	requestOptions := []r2.Option{r2.OptMethod("DELETE")}
+	req := c.request(ctx, path, requestOptions, options)
+	return c.send(req, nil, nil)
+}
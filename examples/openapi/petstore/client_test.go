@@ -0,0 +1,39 @@
+package petstore
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestClientPathRoundTrip(t *testing.T) {
+	assert := assert.New(t)
+
+	var path, escapedPath string
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		path = req.URL.Path
+		escapedPath = req.URL.EscapedPath()
+		rw.Header().Set("Content-Type", "application/json")
+		if req.Method == http.MethodGet {
+			rw.Write([]byte(`{"id":1,"name":"a b/c"}`))
+			return
+		}
+		rw.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := New(server.URL + "/api/")
+	pet, err := client.ShowPetByID(context.Background(), "a b/c")
+	assert.Nil(err)
+	assert.Equal("a b/c", pet.Name)
+	assert.Equal("/api/pets/a b/c", path)
+	assert.Equal("/api/pets/a%20b%2Fc", escapedPath)
+
+	_, err = client.PutOwnersByOwnerIDPetsByPetIDTags(context.Background(), "100%", 2, map[string]string{})
+	assert.Nil(err)
+	assert.Equal("/api/owners/100%/pets/2/tags", path)
+	assert.Equal("/api/owners/100%25/pets/2/tags", escapedPath)
+}
@@ -0,0 +1,6 @@
+/*
+Package petstore is an example client generated by `cmd/apiclient` from `openapi/testdata/petstore.yml`.
+*/
+package petstore
+
+//go:generate go run ../../../cmd/apiclient/main.go -f ../../../openapi/testdata/petstore.yml -package petstore -o client.go
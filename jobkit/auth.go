@@ -0,0 +1,154 @@
+package jobkit
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/blend/go-sdk/env"
+	"github.com/blend/go-sdk/web"
+)
+
+// AuthConfig is the management server auth config.
+//
+// Requests are authorized if they pass any of the configured methods.
+// If no methods are configured, the management server does not authenticate requests.
+type AuthConfig struct {
+	// Username and Password enable http basic auth.
+	Username string `json:"username,omitempty" yaml:"username,omitempty" env:"JOBKIT_AUTH_USERNAME"`
+	Password string `json:"password,omitempty" yaml:"password,omitempty" env:"JOBKIT_AUTH_PASSWORD"`
+	// Tokens are bearer tokens, sent as `Authorization: Bearer <token>`.
+	Tokens []string `json:"tokens,omitempty" yaml:"tokens,omitempty" env:"JOBKIT_AUTH_TOKENS,csv"`
+	// Session authorizes requests with a session from the app auth manager, set with `web.OptAuth`.
+	// Unauthorized page requests are redirected to the auth manager login redirect.
+	Session bool `json:"session,omitempty" yaml:"session,omitempty" env:"JOBKIT_AUTH_SESSION"`
+}
+
+// Resolve includes extra steps on configutil.Read(...).
+func (ac *AuthConfig) Resolve() error {
+	return env.Env().ReadInto(ac)
+}
+
+// IsZero returns if no auth methods are configured.
+func (ac AuthConfig) IsZero() bool {
+	return !ac.HasBasic() && len(ac.Tokens) == 0 && !ac.Session
+}
+
+// HasBasic returns if http basic auth is configured.
+func (ac AuthConfig) HasBasic() bool {
+	return ac.Username != "" && ac.Password != ""
+}
+
+// AuthMiddleware returns a middleware that authorizes management server requests with an auth config.
+//
+// Unauthorized requests to paths under `/api/` get a json 401 response; page requests are redirected
+// to the session login if sessions are enabled, or get a basic auth challenge otherwise.
+//
+// Browsers send basic auth credentials and session cookies with cross site requests, so requests
+// authorized with them that change state (i.e. are not `GET`, `HEAD` or `OPTIONS` requests) also need
+// a csrf token, sent as the `csrf_token` form value or the `X-CSRF-Token` header, that matches the
+// `jobkit_csrf` cookie. Requests authorized with a bearer token do not need a csrf token.
+func AuthMiddleware(cfg AuthConfig) web.Middleware {
+	return func(action web.Action) web.Action {
+		if cfg.IsZero() {
+			return action
+		}
+		return func(r *web.Ctx) web.Result {
+			if token := bearerToken(r.Request); token != "" {
+				for _, expected := range cfg.Tokens {
+					if secureEqual(token, expected) {
+						return action(r)
+					}
+				}
+			}
+			if cfg.HasBasic() {
+				if username, password, ok := r.Request.BasicAuth(); ok && secureEqual(username, cfg.Username) && secureEqual(password, cfg.Password) {
+					return csrfProtected(r, action)
+				}
+			}
+			if cfg.Session && r.App != nil {
+				session, err := r.App.Auth.VerifySession(r)
+				if err != nil && !web.IsErrSessionInvalid(err) {
+					return r.DefaultProvider.InternalError(err)
+				}
+				if session != nil {
+					r.Session = session
+					return csrfProtected(r, action)
+				}
+			}
+
+			if strings.HasPrefix(r.Request.URL.Path, "/api/") {
+				return web.JSON.NotAuthorized()
+			}
+			if cfg.Session && r.App != nil {
+				return r.App.Auth.LoginRedirect(r)
+			}
+			if cfg.HasBasic() {
+				r.Response.Header().Set("WWW-Authenticate", `Basic realm="jobkit"`)
+			}
+			return r.Views.NotAuthorized()
+		}
+	}
+}
+
+// csrfProtected runs an action for a request authorized by basic auth or a session, if the request
+// does not change state or has a csrf token that matches the csrf cookie.
+//
+// The csrf cookie is set if the request does not have one, and the token is set on the request
+// state as `StateKeyCSRFToken` so views can add it to forms.
+func csrfProtected(r *web.Ctx, action web.Action) web.Result {
+	var token string
+	if cookie := r.Cookie(CSRFCookieName); cookie != nil && cookie.Value != "" {
+		token = cookie.Value
+	}
+
+	switch r.Request.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+	default:
+		if token == "" || !secureEqual(csrfRequestToken(r), token) {
+			if strings.HasPrefix(r.Request.URL.Path, "/api/") {
+				return web.JSON.Forbidden()
+			}
+			return r.Views.Status(http.StatusForbidden, "invalid csrf token")
+		}
+	}
+
+	if token == "" {
+		token = web.NewSessionID()
+		// the cookie is host only, so it is not sent to other subdomains.
+		http.SetCookie(r.Response, &http.Cookie{
+			Name:     CSRFCookieName,
+			Value:    token,
+			Path:     "/",
+			Expires:  time.Now().UTC().AddDate(1, 0, 0),
+			HttpOnly: true,
+			Secure:   r.App != nil && r.App.Config.CookieSecureOrDefault(),
+			SameSite: http.SameSiteStrictMode,
+		})
+	}
+	r.WithStateValue(StateKeyCSRFToken, token)
+	return action(r)
+}
+
+// csrfRequestToken returns the csrf token sent with a request, from the csrf header or form value.
+func csrfRequestToken(r *web.Ctx) string {
+	if token := r.Request.Header.Get(HeaderCSRFToken); token != "" {
+		return token
+	}
+	return web.StringValue(r.FormValue(CSRFFormValue))
+}
+
+// bearerToken returns the bearer token from a request's authorization header.
+func bearerToken(req *http.Request) string {
+	header := req.Header.Get("Authorization")
+	if len(header) > len("Bearer ") && strings.EqualFold(header[:len("Bearer ")], "Bearer ") {
+		return strings.TrimSpace(header[len("Bearer "):])
+	}
+	return ""
+}
+
+// secureEqual compares strings in constant time.
+func secureEqual(actual, expected string) bool {
+	return subtle.ConstantTimeCompare([]byte(actual), []byte(expected)) == 1
+}
@@ -0,0 +1,192 @@
+package jobkit
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/cron"
+	"github.com/blend/go-sdk/r2"
+	"github.com/blend/go-sdk/web"
+)
+
+func TestAuthConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True(AuthConfig{}.IsZero())
+	assert.True(AuthConfig{Username: "admin"}.IsZero(), "basic auth needs a password")
+	assert.False(AuthConfig{Username: "admin", Password: "hunter2"}.IsZero())
+	assert.False(AuthConfig{Tokens: []string{"token"}}.IsZero())
+	assert.False(AuthConfig{Session: true}.IsZero())
+}
+
+func TestManagementServerAuth(t *testing.T) {
+	assert := assert.New(t)
+
+	jm := cron.New()
+	assert.Nil(jm.LoadJobs(cron.NewJob("test0", func(_ context.Context) error { return nil })))
+	app := NewManagementServer(jm, Config{
+		Web: web.Config{
+			Port: 5000,
+		},
+		Auth: AuthConfig{
+			Username: "admin",
+			Password: "hunter2",
+			Tokens:   []string{"token-0", "token-1"},
+		},
+	})
+
+	meta, err := web.MockGet(app, "/api/v1/jobs").DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusUnauthorized, meta.StatusCode)
+
+	meta, err = web.MockGet(app, "/").DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusUnauthorized, meta.StatusCode)
+	assert.Equal(`Basic realm="jobkit"`, meta.Header.Get("WWW-Authenticate"))
+
+	meta, err = web.MockGet(app, "/api/v1/jobs", r2.OptBasicAuth("admin", "not-the-password")).DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusUnauthorized, meta.StatusCode)
+
+	meta, err = web.MockGet(app, "/api/v1/jobs", r2.OptBasicAuth("admin", "hunter2")).DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, meta.StatusCode)
+
+	meta, err = web.MockGet(app, "/api/v1/jobs", r2.OptHeaderValue("Authorization", "Bearer token-1")).DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, meta.StatusCode)
+
+	meta, err = web.MockPost(app, "/api/v1/jobs/test0/disable", nil, r2.OptHeaderValue("Authorization", "Bearer token-2")).DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusUnauthorized, meta.StatusCode)
+	assert.False(jm.IsJobDisabled("test0"))
+
+	// health checks do not need credentials.
+	meta, err = web.MockGet(app, "/livez").DiscardWithResponse()
+	assert.Nil(err)
+	assert.NotEqual(http.StatusUnauthorized, meta.StatusCode)
+}
+
+func TestManagementServerAuthSession(t *testing.T) {
+	assert := assert.New(t)
+
+	cache := web.NewLocalSessionCache()
+	cache.Upsert(&web.Session{SessionID: "session-id", UserID: "admin"})
+
+	jm := cron.New()
+	app := NewManagementServer(jm, Config{
+		Web: web.Config{
+			Port: 5000,
+		},
+		Auth: AuthConfig{
+			Session: true,
+		},
+	}, web.OptAuth(web.NewLocalAuthManagerFromCache(cache)))
+
+	meta, err := web.MockGet(app, "/api/v1/jobs").DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusUnauthorized, meta.StatusCode)
+
+	meta, err = web.MockGet(app, "/api/v1/jobs", r2.OptCookieValue(web.DefaultCookieName, "session-id")).DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, meta.StatusCode)
+}
+
+func csrfCookie(res *http.Response) *http.Cookie {
+	for _, cookie := range res.Cookies() {
+		if cookie.Name == CSRFCookieName {
+			return cookie
+		}
+	}
+	return nil
+}
+
+func TestManagementServerAuthSessionCSRF(t *testing.T) {
+	assert := assert.New(t)
+
+	cache := web.NewLocalSessionCache()
+	cache.Upsert(&web.Session{SessionID: "session-id", UserID: "admin"})
+
+	jm := cron.New()
+	assert.Nil(jm.LoadJobs(cron.NewJob("test0", func(_ context.Context) error { return nil })))
+	app := NewManagementServer(jm, Config{
+		Web: web.Config{
+			Port: 5000,
+		},
+		Auth: AuthConfig{
+			Session: true,
+		},
+	}, web.OptAuth(web.NewLocalAuthManagerFromCache(cache)))
+	session := r2.OptCookieValue(web.DefaultCookieName, "session-id")
+
+	// form posts without a csrf token are forbidden.
+	meta, err := web.MockPost(app, "/job.disable/test0", nil, session, r2.OptNoFollow()).DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusForbidden, meta.StatusCode)
+	assert.False(jm.IsJobDisabled("test0"))
+
+	// pages set the csrf cookie and add the token to their forms.
+	contents, meta, err := web.MockGet(app, "/", session).BytesWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, meta.StatusCode)
+	cookie := csrfCookie(meta)
+	assert.NotNil(cookie)
+	assert.True(cookie.HttpOnly)
+	assert.Contains(string(contents), `name="csrf_token" value="`+cookie.Value+`"`)
+
+	meta, err = web.MockPost(app, "/job.disable/test0", nil, session, r2.OptCookieValue(CSRFCookieName, "not-the-token"), r2.OptPostFormValue(CSRFFormValue, cookie.Value), r2.OptNoFollow()).DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusForbidden, meta.StatusCode)
+	assert.False(jm.IsJobDisabled("test0"))
+
+	meta, err = web.MockPost(app, "/job.disable/test0", nil, session, r2.OptCookieValue(CSRFCookieName, cookie.Value), r2.OptPostFormValue(CSRFFormValue, cookie.Value), r2.OptNoFollow()).DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusFound, meta.StatusCode)
+	assert.True(jm.IsJobDisabled("test0"))
+
+	// api requests can send the token in a header.
+	meta, err = web.MockPost(app, "/api/v1/jobs/test0/enable", nil, session).DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusForbidden, meta.StatusCode)
+	assert.True(jm.IsJobDisabled("test0"))
+
+	meta, err = web.MockPost(app, "/api/v1/jobs/test0/enable", nil, session, r2.OptCookieValue(CSRFCookieName, cookie.Value), r2.OptHeaderValue(HeaderCSRFToken, cookie.Value)).DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, meta.StatusCode)
+	assert.False(jm.IsJobDisabled("test0"))
+}
+
+func TestManagementServerAuthBasicCSRF(t *testing.T) {
+	assert := assert.New(t)
+
+	jm := cron.New()
+	assert.Nil(jm.LoadJobs(cron.NewJob("test0", func(_ context.Context) error { return nil })))
+	app := NewManagementServer(jm, Config{
+		Web: web.Config{
+			Port: 5000,
+		},
+		Auth: AuthConfig{
+			Username: "admin",
+			Password: "hunter2",
+			Tokens:   []string{"token-0"},
+		},
+	})
+
+	meta, err := web.MockPost(app, "/api/v1/jobs/test0/disable", nil, r2.OptBasicAuth("admin", "hunter2")).DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusForbidden, meta.StatusCode)
+	assert.False(jm.IsJobDisabled("test0"))
+
+	meta, err = web.MockPost(app, "/api/v1/jobs/test0/disable", nil, r2.OptBasicAuth("admin", "hunter2"), r2.OptCookieValue(CSRFCookieName, "token"), r2.OptHeaderValue(HeaderCSRFToken, "token")).DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, meta.StatusCode)
+	assert.True(jm.IsJobDisabled("test0"))
+
+	// bearer tokens are not sent by browsers on their own, so they do not need a csrf token.
+	meta, err = web.MockPost(app, "/api/v1/jobs/test0/enable", nil, r2.OptHeaderValue("Authorization", "Bearer token-0")).DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, meta.StatusCode)
+	assert.False(jm.IsJobDisabled("test0"))
+}
@@ -15,9 +15,11 @@ import (
 // Config is the jobkit config.
 type Config struct {
 	MaxLogBytes int             `yaml:"maxLogBytes"`
+	HistoryPath string          `yaml:"historyPath"`
 	Cron        cron.Config     `yaml:"cron"`
 	Logger      logger.Config   `yaml:"logger"`
 	Web         web.Config      `yaml:"web"`
+	Auth        AuthConfig      `yaml:"auth"`
 	Airbrake    airbrake.Config `yaml:"airbrake"`
 	AWS         aws.Config      `yaml:"aws"`
 	Email       email.Message   `yaml:"email"`
@@ -31,6 +33,7 @@ func (c *Config) Resolve() error {
 		c.Cron.Resolve(),
 		c.Logger.Resolve(),
 		c.Web.Resolve(),
+		c.Auth.Resolve(),
 		c.Airbrake.Resolve(),
 		c.AWS.Resolve(),
 		c.Email.Resolve(),
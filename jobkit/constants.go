@@ -1,6 +1,26 @@
 package jobkit
 
+import "time"
+
 // Constants and Defaults
 const (
 	DefaultMaxLogBytes = 10 * (1 << 10)
 )
+
+// DefaultHistoryPageSize is the default number of invocations shown per page of job history.
+const DefaultHistoryPageSize = 25
+
+// DefaultOutputStreamInterval is the default interval between checks for new output when streaming the output of running invocations.
+const DefaultOutputStreamInterval = 500 * time.Millisecond
+
+// CSRF constants for requests authorized by basic auth or a session.
+const (
+	// CSRFCookieName is the name of the cookie that holds the csrf token.
+	CSRFCookieName = "jobkit_csrf"
+	// CSRFFormValue is the form value that state changing form posts send the csrf token in.
+	CSRFFormValue = "csrf_token"
+	// HeaderCSRFToken is the header that state changing requests can send the csrf token in.
+	HeaderCSRFToken = "X-CSRF-Token"
+	// StateKeyCSRFToken is the request state key views read the csrf token from.
+	StateKeyCSRFToken = "jobkit.csrf_token"
+)
@@ -0,0 +1,33 @@
+package jobkit
+
+var graphTemplate = `
+{{ define "graph" }}
+{{ template "header" . }}
+<div class="container">
+	<ul class="breadcrumbs">
+		<li><a href="/">Jobs</a></li>
+		<li>Graph</li>
+	</ul>
+	<div class="graph">
+	{{ range $level, $nodes := .ViewModel.Levels }}
+		<div class="graph-level">
+		{{ range $index, $node := $nodes }}
+			<div id="{{ $node.Name }}" class="graph-node {{ $node.Status }}{{ if $node.Disabled }} disabled{{ end }}">
+				<a href="/job.history/{{ $node.Name }}">{{ $node.Name }}</a>
+				{{ if $node.Dependencies }}
+				<div class="small-text">after {{ range $dependencyIndex, $dependency := $node.Dependencies }}{{ if $dependencyIndex }}, {{ end }}<a href="#{{ $dependency }}">{{ $dependency }}</a>{{ end }}</div>
+				{{ end }}
+				{{ if $node.Dependents }}
+				<div class="small-text">before {{ range $dependentIndex, $dependent := $node.Dependents }}{{ if $dependentIndex }}, {{ end }}<a href="#{{ $dependent }}">{{ $dependent }}</a>{{ end }}</div>
+				{{ end }}
+			</div>
+		{{ end }}
+		</div>
+	{{ else }}
+		<h4>No Jobs Loaded</h4>
+	{{ end }}
+	</div>
+</div>
+{{ template "footer" . }}
+{{ end }}
+`
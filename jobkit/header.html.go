@@ -472,8 +472,39 @@ var headerTemplate = `
 			margin-left: 0;
 			content: none;
 		}
+
+		.graph {
+			display: flex;
+			flex-direction: row;
+			align-items: flex-start;
+			overflow-x: auto;
+			font-size: 12px;
+		}
+		.graph-level {
+			display: flex;
+			flex-direction: column;
+			margin-right: 40px;
+		}
+		.graph-node {
+			border: 1px solid #efefef;
+			margin: 10px 0;
+			padding: 10px;
+			min-width: 150px;
+		}
+		.graph-node.failed {
+			background-color: #F55656
+		}
+		.graph-node.cancelled {
+			background-color: #FFB366;
+		}
+		.graph-node.disabled {
+			opacity: 0.5;
+		}
 	</style>
 </head>
 <body>
 {{ end }}
+{{ define "csrf" }}
+{{ with .Ctx.StateValue "jobkit.csrf_token" }}<input type="hidden" name="csrf_token" value="{{ . }}" />{{ end }}
+{{ end }}
 `
@@ -0,0 +1,19 @@
+package jobkit
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/blend/go-sdk/cron"
+	"github.com/blend/go-sdk/web"
+)
+
+// HealthCheckJobManager returns a health check func that fails if the job manager is not running.
+func HealthCheckJobManager(jm *cron.JobManager) web.HealthCheckFunc {
+	return func(_ context.Context) error {
+		if jm.IsStarted() {
+			return nil
+		}
+		return fmt.Errorf("job manager is stopped or in an inconsistent state")
+	}
+}
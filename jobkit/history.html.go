@@ -0,0 +1,53 @@
+package jobkit
+
+var historyTemplate = `
+{{ define "history" }}
+{{ template "header" . }}
+<div class="container">
+	<ul class="breadcrumbs">
+		<li><a href="/">Jobs</a></li>
+		<li>{{ .ViewModel.JobName }}</li>
+		<li>History</li>
+	</ul>
+	<table class="u-full-width">
+		<thead>
+			<tr>
+				<th>Invocation</th>
+				<th>Started</th>
+				<th>Finished</th>
+				<th>Timeout</th>
+				<th>Cancelled</th>
+				<th>Elapsed</th>
+				<th>Error</th>
+			</tr>
+		</thead>
+		<tbody>
+		{{ range $index, $ji := .ViewModel.History }}
+		<tr class="{{ if $ji.Status | eq "failed" }}failed{{ else if $ji.Status | eq "cancelled"}}cancelled{{else}}ok{{end}}">
+			<td><a href="/job.invocation/{{$ji.JobName}}/{{ $ji.ID }}">{{ $ji.ID }}</a></td>
+			<td>{{ $ji.Started | rfc3339 }}</td>
+			<td>{{ if $ji.Finished.IsZero }}-{{ else }}{{ $ji.Finished | rfc3339 }}{{ end }}</td>
+			<td>{{ if $ji.Timeout.IsZero }}-{{ else }}{{ $ji.Timeout | rfc3339 }}{{ end }}</td>
+			<td>{{ if $ji.Cancelled.IsZero }}-{{ else }}{{ $ji.Cancelled | rfc3339 }}{{ end }}</td>
+			<td>{{ $ji.Elapsed }}</td>
+			<td>{{ if $ji.Err }}<code>{{ $ji.Err }}</code>{{ else }}-{{end}}</td>
+		</tr>
+		{{ else }}
+		<tr>
+			<td colspan=7>No History</td>
+		</tr>
+		{{ end }}
+		</tbody>
+	</table>
+	<div class="pagination">
+		{{ if .ViewModel.HasPrevious }}
+		<a class="button" href="/job.history/{{ .ViewModel.JobName }}?offset={{ .ViewModel.PreviousOffset }}&limit={{ .ViewModel.Limit }}">Newer</a>
+		{{ end }}
+		{{ if .ViewModel.HasNext }}
+		<a class="button" href="/job.history/{{ .ViewModel.JobName }}?offset={{ .ViewModel.NextOffset }}&limit={{ .ViewModel.Limit }}">Older</a>
+		{{ end }}
+	</div>
+</div>
+{{ template "footer" . }}
+{{ end }}
+`
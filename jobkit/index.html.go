@@ -4,6 +4,10 @@ var indexTemplate = `
 {{ define "index" }}
 {{ template "header" . }}
 <div class="container">
+		<ul class="breadcrumbs">
+			<li>Jobs</li>
+			<li><a href="/graph">Graph</a></li>
+		</ul>
 		{{ range $index, $job := .ViewModel.Jobs }}
 		<table class="job u-full-width">
 			<thead>
@@ -68,19 +72,25 @@ var indexTemplate = `
 					<td> <!-- actions -->
 					{{ if $job.Disabled }}
 						<form method="POST" action="/job.enable/{{ $job.Name }}">
+							{{ template "csrf" $ }}
 							<input type="submit" class="button" value="Enable" />
 						</form>
 					{{else}}
 						<form method="POST" action="/job.disable/{{ $job.Name }}">
+							{{ template "csrf" $ }}
 							<input type="submit" class="button" value="Disable" />
 						</form>
 					{{end}}
 					{{ if $job.Current }}
 					<form method="POST" action="/job.cancel/{{ $job.Name }}">
+						{{ template "csrf" $ }}
 						<input type="submit" class="button button-danger" value="Cancel" />
 					</form>
+					{{else if $job.ParameterSchema}}
+					<a class="button button-primary" href="/job.run/{{ $job.Name }}">Run</a>
 					{{else}}
 					<form method="POST" action="/job.run/{{ $job.Name }}">
+						{{ template "csrf" $ }}
 						<input type="submit" class="button button-primary" value="Run" />
 					</form>
 					{{end}}
@@ -96,7 +106,7 @@ var indexTemplate = `
 				{{ end }}
 				<tr>
 					<td colspan=8>
-						<h4>History</h4>
+						<h4>History <small><a href="/job.history/{{ $job.Name }}">All</a></small></h4>
 						<table class="u-full-width">
 							<thead>
 								<tr>
@@ -31,6 +31,24 @@ var invocationTemplate = `
 			</tr>
 		</tbody>
 	</table>
+	{{ if .ViewModel.Parameters }}
+	<table class="u-full-width">
+		<thead>
+			<tr>
+				<th>Parameter</th>
+				<th>Value</th>
+			</tr>
+		</thead>
+		<tbody>
+		{{ range $name, $value := .ViewModel.Parameters }}
+			<tr>
+				<td>{{ $name }}</td>
+				<td><code>{{ $value }}</code></td>
+			</tr>
+		{{ end }}
+		</tbody>
+	</table>
+	{{ end }}
 	{{ if .ViewModel.Err }}
 	<table class="u-full-width">
 		<thead>
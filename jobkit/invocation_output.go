@@ -0,0 +1,88 @@
+package jobkit
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/blend/go-sdk/bufferutil"
+	"github.com/blend/go-sdk/cron"
+	"github.com/blend/go-sdk/web"
+	"github.com/blend/go-sdk/webutil"
+)
+
+var (
+	_ web.Result = (*InvocationOutputResult)(nil)
+)
+
+// InvocationOutputResult is a result that streams the output of an invocation as plain text.
+//
+// If the invocation is running, output is written as the job writes it, until the invocation
+// finishes or the request is cancelled.
+type InvocationOutputResult struct {
+	Invocation  *cron.JobInvocation
+	ErrorOutput bool
+	Interval    time.Duration
+}
+
+// IntervalOrDefault returns the interval between checks for new output or a default.
+func (ior InvocationOutputResult) IntervalOrDefault() time.Duration {
+	if ior.Interval > 0 {
+		return ior.Interval
+	}
+	return DefaultOutputStreamInterval
+}
+
+// Render implements web.Result.
+func (ior InvocationOutputResult) Render(r *web.Ctx) error {
+	r.Response.Header().Set(webutil.HeaderContentType, webutil.ContentTypeText)
+	r.Response.Header().Set(webutil.HeaderXContentTypeOptions, "nosniff")
+	r.Response.WriteHeader(http.StatusOK)
+
+	var offset int
+	for {
+		// check if the invocation has finished before reading the output so the last read gets all of it.
+		var finished bool
+		select {
+		case <-ior.Invocation.Done():
+			finished = true
+		default:
+		}
+
+		if buffer := ior.buffer(); buffer != nil {
+			if chunk := buffer.BytesFrom(offset); len(chunk) > 0 {
+				if _, err := r.Response.Write(chunk); err != nil {
+					return err
+				}
+				offset += len(chunk)
+				r.Response.Flush()
+			}
+		}
+		if finished {
+			return nil
+		}
+
+		select {
+		case <-ior.Invocation.Done():
+		case <-r.Context().Done():
+			return nil
+		case <-time.After(ior.IntervalOrDefault()):
+		}
+	}
+}
+
+// buffer returns the output buffer of the invocation state, if it is set.
+func (ior InvocationOutputResult) buffer() *bufferutil.Buffer {
+	var state *JobInvocationState
+	switch typed := ior.Invocation.State.(type) {
+	case *JobInvocationState:
+		state = typed
+	case JobInvocationState:
+		state = &typed
+	default:
+		return nil
+	}
+	if ior.ErrorOutput {
+		return state.ErrorOutput
+	}
+	return state.Output
+}
@@ -2,6 +2,7 @@ package jobkit
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"time"
 
@@ -25,6 +26,9 @@ var (
 	_ cron.OnFixedReceiver        = (*Job)(nil)
 	_ cron.OnDisabledReceiver     = (*Job)(nil)
 	_ cron.OnEnabledReceiver      = (*Job)(nil)
+
+	_ cron.HistoryRetentionProvider   = (*Job)(nil)
+	_ cron.InvocationStateUnmarshaler = (*Job)(nil)
 )
 
 // Job is the main job body.
@@ -132,6 +136,23 @@ func (job *Job) WithErrorClient(client diagnostics.Notifier) *Job {
 	return job
 }
 
+// HistoryRetention returns the job history retention policy from the config.
+func (job Job) HistoryRetention() cron.HistoryRetention {
+	return cron.HistoryRetention{
+		MaxCount: job.config.HistoryMaxCount,
+		MaxAge:   job.config.HistoryMaxAge,
+	}
+}
+
+// UnmarshalInvocationState restores the job invocation state from a history store.
+func (job Job) UnmarshalInvocationState(contents []byte) (interface{}, error) {
+	state := NewJobInvocationState()
+	if err := json.Unmarshal(contents, state); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
 // OnStart is a lifecycle event handler.
 func (job Job) OnStart(ctx context.Context) {
 	if job.config.NotifyOnStartOrDefault() {
@@ -16,6 +16,12 @@ type JobConfig struct {
 	Schedule string `json:"schedule" yaml:"schedule"`
 	// Timeout represents the abort threshold for the job.
 	Timeout time.Duration `json:"timeout" yaml:"timeout"`
+	// HistoryMaxCount is the maximum number of invocations to keep in the job history.
+	// If unset, the cron config value is used.
+	HistoryMaxCount int `json:"historyMaxCount" yaml:"historyMaxCount"`
+	// HistoryMaxAge is the maximum age of invocations to keep in the job history.
+	// If unset, the cron config value is used.
+	HistoryMaxAge time.Duration `json:"historyMaxAge" yaml:"historyMaxAge"`
 
 	// NotifyOnStart governs if we should send notifications job start.
 	NotifyOnStart *bool `json:"notifyOnStart" yaml:"notifyOnStart"`
@@ -0,0 +1,64 @@
+package jobkit
+
+import (
+	"context"
+
+	"github.com/blend/go-sdk/cron"
+)
+
+// NewJobHistory returns a page of the history for a job, most recent first.
+func NewJobHistory(ctx context.Context, js *cron.JobScheduler, offset, limit int) (*JobHistory, error) {
+	if offset < 0 {
+		offset = 0
+	}
+	if limit <= 0 {
+		limit = DefaultHistoryPageSize
+	}
+	history, err := js.ListHistory(ctx, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	total, err := js.CountHistory(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &JobHistory{
+		JobName: js.Name,
+		History: history,
+		Offset:  offset,
+		Limit:   limit,
+		Total:   total,
+	}, nil
+}
+
+// JobHistory is a page of job history.
+type JobHistory struct {
+	JobName string               `json:"jobName"`
+	History []cron.JobInvocation `json:"history"`
+	Offset  int                  `json:"offset"`
+	Limit   int                  `json:"limit"`
+	Total   int                  `json:"total"`
+}
+
+// HasPrevious returns if there is a previous (more recent) page.
+func (jh JobHistory) HasPrevious() bool {
+	return jh.Offset > 0
+}
+
+// PreviousOffset returns the offset of the previous page.
+func (jh JobHistory) PreviousOffset() int {
+	if previous := jh.Offset - jh.Limit; previous > 0 {
+		return previous
+	}
+	return 0
+}
+
+// HasNext returns if there is a next (older) page.
+func (jh JobHistory) HasNext() bool {
+	return jh.Offset+jh.Limit < jh.Total
+}
+
+// NextOffset returns the offset of the next page.
+func (jh JobHistory) NextOffset() int {
+	return jh.Offset + jh.Limit
+}
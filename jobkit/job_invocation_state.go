@@ -1,10 +1,12 @@
 package jobkit
 
 import (
-	"bytes"
 	"context"
+	"encoding/json"
 
+	"github.com/blend/go-sdk/bufferutil"
 	"github.com/blend/go-sdk/cron"
+	"github.com/blend/go-sdk/ex"
 )
 
 // WithJobInvocationState sets the job invocation state on a context.
@@ -34,13 +36,46 @@ func GetJobInvocationState(ctx context.Context) *JobInvocationState {
 // NewJobInvocationState returns a new job invocation state.
 func NewJobInvocationState() *JobInvocationState {
 	return &JobInvocationState{
-		Output:      new(bytes.Buffer),
-		ErrorOutput: new(bytes.Buffer),
+		Output:      new(bufferutil.Buffer),
+		ErrorOutput: new(bufferutil.Buffer),
 	}
 }
 
 // JobInvocationState is the state object for a job invocation.
+//
+// The output buffers are safe to read while the job writes to them,
+// so the output of running invocations can be streamed.
 type JobInvocationState struct {
-	Output      *bytes.Buffer
-	ErrorOutput *bytes.Buffer
+	Output      *bufferutil.Buffer
+	ErrorOutput *bufferutil.Buffer
+}
+
+// jobInvocationStateJSON is the serialized form of the job invocation state.
+type jobInvocationStateJSON struct {
+	Output      string `json:"output,omitempty"`
+	ErrorOutput string `json:"errorOutput,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler, writing the output buffers as strings
+// so they can be kept by history stores.
+func (jis JobInvocationState) MarshalJSON() ([]byte, error) {
+	var output jobInvocationStateJSON
+	if jis.Output != nil {
+		output.Output = jis.Output.String()
+	}
+	if jis.ErrorOutput != nil {
+		output.ErrorOutput = jis.ErrorOutput.String()
+	}
+	return json.Marshal(output)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (jis *JobInvocationState) UnmarshalJSON(contents []byte) error {
+	var input jobInvocationStateJSON
+	if err := json.Unmarshal(contents, &input); err != nil {
+		return ex.New(err)
+	}
+	jis.Output = bufferutil.NewBufferString(input.Output)
+	jis.ErrorOutput = bufferutil.NewBufferString(input.ErrorOutput)
+	return nil
 }
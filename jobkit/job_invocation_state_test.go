@@ -0,0 +1,39 @@
+package jobkit
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/bufferutil"
+	"github.com/blend/go-sdk/cron"
+)
+
+func TestJobInvocationStateJSON(t *testing.T) {
+	assert := assert.New(t)
+
+	state := JobInvocationState{
+		Output:      bufferutil.NewBufferString("test output"),
+		ErrorOutput: bufferutil.NewBufferString("test error output"),
+	}
+	contents, err := json.Marshal(state)
+	assert.Nil(err)
+	assert.Equal(`{"output":"test output","errorOutput":"test error output"}`, string(contents))
+
+	restored, err := (Job{}).UnmarshalInvocationState(contents)
+	assert.Nil(err)
+	typed, ok := restored.(*JobInvocationState)
+	assert.True(ok)
+	assert.Equal("test output", typed.Output.String())
+	assert.Equal("test error output", typed.ErrorOutput.String())
+}
+
+func TestJobHistoryRetention(t *testing.T) {
+	assert := assert.New(t)
+
+	job, err := NewJob(JobConfig{Name: "test", HistoryMaxCount: 50}, nil)
+	assert.Nil(err)
+	js := cron.NewJobScheduler(job)
+	assert.Equal(50, js.HistoryRetention().MaxCount)
+	assert.Equal(cron.DefaultHistoryMaxAge, js.HistoryRetention().MaxAge)
+}
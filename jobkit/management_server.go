@@ -1,9 +1,12 @@
 package jobkit
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 
 	"github.com/blend/go-sdk/cron"
+	"github.com/blend/go-sdk/ex"
 	"github.com/blend/go-sdk/web"
 )
 
@@ -16,36 +19,52 @@ func NewManagementServer(jm *cron.JobManager, cfg Config, options ...web.Option)
 		footerTemplate,
 		indexTemplate,
 		invocationTemplate,
+		historyTemplate,
+		graphTemplate,
+		runTemplate,
 	)
+	// health checks are registered before the auth middleware so probes do not need credentials.
+	app.Register(web.NewHealth(
+		web.NewHealthCheck("cron", HealthCheckJobManager(jm), web.OptHealthCheckLiveness(true)),
+	))
+	app.Use(AuthMiddleware(cfg.Auth))
+
 	app.GET("/", func(r *web.Ctx) web.Result {
 		return r.Views.View("index", jm.Status())
 	})
-	app.GET("/healthz", func(_ *web.Ctx) web.Result {
-		if jm.IsStarted() {
-			return web.JSON.OK()
-		}
-		return web.JSON.InternalError(fmt.Errorf("job manager is stopped or in an inconsistent state"))
-	})
 	app.GET("/api/jobs", func(_ *web.Ctx) web.Result {
 		return web.JSON.Result(jm.Status())
 	})
+	app.GET("/graph", func(r *web.Ctx) web.Result {
+		return r.Views.View("graph", jm.Graph())
+	})
+	app.GET("/api/graph", func(_ *web.Ctx) web.Result {
+		return web.JSON.Result(jm.Graph())
+	})
 	app.GET("/api/job.status/:jobName", func(r *web.Ctx) web.Result {
 		jobName, err := r.RouteParam("jobName")
 		if err != nil {
 			return web.JSON.BadRequest(err)
 		}
 		status, err := jm.Job(jobName)
-		if err := jm.RunJob(jobName); err != nil {
+		if err != nil {
 			return web.JSON.BadRequest(err)
 		}
 		return web.JSON.Result(status)
 	})
+	app.GET("/job.run/:jobName", func(r *web.Ctx) web.Result {
+		job, err := jm.Job(web.StringValue(r.RouteParam("jobName")))
+		if err != nil {
+			return r.Views.BadRequest(err)
+		}
+		return r.Views.View("run", job)
+	})
 	app.POST("/job.run/:jobName", func(r *web.Ctx) web.Result {
-		jobName, err := r.RouteParam("jobName")
+		job, err := jm.Job(web.StringValue(r.RouteParam("jobName")))
 		if err != nil {
 			return r.Views.BadRequest(err)
 		}
-		if err := jm.RunJob(jobName); err != nil {
+		if err := jm.RunJobWithParameters(job.Name, formParameterValues(r, job.ParameterSchema)); err != nil {
 			return r.Views.BadRequest(err)
 		}
 		return web.RedirectWithMethod("GET", "/")
@@ -55,7 +74,11 @@ func NewManagementServer(jm *cron.JobManager, cfg Config, options ...web.Option)
 		if err != nil {
 			return web.JSON.BadRequest(err)
 		}
-		if err := jm.RunJob(jobName); err != nil {
+		values, err := jsonParameterValues(r)
+		if err != nil {
+			return web.JSON.BadRequest(err)
+		}
+		if err := jm.RunJobWithParameters(jobName, values); err != nil {
 			return web.JSON.BadRequest(err)
 		}
 		return web.JSON.OK()
@@ -120,6 +143,28 @@ func NewManagementServer(jm *cron.JobManager, cfg Config, options ...web.Option)
 		}
 		return web.RedirectWithMethod("GET", "/")
 	})
+	app.GET("/job.history/:jobName", func(r *web.Ctx) web.Result {
+		job, err := jm.Job(web.StringValue(r.RouteParam("jobName")))
+		if err != nil {
+			return r.Views.BadRequest(err)
+		}
+		history, err := NewJobHistory(r.Context(), job, queryInt(r, "offset"), queryInt(r, "limit"))
+		if err != nil {
+			return r.Views.InternalError(err)
+		}
+		return r.Views.View("history", history)
+	})
+	app.GET("/api/job.history/:jobName", func(r *web.Ctx) web.Result {
+		job, err := jm.Job(web.StringValue(r.RouteParam("jobName")))
+		if err != nil {
+			return web.JSON.BadRequest(err)
+		}
+		history, err := NewJobHistory(r.Context(), job, queryInt(r, "offset"), queryInt(r, "limit"))
+		if err != nil {
+			return web.JSON.InternalError(err)
+		}
+		return web.JSON.Result(history)
+	})
 	app.GET("/job.invocation/:jobName/:invocation", func(r *web.Ctx) web.Result {
 		job, err := jm.Job(web.StringValue(r.RouteParam("jobName")))
 		if err != nil {
@@ -142,5 +187,154 @@ func NewManagementServer(jm *cron.JobManager, cfg Config, options ...web.Option)
 		}
 		return web.JSON.Result(invocation)
 	})
+	addAPIV1(app, jm)
 	return app
 }
+
+// addAPIV1 adds the versioned json api routes.
+func addAPIV1(app *web.App, jm *cron.JobManager) {
+	app.GET("/api/v1/jobs", func(_ *web.Ctx) web.Result {
+		return web.JSON.Result(jm.Status())
+	})
+	app.GET("/api/v1/graph", func(_ *web.Ctx) web.Result {
+		return web.JSON.Result(jm.Graph())
+	})
+	app.GET("/api/v1/jobs/:jobName", func(r *web.Ctx) web.Result {
+		job, err := jm.Job(web.StringValue(r.RouteParam("jobName")))
+		if err != nil {
+			return apiError(err)
+		}
+		return web.JSON.Result(job)
+	})
+	app.GET("/api/v1/jobs/:jobName/history", func(r *web.Ctx) web.Result {
+		job, err := jm.Job(web.StringValue(r.RouteParam("jobName")))
+		if err != nil {
+			return apiError(err)
+		}
+		history, err := NewJobHistory(r.Context(), job, queryInt(r, "offset"), queryInt(r, "limit"))
+		if err != nil {
+			return web.JSON.InternalError(err)
+		}
+		return web.JSON.Result(history)
+	})
+	app.GET("/api/v1/jobs/:jobName/invocations/:invocation", func(r *web.Ctx) web.Result {
+		invocation, result := apiInvocation(r, jm)
+		if result != nil {
+			return result
+		}
+		return web.JSON.Result(invocation)
+	})
+	app.GET("/api/v1/jobs/:jobName/invocations/:invocation/output", func(r *web.Ctx) web.Result {
+		invocation, result := apiInvocation(r, jm)
+		if result != nil {
+			return result
+		}
+		return InvocationOutputResult{Invocation: invocation}
+	})
+	app.GET("/api/v1/jobs/:jobName/invocations/:invocation/errorOutput", func(r *web.Ctx) web.Result {
+		invocation, result := apiInvocation(r, jm)
+		if result != nil {
+			return result
+		}
+		return InvocationOutputResult{Invocation: invocation, ErrorOutput: true}
+	})
+	app.POST("/api/v1/jobs/:jobName/run", func(r *web.Ctx) web.Result {
+		values, err := jsonParameterValues(r)
+		if err != nil {
+			return web.JSON.BadRequest(err)
+		}
+		if err := jm.RunJobWithParameters(web.StringValue(r.RouteParam("jobName")), values); err != nil {
+			return apiError(err)
+		}
+		return web.JSON.OK()
+	})
+	app.POST("/api/v1/jobs/:jobName/cancel", func(r *web.Ctx) web.Result {
+		if err := jm.CancelJob(web.StringValue(r.RouteParam("jobName"))); err != nil {
+			return apiError(err)
+		}
+		return web.JSON.OK()
+	})
+	app.POST("/api/v1/jobs/:jobName/enable", func(r *web.Ctx) web.Result {
+		if err := jm.EnableJobs(web.StringValue(r.RouteParam("jobName"))); err != nil {
+			return apiError(err)
+		}
+		return web.JSON.OK()
+	})
+	app.POST("/api/v1/jobs/:jobName/disable", func(r *web.Ctx) web.Result {
+		if err := jm.DisableJobs(web.StringValue(r.RouteParam("jobName"))); err != nil {
+			return apiError(err)
+		}
+		return web.JSON.OK()
+	})
+}
+
+// apiInvocation returns the invocation named by the route parameters, which may be running,
+// or the error result if it is not found.
+func apiInvocation(r *web.Ctx, jm *cron.JobManager) (*cron.JobInvocation, web.Result) {
+	job, err := jm.Job(web.StringValue(r.RouteParam("jobName")))
+	if err != nil {
+		return nil, apiError(err)
+	}
+	id := web.StringValue(r.RouteParam("invocation"))
+	if current := job.Current; current != nil && current.ID == id {
+		return current, nil
+	}
+	invocation := job.GetInvocationByID(id)
+	if invocation == nil {
+		return nil, web.JSON.NotFound()
+	}
+	return invocation, nil
+}
+
+// apiError returns the json result for an error, which is not found for jobs that are not loaded.
+func apiError(err error) web.Result {
+	if cron.IsJobNotLoaded(err) || cron.IsJobNotFound(err) {
+		return web.JSON.NotFound()
+	}
+	return web.JSON.BadRequest(err)
+}
+
+// queryInt returns an integer query string value, or zero if it is unset or invalid.
+func queryInt(r *web.Ctx, key string) int {
+	value, _ := web.IntValue(r.QueryValue(key))
+	return value
+}
+
+// formParameterValues returns the values of a job's parameters from a posted form.
+func formParameterValues(r *web.Ctx, schema cron.ParameterSchema) cron.ParameterValues {
+	values := cron.ParameterValues{}
+	for _, parameter := range schema {
+		if value := web.StringValue(r.FormValue(parameter.Name)); value != "" {
+			values[parameter.Name] = value
+		}
+	}
+	return values
+}
+
+// jsonParameterValues returns parameter values from a posted json object, if one is posted.
+// Numbers and booleans are read as their literal json text.
+func jsonParameterValues(r *web.Ctx) (cron.ParameterValues, error) {
+	body, err := r.PostBody()
+	if err != nil {
+		return nil, err
+	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil, nil
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, ex.New(err)
+	}
+	values := cron.ParameterValues{}
+	for name, value := range raw {
+		var text string
+		if err := json.Unmarshal(value, &text); err == nil {
+			values[name] = text
+			continue
+		}
+		if string(value) != "null" {
+			values[name] = string(value)
+		}
+	}
+	return values, nil
+}
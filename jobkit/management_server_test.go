@@ -1,14 +1,19 @@
 package jobkit
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/bufferutil"
 	"github.com/blend/go-sdk/cron"
+	"github.com/blend/go-sdk/r2"
 	"github.com/blend/go-sdk/uuid"
 	"github.com/blend/go-sdk/web"
 )
@@ -40,6 +45,38 @@ func TestManagementServer(t *testing.T) {
 	assert.Len(jobs.Jobs, 2)
 }
 
+func TestManagementServerJobStatus(t *testing.T) {
+	assert := assert.New(t)
+
+	runs := make(chan struct{}, 1)
+	jm := cron.New()
+	assert.Nil(jm.LoadJobs(cron.NewJob("test0", func(_ context.Context) error {
+		runs <- struct{}{}
+		return nil
+	})))
+	app := NewManagementServer(jm, Config{
+		Web: web.Config{
+			Port: 5000,
+		},
+	})
+
+	var status cron.JobScheduler
+	meta, err := web.MockGet(app, "/api/job.status/test0").JSONWithResponse(&status)
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, meta.StatusCode)
+	assert.Equal("test0", status.Name)
+
+	meta, err = web.MockGet(app, "/api/job.status/not-loaded").DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusBadRequest, meta.StatusCode)
+
+	select {
+	case <-runs:
+		assert.FailNow("getting the job status should not run the job")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
 func TestManagementServerHealthz(t *testing.T) {
 	assert := assert.New(t)
 
@@ -61,9 +98,16 @@ func TestManagementServerHealthz(t *testing.T) {
 
 	jm.Stop()
 
-	meta, err = web.MockGet(app, "/healthz").DiscardWithResponse()
+	var status web.HealthStatus
+	meta, err = web.MockGet(app, "/healthz").JSONWithResponse(&status)
 	assert.Nil(err)
-	assert.Equal(http.StatusInternalServerError, meta.StatusCode)
+	assert.Equal(http.StatusServiceUnavailable, meta.StatusCode)
+	assert.Equal(web.HealthStatusFailed, status.Status)
+	assert.Equal(web.HealthStatusFailed, status.Checks["cron"].Status)
+
+	meta, err = web.MockGet(app, "/livez").DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusServiceUnavailable, meta.StatusCode)
 }
 
 func TestManagementServerIndex(t *testing.T) {
@@ -84,8 +128,8 @@ func TestManagementServerIndex(t *testing.T) {
 			ID:      invocationID,
 			JobName: jobName,
 			State: JobInvocationState{
-				Output:      bytes.NewBufferString(output),
-				ErrorOutput: bytes.NewBufferString(errorOutput),
+				Output:      bufferutil.NewBufferString(output),
+				ErrorOutput: bufferutil.NewBufferString(errorOutput),
 			},
 		},
 	}
@@ -110,3 +154,246 @@ func TestManagementServerIndex(t *testing.T) {
 	assert.Contains(string(contents), output)
 	assert.Contains(string(contents), errorOutput)
 }
+
+func TestManagementServerHistory(t *testing.T) {
+	assert := assert.New(t)
+
+	jobName := "test0"
+	jm := cron.New()
+	jm.LoadJobs(cron.NewJob(jobName, func(_ context.Context) error { return nil }))
+
+	js, err := jm.Job(jobName)
+	assert.Nil(err)
+	for x := 0; x < 5; x++ {
+		js.History = append(js.History, cron.JobInvocation{ID: fmt.Sprintf("invocation-%d", x), JobName: jobName})
+	}
+
+	app := NewManagementServer(jm, Config{
+		Web: web.Config{
+			Port: 5000,
+		},
+	})
+
+	var history JobHistory
+	meta, err := web.MockGet(app, "/api/job.history/"+jobName, r2.OptQueryValue("offset", "1"), r2.OptQueryValue("limit", "2")).JSONWithResponse(&history)
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, meta.StatusCode)
+	assert.Equal(5, history.Total)
+	assert.Len(history.History, 2)
+	assert.Equal("invocation-3", history.History[0].ID)
+	assert.True(history.HasPrevious())
+	assert.True(history.HasNext())
+	assert.Equal(3, history.NextOffset())
+
+	contents, meta, err := web.MockGet(app, "/job.history/"+jobName, r2.OptQueryValue("limit", "2")).BytesWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, meta.StatusCode)
+	assert.Contains(string(contents), "invocation-4")
+	assert.Contains(string(contents), "invocation-3")
+	assert.NotContains(string(contents), "invocation-2")
+	assert.Contains(string(contents), "offset=2&limit=2")
+}
+
+func TestManagementServerGraph(t *testing.T) {
+	assert := assert.New(t)
+
+	jm := cron.New()
+	assert.Nil(jm.LoadJobs(
+		cron.NewJob("extract", func(_ context.Context) error { return nil }),
+		cron.NewJob("load", func(_ context.Context) error { return nil }, cron.OptJobBuilderDependencies("extract")),
+	))
+
+	app := NewManagementServer(jm, Config{
+		Web: web.Config{
+			Port: 5000,
+		},
+	})
+
+	var graph cron.JobGraph
+	meta, err := web.MockGet(app, "/api/graph").JSONWithResponse(&graph)
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, meta.StatusCode)
+	assert.Len(graph.Nodes, 2)
+	assert.Equal("load", graph.Nodes[1].Name)
+	assert.Equal(1, graph.Nodes[1].Level)
+	assert.Equal([]string{"extract"}, graph.Nodes[1].Dependencies)
+
+	contents, meta, err := web.MockGet(app, "/graph").BytesWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, meta.StatusCode)
+	assert.Contains(string(contents), `<a href="#extract">extract</a>`)
+	assert.Contains(string(contents), `<a href="#load">load</a>`)
+}
+
+func TestManagementServerRunWithParameters(t *testing.T) {
+	assert := assert.New(t)
+
+	// the api and the form run separate jobs so their runs do not overlap.
+	invoked := make(chan cron.ParameterValues, 1)
+	action := func(ctx context.Context) error {
+		invoked <- cron.GetJobInvocation(ctx).Parameters
+		return nil
+	}
+	schema := cron.OptJobBuilderParameterSchema(
+		cron.Parameter{Name: "table", Required: true, Description: "the table to backfill"},
+		cron.Parameter{Name: "limit", Type: cron.ParameterTypeInt, Default: "100"},
+	)
+	jm := cron.New()
+	assert.Nil(jm.LoadJobs(
+		cron.NewJob("backfill", action, schema),
+		cron.NewJob("backfill-form", action, schema),
+	))
+
+	app := NewManagementServer(jm, Config{
+		Web: web.Config{
+			Port: 5000,
+		},
+	})
+
+	contents, meta, err := web.MockGet(app, "/job.run/backfill-form").BytesWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, meta.StatusCode)
+	assert.Contains(string(contents), `name="table"`)
+	assert.Contains(string(contents), "the table to backfill")
+
+	meta, err = web.MockPost(app, "/api/job.run/backfill", ioutil.NopCloser(bytes.NewBufferString(`{"limit":"ten"}`))).DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusBadRequest, meta.StatusCode)
+
+	meta, err = web.MockPost(app, "/api/job.run/backfill", ioutil.NopCloser(bytes.NewBufferString(`{"table":"users","limit":10}`))).DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, meta.StatusCode)
+	select {
+	case values := <-invoked:
+		assert.Equal(cron.ParameterValues{"table": "users", "limit": "10"}, values)
+	case <-time.After(5 * time.Second):
+		assert.FailNow("the job should have run")
+	}
+
+	meta, err = web.MockPost(app, "/job.run/backfill-form", nil, r2.OptPostFormValue("table", "accounts"), r2.OptNoFollow()).DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusFound, meta.StatusCode)
+	select {
+	case values := <-invoked:
+		assert.Equal(cron.ParameterValues{"table": "accounts", "limit": "100"}, values)
+	case <-time.After(5 * time.Second):
+		assert.FailNow("the job should have run")
+	}
+}
+
+func TestManagementServerAPIV1(t *testing.T) {
+	assert := assert.New(t)
+
+	invoked := make(chan cron.ParameterValues, 1)
+	jm := cron.New()
+	assert.Nil(jm.LoadJobs(
+		cron.NewJob("test0", func(ctx context.Context) error {
+			invoked <- cron.GetJobInvocation(ctx).Parameters
+			return nil
+		}, cron.OptJobBuilderParameterSchema(cron.Parameter{Name: "table"})),
+		cron.NewJob("test1", func(_ context.Context) error { return nil }),
+	))
+
+	app := NewManagementServer(jm, Config{
+		Web: web.Config{
+			Port: 5000,
+		},
+	})
+
+	var status cron.Status
+	meta, err := web.MockGet(app, "/api/v1/jobs").JSONWithResponse(&status)
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, meta.StatusCode)
+	assert.Len(status.Jobs, 2)
+
+	var job cron.JobScheduler
+	meta, err = web.MockGet(app, "/api/v1/jobs/test0").JSONWithResponse(&job)
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, meta.StatusCode)
+	assert.Equal("test0", job.Name)
+	assert.Len(job.ParameterSchema, 1)
+
+	meta, err = web.MockGet(app, "/api/v1/jobs/not-loaded").DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusNotFound, meta.StatusCode)
+	meta, err = web.MockPost(app, "/api/v1/jobs/not-loaded/run", nil).DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusNotFound, meta.StatusCode)
+
+	meta, err = web.MockPost(app, "/api/v1/jobs/test0/run", ioutil.NopCloser(bytes.NewBufferString(`{"table":"users"}`))).DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, meta.StatusCode)
+	select {
+	case values := <-invoked:
+		assert.Equal(cron.ParameterValues{"table": "users"}, values)
+	case <-time.After(5 * time.Second):
+		assert.FailNow("the job should have run")
+	}
+
+	meta, err = web.MockPost(app, "/api/v1/jobs/test1/disable", nil).DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, meta.StatusCode)
+	assert.True(jm.IsJobDisabled("test1"))
+	meta, err = web.MockPost(app, "/api/v1/jobs/test1/enable", nil).DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, meta.StatusCode)
+	assert.False(jm.IsJobDisabled("test1"))
+	meta, err = web.MockPost(app, "/api/v1/jobs/test1/cancel", nil).DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, meta.StatusCode)
+
+	var history JobHistory
+	meta, err = web.MockGet(app, "/api/v1/jobs/test1/history").JSONWithResponse(&history)
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, meta.StatusCode)
+	assert.Equal("test1", history.JobName)
+}
+
+func TestManagementServerAPIV1Output(t *testing.T) {
+	assert := assert.New(t)
+
+	started, release := make(chan string), make(chan struct{})
+	jm := cron.New()
+	assert.Nil(jm.LoadJobs(cron.NewJob("test0", func(ctx context.Context) error {
+		state := NewJobInvocationState()
+		WithJobInvocationState(ctx, state)
+		state.Output.WriteString("first\n")
+		state.ErrorOutput.WriteString("error\n")
+		started <- cron.GetJobInvocation(ctx).ID
+		<-release
+		state.Output.WriteString("second\n")
+		return nil
+	})))
+	app := NewManagementServer(jm, Config{
+		Web: web.Config{
+			Port: 5000,
+		},
+	})
+
+	assert.Nil(jm.RunJob("test0"))
+	id := <-started
+
+	// the output of the running invocation is streamed until it finishes.
+	res, err := web.MockGet(app, "/api/v1/jobs/test0/invocations/"+id+"/output").Do()
+	assert.Nil(err)
+	defer res.Body.Close()
+	assert.Equal(http.StatusOK, res.StatusCode)
+	reader := bufio.NewReader(res.Body)
+	line, err := reader.ReadString('\n')
+	assert.Nil(err)
+	assert.Equal("first\n", line)
+
+	close(release)
+	rest, err := ioutil.ReadAll(reader)
+	assert.Nil(err)
+	assert.Equal("second\n", string(rest))
+
+	contents, meta, err := web.MockGet(app, "/api/v1/jobs/test0/invocations/"+id+"/errorOutput").BytesWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, meta.StatusCode)
+	assert.Equal("error\n", string(contents))
+
+	meta, err = web.MockGet(app, "/api/v1/jobs/test0/invocations/not-an-invocation/output").DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusNotFound, meta.StatusCode)
+}
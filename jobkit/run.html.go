@@ -0,0 +1,60 @@
+package jobkit
+
+var runTemplate = `
+{{ define "run" }}
+{{ template "header" . }}
+<div class="container">
+	<ul class="breadcrumbs">
+		<li><a href="/">Jobs</a></li>
+		<li>{{ .ViewModel.Name }}</li>
+		<li>Run</li>
+	</ul>
+	<form method="POST" action="/job.run/{{ .ViewModel.Name }}">
+		{{ template "csrf" . }}
+		<table class="u-full-width">
+			<thead>
+				<tr>
+					<th>Parameter</th>
+					<th>Type</th>
+					<th>Value</th>
+					<th>Description</th>
+				</tr>
+			</thead>
+			<tbody>
+			{{ range $index, $parameter := .ViewModel.ParameterSchema }}
+				<tr>
+					<td><label for="{{ $parameter.Name }}">{{ $parameter.Name }}{{ if $parameter.Required }} *{{ end }}</label></td>
+					<td>{{ $parameter.TypeOrDefault }}</td>
+					<td>
+					{{ if $parameter.Values }}
+						<select id="{{ $parameter.Name }}" name="{{ $parameter.Name }}">
+							{{ if not $parameter.Required }}<option value=""></option>{{ end }}
+							{{ range $valueIndex, $value := $parameter.Values }}
+							<option value="{{ $value }}"{{ if eq $value $parameter.Default }} selected{{ end }}>{{ $value }}</option>
+							{{ end }}
+						</select>
+					{{ else if eq $parameter.TypeOrDefault "bool" }}
+						<select id="{{ $parameter.Name }}" name="{{ $parameter.Name }}">
+							{{ if not $parameter.Required }}<option value=""></option>{{ end }}
+							<option value="true"{{ if eq $parameter.Default "true" }} selected{{ end }}>true</option>
+							<option value="false"{{ if eq $parameter.Default "false" }} selected{{ end }}>false</option>
+						</select>
+					{{ else }}
+						<input type="text" id="{{ $parameter.Name }}" name="{{ $parameter.Name }}" value="{{ $parameter.Default }}" />
+					{{ end }}
+					</td>
+					<td>{{ if $parameter.Description }}{{ $parameter.Description }}{{ else }}-{{ end }}</td>
+				</tr>
+			{{ else }}
+				<tr>
+					<td colspan=4>No Parameters</td>
+				</tr>
+			{{ end }}
+			</tbody>
+		</table>
+		<input type="submit" class="button button-primary" value="Run" />
+	</form>
+</div>
+{{ template "footer" . }}
+{{ end }}
+`
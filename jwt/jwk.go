@@ -0,0 +1,170 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+
+	"github.com/blend/go-sdk/ex"
+)
+
+// JWK key types and uses.
+const (
+	KeyTypeRSA = "RSA"
+	KeyTypeEC  = "EC"
+
+	KeyUseSignature = "sig"
+)
+
+// Common JWK errors.
+var (
+	ErrJWKUnsupportedKeyType ex.Class = "jwk key type is unsupported"
+	ErrJWKInvalid            ex.Class = "jwk is invalid"
+	ErrJWKNotFound           ex.Class = "jwk not found for key id"
+)
+
+// JWK is a JSON web key, as described in https://tools.ietf.org/html/rfc7517.
+// Only the public parameters of RSA and EC keys are supported.
+type JWK struct {
+	KTY string `json:"kty"`
+	USE string `json:"use,omitempty"`
+	KID string `json:"kid,omitempty"`
+	ALG string `json:"alg,omitempty"`
+
+	// RSA parameters
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+
+	// EC parameters
+	CRV string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// NewJWK returns a new JWK for a given public key.
+// The public key must be an *rsa.PublicKey or an *ecdsa.PublicKey.
+func NewJWK(kid, alg string, publicKey interface{}) (JWK, error) {
+	switch typed := publicKey.(type) {
+	case *rsa.PublicKey:
+		return JWK{
+			KTY: KeyTypeRSA,
+			USE: KeyUseSignature,
+			KID: kid,
+			ALG: alg,
+			N:   encodeBigInt(typed.N),
+			E:   encodeBigInt(big.NewInt(int64(typed.E))),
+		}, nil
+	case *ecdsa.PublicKey:
+		size := (typed.Curve.Params().BitSize + 7) / 8
+		return JWK{
+			KTY: KeyTypeEC,
+			USE: KeyUseSignature,
+			KID: kid,
+			ALG: alg,
+			CRV: typed.Curve.Params().Name,
+			X:   base64.RawURLEncoding.EncodeToString(padBytes(typed.X.Bytes(), size)),
+			Y:   base64.RawURLEncoding.EncodeToString(padBytes(typed.Y.Bytes(), size)),
+		}, nil
+	default:
+		return JWK{}, ex.New(ErrJWKUnsupportedKeyType)
+	}
+}
+
+// PublicKey returns the public key described by the JWK.
+// It returns an *rsa.PublicKey or an *ecdsa.PublicKey.
+func (j JWK) PublicKey() (interface{}, error) {
+	switch j.KTY {
+	case KeyTypeRSA:
+		n, err := decodeBigInt(j.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(j.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case KeyTypeEC:
+		var curve elliptic.Curve
+		switch j.CRV {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, ex.New(ErrJWKInvalid, ex.OptMessagef("unsupported curve: %s", j.CRV))
+		}
+		x, err := decodeBigInt(j.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(j.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, ex.New(ErrJWKInvalid, ex.OptMessage("point is not on curve"))
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, ex.New(ErrJWKUnsupportedKeyType, ex.OptMessagef("key type: %s", j.KTY))
+	}
+}
+
+// JWKS is a JSON web key set.
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// Key returns a key by key id.
+func (j JWKS) Key(kid string) (key JWK, ok bool) {
+	for _, key = range j.Keys {
+		if key.KID == kid {
+			ok = true
+			return
+		}
+	}
+	return
+}
+
+// Keyfunc returns the public key for a token based on its `kid` header.
+// It also checks that the token algorithm matches the key algorithm if the key specifies one.
+func (j JWKS) Keyfunc(token *Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := j.Key(kid)
+	if !ok {
+		return nil, ex.New(ErrValidation, ex.OptInner(ex.New(ErrJWKNotFound, ex.OptMessagef("kid: %s", kid))))
+	}
+	if key.ALG != "" && token.Method != nil && token.Method.Alg() != key.ALG {
+		return nil, ex.New(ErrValidation, ex.OptInner(ErrInvalidSigningMethod))
+	}
+	return key.PublicKey()
+}
+
+func encodeBigInt(value *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(value.Bytes())
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	if value == "" {
+		return nil, ex.New(ErrJWKInvalid, ex.OptMessage("key parameter is empty"))
+	}
+	contents, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, ex.New(ErrJWKInvalid, ex.OptInner(err))
+	}
+	return new(big.Int).SetBytes(contents), nil
+}
+
+func padBytes(contents []byte, size int) []byte {
+	if len(contents) >= size {
+		return contents
+	}
+	padded := make([]byte, size)
+	copy(padded[size-len(contents):], contents)
+	return padded
+}
@@ -0,0 +1,88 @@
+package jwt_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"encoding/json"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/jwt"
+	"github.com/blend/go-sdk/jwt/test"
+)
+
+func TestJWKRSA(t *testing.T) {
+	assert := assert.New(t)
+
+	privateKey := test.MustLoadRSAPrivateKey(test.SampleKey)
+	jwk, err := jwt.NewJWK("test-key", jwt.SigningMethodNameRS256, &privateKey.PublicKey)
+	assert.Nil(err)
+	assert.Equal(jwt.KeyTypeRSA, jwk.KTY)
+	assert.Equal("AQAB", jwk.E)
+
+	publicKey, err := jwk.PublicKey()
+	assert.Nil(err)
+	typed, ok := publicKey.(*rsa.PublicKey)
+	assert.True(ok)
+	assert.Equal(0, privateKey.PublicKey.N.Cmp(typed.N))
+	assert.Equal(privateKey.PublicKey.E, typed.E)
+}
+
+func TestJWKEC(t *testing.T) {
+	assert := assert.New(t)
+
+	privateKey, err := jwt.ParseECPrivateKeyFromPEM(test.EC256Private)
+	assert.Nil(err)
+	jwk, err := jwt.NewJWK("test-key", jwt.SigningMethodNameES256, &privateKey.PublicKey)
+	assert.Nil(err)
+	assert.Equal(jwt.KeyTypeEC, jwk.KTY)
+	assert.Equal("P-256", jwk.CRV)
+
+	publicKey, err := jwk.PublicKey()
+	assert.Nil(err)
+	typed, ok := publicKey.(*ecdsa.PublicKey)
+	assert.True(ok)
+	assert.Equal(0, privateKey.PublicKey.X.Cmp(typed.X))
+	assert.Equal(0, privateKey.PublicKey.Y.Cmp(typed.Y))
+
+	jwk.Y = jwk.X
+	_, err = jwk.PublicKey()
+	assert.NotNil(err)
+}
+
+func TestJWKUnsupported(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := jwt.NewJWK("test-key", jwt.SigningMethodNameHMAC256, []byte(test.HMACTestKey))
+	assert.NotNil(err)
+	_, err = jwt.JWK{KTY: "oct"}.PublicKey()
+	assert.NotNil(err)
+}
+
+func TestJWKSKeyfunc(t *testing.T) {
+	assert := assert.New(t)
+
+	privateKey := test.MustLoadRSAPrivateKey(test.SampleKey)
+	jwk, err := jwt.NewJWK("test-key", jwt.SigningMethodNameRS256, &privateKey.PublicKey)
+	assert.Nil(err)
+
+	contents, err := json.Marshal(jwt.JWKS{Keys: []jwt.JWK{jwk}})
+	assert.Nil(err)
+	var jwks jwt.JWKS
+	assert.Nil(json.Unmarshal(contents, &jwks))
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{"foo": "bar"})
+	token.Header["kid"] = "test-key"
+	signed, err := token.SignedString(privateKey)
+	assert.Nil(err)
+
+	parsed, err := jwt.Parse(signed, jwks.Keyfunc)
+	assert.Nil(err)
+	assert.True(parsed.Valid)
+
+	token.Header["kid"] = "other-key"
+	signed, err = token.SignedString(privateKey)
+	assert.Nil(err)
+	_, err = jwt.Parse(signed, jwks.Keyfunc)
+	assert.True(jwt.IsValidation(err))
+}
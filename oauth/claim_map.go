@@ -0,0 +1,83 @@
+package oauth
+
+import "strconv"
+
+// DefaultClaimMap maps the standard OpenID Connect claims to profile fields.
+var DefaultClaimMap = ClaimMap{
+	ID:            "sub",
+	Email:         "email",
+	VerifiedEmail: "email_verified",
+	Name:          "name",
+	GivenName:     "given_name",
+	FamilyName:    "family_name",
+	Link:          "profile",
+	Gender:        "gender",
+	Locale:        "locale",
+	PictureURL:    "picture",
+}
+
+// ClaimMap maps id token claim names to profile fields.
+// Fields that are unset are not mapped.
+type ClaimMap struct {
+	ID            string `json:"id,omitempty" yaml:"id,omitempty"`
+	Email         string `json:"email,omitempty" yaml:"email,omitempty"`
+	VerifiedEmail string `json:"verifiedEmail,omitempty" yaml:"verifiedEmail,omitempty"`
+	Name          string `json:"name,omitempty" yaml:"name,omitempty"`
+	GivenName     string `json:"givenName,omitempty" yaml:"givenName,omitempty"`
+	FamilyName    string `json:"familyName,omitempty" yaml:"familyName,omitempty"`
+	Link          string `json:"link,omitempty" yaml:"link,omitempty"`
+	Gender        string `json:"gender,omitempty" yaml:"gender,omitempty"`
+	Locale        string `json:"locale,omitempty" yaml:"locale,omitempty"`
+	PictureURL    string `json:"pictureURL,omitempty" yaml:"pictureURL,omitempty"`
+}
+
+// IsZero returns if the claim map is unset.
+func (cm ClaimMap) IsZero() bool {
+	return cm == ClaimMap{}
+}
+
+// Profile returns a profile from a set of claims.
+func (cm ClaimMap) Profile(claims map[string]interface{}) Profile {
+	return Profile{
+		ID:            claimString(claims, cm.ID),
+		Email:         claimString(claims, cm.Email),
+		VerifiedEmail: claimBool(claims, cm.VerifiedEmail),
+		Name:          claimString(claims, cm.Name),
+		GivenName:     claimString(claims, cm.GivenName),
+		FamilyName:    claimString(claims, cm.FamilyName),
+		Link:          claimString(claims, cm.Link),
+		Gender:        claimString(claims, cm.Gender),
+		Locale:        claimString(claims, cm.Locale),
+		PictureURL:    claimString(claims, cm.PictureURL),
+	}
+}
+
+func claimString(claims map[string]interface{}, name string) string {
+	if name == "" {
+		return ""
+	}
+	switch typed := claims[name].(type) {
+	case string:
+		return typed
+	case float64:
+		return strconv.FormatFloat(typed, 'f', -1, 64)
+	default:
+		return ""
+	}
+}
+
+// claimBool returns a boolean claim; some providers send booleans as strings.
+func claimBool(claims map[string]interface{}, name string) bool {
+	if name == "" {
+		return false
+	}
+	switch typed := claims[name].(type) {
+	case bool:
+		return typed
+	case string:
+		value, _ := strconv.ParseBool(typed)
+		return value
+	default:
+		return false
+	}
+}
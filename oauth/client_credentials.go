@@ -0,0 +1,227 @@
+package oauth
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/r2"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// NewClientCredentials returns a new client credentials token source from a config.
+// It uses the config client id, client secret, token url and scopes.
+/*
+The token source should be shared between requests so tokens are reused:
+
+	tokens := oauth.NewClientCredentials(cfg)
+	...
+	err := r2.New(serviceURL, tokens.R2Option()).JSON(&output)
+*/
+func NewClientCredentials(cfg Config, options ...ClientCredentialsOption) *ClientCredentials {
+	cc := &ClientCredentials{
+		ClientID:     cfg.ClientID,
+		ClientSecret: cfg.ClientSecret,
+		TokenURL:     cfg.TokenURL,
+		Scopes:       cfg.Scopes,
+		ExpiryDelta:  DefaultClientCredentialsExpiryDelta,
+		now:          func() time.Time { return time.Now().UTC() },
+	}
+	for _, option := range options {
+		option(cc)
+	}
+	return cc
+}
+
+// ClientCredentialsOption is an option for client credentials token sources.
+type ClientCredentialsOption func(*ClientCredentials)
+
+// OptClientCredentialsScopes sets the scopes tokens are requested with.
+func OptClientCredentialsScopes(scopes ...string) ClientCredentialsOption {
+	return func(cc *ClientCredentials) { cc.Scopes = scopes }
+}
+
+// OptClientCredentialsEndpointParams sets additional parameters sent to the token endpoint, e.g. an `audience`.
+func OptClientCredentialsEndpointParams(params url.Values) ClientCredentialsOption {
+	return func(cc *ClientCredentials) { cc.EndpointParams = params }
+}
+
+// OptClientCredentialsExpiryDelta sets how long before a token expires it is refreshed.
+func OptClientCredentialsExpiryDelta(expiryDelta time.Duration) ClientCredentialsOption {
+	return func(cc *ClientCredentials) { cc.ExpiryDelta = expiryDelta }
+}
+
+// OptClientCredentialsHTTPClient sets the http client used to request tokens.
+func OptClientCredentialsHTTPClient(client *http.Client) ClientCredentialsOption {
+	return func(cc *ClientCredentials) { cc.HTTPClient = client }
+}
+
+// ClientCredentials is a token source for the oauth2 client credentials flow, used for
+// service to service requests.
+//
+// Tokens are cached until `ExpiryDelta` before they expire. It is safe to use concurrently;
+// concurrent callers share a single token request when the cached token needs to be refreshed.
+type ClientCredentials struct {
+	ClientID       string
+	ClientSecret   string
+	TokenURL       string
+	Scopes         []string
+	EndpointParams url.Values
+	ExpiryDelta    time.Duration
+	HTTPClient     *http.Client
+
+	sync.Mutex
+	token *oauth2.Token
+	now   func() time.Time
+}
+
+// Validate validates the client credentials configuration.
+func (cc *ClientCredentials) Validate() error {
+	if cc.ClientID == "" {
+		return ex.New(ErrClientIDRequired)
+	}
+	if cc.ClientSecret == "" {
+		return ex.New(ErrClientSecretRequired)
+	}
+	if cc.TokenURL == "" {
+		return ex.New(ErrTokenURLRequired)
+	}
+	return nil
+}
+
+// Token returns the cached token, or requests a new token if the cached token
+// is missing or about to expire.
+func (cc *ClientCredentials) Token(ctx context.Context) (*oauth2.Token, error) {
+	cc.Lock()
+	defer cc.Unlock()
+
+	if cc.isValid(cc.token) {
+		return cc.token, nil
+	}
+	if err := cc.Validate(); err != nil {
+		return nil, err
+	}
+	if cc.HTTPClient != nil {
+		ctx = context.WithValue(ctx, oauth2.HTTPClient, cc.HTTPClient)
+	}
+	token, err := cc.config().Token(ctx)
+	if err != nil {
+		return nil, ex.New(ErrFailedClientCredentials, ex.OptInner(err))
+	}
+	cc.token = token
+	return token, nil
+}
+
+// Invalidate clears a cached token so the next call to `Token` requests a new token.
+// It is a no-op if the cached token has already been replaced.
+func (cc *ClientCredentials) Invalidate(token *oauth2.Token) {
+	cc.Lock()
+	defer cc.Unlock()
+	if cc.token == token {
+		cc.token = nil
+	}
+}
+
+// R2Option returns an r2 option that authorizes requests with client credentials tokens.
+//
+// If a request is rejected with a 401, the token is invalidated and the request is sent
+// once more with a new token; request bodies are buffered so they can be resent.
+// It wraps the request client transport, and so should be applied after any transport options.
+func (cc *ClientCredentials) R2Option() r2.Option {
+	return func(r *r2.Request) error {
+		var client http.Client
+		if r.Client != nil {
+			client = *r.Client
+		}
+		client.Transport = &clientCredentialsTransport{
+			Credentials: cc,
+			Transport:   client.Transport,
+		}
+		r.Client = &client
+		return nil
+	}
+}
+
+func (cc *ClientCredentials) isValid(token *oauth2.Token) bool {
+	if token == nil || token.AccessToken == "" {
+		return false
+	}
+	if token.Expiry.IsZero() {
+		return true
+	}
+	return cc.now().Add(cc.ExpiryDelta).Before(token.Expiry)
+}
+
+func (cc *ClientCredentials) config() *clientcredentials.Config {
+	return &clientcredentials.Config{
+		ClientID:       cc.ClientID,
+		ClientSecret:   cc.ClientSecret,
+		TokenURL:       cc.TokenURL,
+		Scopes:         cc.Scopes,
+		EndpointParams: cc.EndpointParams,
+	}
+}
+
+// clientCredentialsTransport sets the authorization header on requests, and retries once on a 401.
+type clientCredentialsTransport struct {
+	Credentials *ClientCredentials
+	Transport   http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (cct *clientCredentialsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		body, err = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, ex.New(err)
+		}
+	}
+
+	token, err := cct.Credentials.Token(req.Context())
+	if err != nil {
+		return nil, err
+	}
+	res, err := cct.transport().RoundTrip(authorize(req, token, body))
+	if err != nil || res.StatusCode != http.StatusUnauthorized {
+		return res, err
+	}
+
+	res.Body.Close()
+	cct.Credentials.Invalidate(token)
+	token, err = cct.Credentials.Token(req.Context())
+	if err != nil {
+		return nil, err
+	}
+	return cct.transport().RoundTrip(authorize(req, token, body))
+}
+
+func (cct *clientCredentialsTransport) transport() http.RoundTripper {
+	if cct.Transport != nil {
+		return cct.Transport
+	}
+	return http.DefaultTransport
+}
+
+// authorize returns a copy of a request with a token set in the authorization header.
+func authorize(req *http.Request, token *oauth2.Token, body []byte) *http.Request {
+	authorized := req.WithContext(req.Context())
+	authorized.Header = make(http.Header, len(req.Header)+1)
+	for key, values := range req.Header {
+		authorized.Header[key] = values
+	}
+	token.SetAuthHeader(authorized)
+	if body != nil {
+		authorized.Body = ioutil.NopCloser(bytes.NewReader(body))
+		authorized.ContentLength = int64(len(body))
+	}
+	return authorized
+}
@@ -0,0 +1,137 @@
+package oauth
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/r2"
+)
+
+func clientCredentialsTokenServer(tokens *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		clientID, clientSecret, _ := r.BasicAuth()
+		if err := r.ParseForm(); err != nil || r.PostForm.Get("grant_type") != "client_credentials" || clientID != "foo" || clientSecret != "bar" {
+			http.Error(rw, `{"error":"invalid_client"}`, http.StatusUnauthorized)
+			return
+		}
+		count := atomic.AddInt32(tokens, 1)
+		rw.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(rw, `{"access_token":"token-%d","token_type":"bearer","expires_in":3600,"scope":%q}`, count, r.PostForm.Get("scope"))
+	}))
+}
+
+func TestClientCredentialsToken(t *testing.T) {
+	assert := assert.New(t)
+
+	var tokens int32
+	server := clientCredentialsTokenServer(&tokens)
+	defer server.Close()
+
+	cc := NewClientCredentials(Config{ClientID: "foo", ClientSecret: "bar", TokenURL: server.URL, Scopes: []string{"read", "write"}})
+	now := time.Now().UTC()
+	cc.now = func() time.Time { return now }
+
+	token, err := cc.Token(context.Background())
+	assert.Nil(err)
+	assert.Equal("token-1", token.AccessToken)
+	assert.Equal("read write", token.Extra("scope"))
+
+	token, err = cc.Token(context.Background())
+	assert.Nil(err)
+	assert.Equal("token-1", token.AccessToken)
+
+	// the token is refreshed within the expiry delta of the token expiry.
+	now = token.Expiry.Add(-DefaultClientCredentialsExpiryDelta)
+	token, err = cc.Token(context.Background())
+	assert.Nil(err)
+	assert.Equal("token-2", token.AccessToken)
+
+	cc.Invalidate(token)
+	token, err = cc.Token(context.Background())
+	assert.Nil(err)
+	assert.Equal("token-3", token.AccessToken)
+	assert.Equal(3, atomic.LoadInt32(&tokens))
+}
+
+func TestClientCredentialsTokenConcurrent(t *testing.T) {
+	assert := assert.New(t)
+
+	var tokens int32
+	server := clientCredentialsTokenServer(&tokens)
+	defer server.Close()
+
+	cc := NewClientCredentials(Config{ClientID: "foo", ClientSecret: "bar", TokenURL: server.URL})
+	wg := sync.WaitGroup{}
+	wg.Add(8)
+	for x := 0; x < 8; x++ {
+		go func() {
+			defer wg.Done()
+			token, err := cc.Token(context.Background())
+			assert.Nil(err)
+			assert.Equal("token-1", token.AccessToken)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(1, atomic.LoadInt32(&tokens))
+}
+
+func TestClientCredentialsTokenErrors(t *testing.T) {
+	assert := assert.New(t)
+
+	var tokens int32
+	server := clientCredentialsTokenServer(&tokens)
+	defer server.Close()
+
+	_, err := NewClientCredentials(Config{ClientID: "foo", ClientSecret: "bar"}).Token(context.Background())
+	assert.Equal(ErrTokenURLRequired, ex.ErrClass(err))
+
+	_, err = NewClientCredentials(Config{ClientID: "foo", ClientSecret: "not-bar", TokenURL: server.URL}).Token(context.Background())
+	assert.Equal(ErrFailedClientCredentials, ex.ErrClass(err))
+	assert.NotNil(ex.ErrInner(err))
+}
+
+func TestClientCredentialsR2Option(t *testing.T) {
+	assert := assert.New(t)
+
+	var tokens int32
+	tokenServer := clientCredentialsTokenServer(&tokens)
+	defer tokenServer.Close()
+
+	var authorizations []string
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		authorizations = append(authorizations, r.Header.Get("Authorization"))
+		body, _ := ioutil.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		// the first token is revoked server side.
+		if r.Header.Get("Authorization") == "Bearer token-1" {
+			rw.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	cc := NewClientCredentials(Config{ClientID: "foo", ClientSecret: "bar", TokenURL: tokenServer.URL})
+	res, err := r2.New(server.URL, r2.OptPost(), r2.OptBody(ioutil.NopCloser(strings.NewReader("payload"))), cc.R2Option()).DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, res.StatusCode)
+	assert.Equal([]string{"Bearer token-1", "Bearer token-2"}, authorizations)
+	assert.Equal([]string{"payload", "payload"}, bodies)
+
+	res, err = r2.New(server.URL, cc.R2Option()).DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, res.StatusCode)
+	assert.Equal("Bearer token-2", authorizations[2])
+	assert.Equal(2, atomic.LoadInt32(&tokens))
+}
@@ -2,6 +2,7 @@ package oauth
 
 import (
 	"encoding/base64"
+	"time"
 
 	"github.com/blend/go-sdk/env"
 )
@@ -20,6 +21,17 @@ type Config struct {
 	ClientID string `json:"clientID,omitempty" yaml:"clientID,omitempty" env:"OAUTH_CLIENT_ID"`
 	// ClientSecret is part of the oauth credential pair.
 	ClientSecret string `json:"clientSecret,omitempty" yaml:"clientSecret,omitempty" env:"OAUTH_CLIENT_SECRET"`
+	// Issuer is the OpenID Connect issuer url; if it is set, the provider is loaded from
+	// the issuer discovery document instead of using google.
+	Issuer string `json:"issuer,omitempty" yaml:"issuer,omitempty" env:"OAUTH_ISSUER"`
+	// DiscoveryTimeout is the timeout for loading the provider from the issuer discovery document.
+	DiscoveryTimeout time.Duration `json:"discoveryTimeout,omitempty" yaml:"discoveryTimeout,omitempty" env:"OAUTH_DISCOVERY_TIMEOUT"`
+	// PKCE enables proof key for code exchange; it requires a secret.
+	PKCE bool `json:"pkce,omitempty" yaml:"pkce,omitempty" env:"OAUTH_PKCE"`
+	// ClaimMap maps id token claims to profile fields for OpenID Connect providers.
+	ClaimMap ClaimMap `json:"claimMap,omitempty" yaml:"claimMap,omitempty"`
+	// TokenURL is the token endpoint used by the client credentials flow.
+	TokenURL string `json:"tokenURL,omitempty" yaml:"tokenURL,omitempty" env:"OAUTH_TOKEN_URL"`
 }
 
 // IsZero returns if the config is set or not.
@@ -44,6 +56,14 @@ func (c Config) DecodeSecret() ([]byte, error) {
 	return nil, nil
 }
 
+// DiscoveryTimeoutOrDefault returns the discovery timeout or a default.
+func (c Config) DiscoveryTimeoutOrDefault() time.Duration {
+	if c.DiscoveryTimeout > 0 {
+		return c.DiscoveryTimeout
+	}
+	return DefaultDiscoveryTimeout
+}
+
 // ScopesOrDefault gets oauth scopes to authenticate with or a default set of scopes.
 func (c Config) ScopesOrDefault() []string {
 	if len(c.Scopes) > 0 {
@@ -15,6 +15,7 @@ func TestNewConfigFromEnv(t *testing.T) {
 	env.Env().Set("OAUTH_HOSTED_DOMAIN", "foo.com")
 	env.Env().Set("OAUTH_CLIENT_ID", "foo")
 	env.Env().Set("OAUTH_CLIENT_SECRET", "bar")
+	env.Env().Set("OAUTH_TOKEN_URL", "https://auth.foo.com/oauth/token")
 
 	cfg := &Config{}
 	err := cfg.Resolve()
@@ -23,6 +24,7 @@ func TestNewConfigFromEnv(t *testing.T) {
 	assert.Equal("bar", cfg.ClientSecret)
 	assert.Equal("https://app.com/oauth/google", cfg.RedirectURI)
 	assert.Equal("foo.com", cfg.HostedDomain)
+	assert.Equal("https://auth.foo.com/oauth/token", cfg.TokenURL)
 }
 
 func TestConfig(t *testing.T) {
@@ -1,5 +1,7 @@
 package oauth
 
+import "time"
+
 var (
 	// DefaultScopes is the default oauth scopes.
 	DefaultScopes = []string{
@@ -9,6 +11,14 @@ var (
 	}
 )
 
+const (
+	// DefaultClientCredentialsExpiryDelta is the default time before a client credentials token
+	// expires that it is refreshed.
+	DefaultClientCredentialsExpiryDelta = 30 * time.Second
+	// DefaultDiscoveryTimeout is the default timeout for loading the provider when a manager is created from a config.
+	DefaultDiscoveryTimeout = 10 * time.Second
+)
+
 const (
 	// ErrCodeMissing is returned if the code was missing from an oauth return request.
 	ErrCodeMissing Error = "state missing from request"
@@ -37,4 +47,22 @@ const (
 	ErrRedirectURIRequired Error = "redirectURI is required"
 	// ErrInvalidRedirectURI is an error in validating the redirect uri.
 	ErrInvalidRedirectURI Error = "invalid redirectURI"
+	// ErrTokenURLRequired is a self validation error.
+	ErrTokenURLRequired Error = "tokenURL is required"
+
+	// ErrProviderDiscovery is returned if the OpenID Connect discovery document cannot be loaded or is invalid.
+	ErrProviderDiscovery Error = "oauth provider discovery failed"
+	// ErrProviderKeys is returned if the OpenID Connect provider keys cannot be loaded.
+	ErrProviderKeys Error = "oauth provider keys fetch failed"
+	// ErrProviderResponseStatus is returned if the OpenID Connect provider returns a non 200 response.
+	ErrProviderResponseStatus Error = "oauth provider returned a non 200 response"
+	// ErrIDTokenMissing is returned if the token response from an OpenID Connect provider does not include an id token.
+	ErrIDTokenMissing Error = "id token missing from token response"
+	// ErrInvalidIDToken is returned if an id token fails verification.
+	ErrInvalidIDToken Error = "invalid id token"
+	// ErrInvalidNonce is returned if the id token nonce does not match the oauth state nonce.
+	ErrInvalidNonce Error = "invalid id token nonce"
+
+	// ErrFailedClientCredentials happens if a client credentials token request fails.
+	ErrFailedClientCredentials Error = "oauth client credentials token request failed"
 )
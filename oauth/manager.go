@@ -3,6 +3,7 @@ package oauth
 import (
 	"context"
 	"crypto/hmac"
+	"crypto/sha256"
 	"crypto/sha512"
 	"encoding/base64"
 	"encoding/json"
@@ -51,21 +52,42 @@ type Manager struct {
 	HostedDomain         string
 	ClientID             string
 	ClientSecret         string
+	// Provider is an optional OpenID Connect provider used instead of google.
+	Provider *Provider
+	// PKCE enables proof key for code exchange.
+	// The code verifier is derived from the state token with the secret, so a secret is required.
+	PKCE bool
+	// ClaimMap maps id token claims to profile fields if a provider is set.
+	ClaimMap ClaimMap
 }
 
 // OAuthURL is the auth url for google with a given clientID.
 // This is typically the link that a user will click on to start the auth process.
 func (m *Manager) OAuthURL(r *http.Request, stateOptions ...StateOption) (oauthURL string, err error) {
+	if m.PKCE && len(m.Secret) == 0 {
+		err = ex.New(ErrSecretRequired, ex.OptMessage("pkce requires a secret"))
+		return
+	}
+	stateValue := m.CreateState(stateOptions...)
 	var state string
-	state, err = SerializeState(m.CreateState(stateOptions...))
+	state, err = SerializeState(stateValue)
 	if err != nil {
 		return
 	}
 
 	var opts []oauth2.AuthCodeOption
-	if len(m.HostedDomain) > 0 {
+	if len(m.HostedDomain) > 0 && m.Provider == nil {
 		opts = append(opts, oauth2.SetAuthURLParam("hd", m.HostedDomain))
 	}
+	if len(stateValue.Nonce) > 0 {
+		opts = append(opts, oauth2.SetAuthURLParam("nonce", stateValue.Nonce))
+	}
+	if m.PKCE {
+		opts = append(opts,
+			oauth2.SetAuthURLParam("code_challenge", CodeChallengeS256(m.codeVerifier(stateValue))),
+			oauth2.SetAuthURLParam("code_challenge_method", "S256"),
+		)
+	}
 	oauthURL = m.conf(r).AuthCodeURL(state, opts...)
 	return
 }
@@ -104,7 +126,11 @@ func (m *Manager) Finish(r *http.Request) (result *Result, err error) {
 	}
 
 	// Handle the exchange code to initiate a transport.
-	tok, err := m.conf(r).Exchange(r.Context(), code)
+	var opts []oauth2.AuthCodeOption
+	if m.PKCE {
+		opts = append(opts, oauth2.SetAuthURLParam("code_verifier", m.codeVerifier(result.State)))
+	}
+	tok, err := m.conf(r).Exchange(r.Context(), code, opts...)
 	if err != nil {
 		err = ex.New(ErrFailedCodeExchange, ex.OptInner(err))
 		return
@@ -115,6 +141,11 @@ func (m *Manager) Finish(r *http.Request) (result *Result, err error) {
 	result.Response.RefreshToken = tok.RefreshToken
 	result.Response.Expiry = tok.Expiry
 
+	if m.Provider != nil {
+		err = m.finishProvider(r.Context(), tok, result)
+		return
+	}
+
 	var prof Profile
 	prof, err = m.FetchProfile(r.Context(), tok.AccessToken)
 	if err != nil {
@@ -157,9 +188,30 @@ func (m *Manager) CreateState(options ...StateOption) (state State) {
 		state.Token = uuid.V4().String()
 		state.SecureToken = m.hash(state.Token)
 	}
+	if m.Provider != nil && state.Nonce == "" {
+		if len(m.Secret) > 0 {
+			state.Nonce = m.nonce(state)
+		} else {
+			state.Nonce = uuid.V4().String()
+		}
+	}
 	return
 }
 
+// ClaimMapOrDefault returns the claim map or a default.
+func (m *Manager) ClaimMapOrDefault() ClaimMap {
+	if !m.ClaimMap.IsZero() {
+		return m.ClaimMap
+	}
+	return DefaultClaimMap
+}
+
+// CodeChallengeS256 returns the S256 pkce code challenge for a code verifier.
+func CodeChallengeS256(codeVerifier string) string {
+	hash := sha256.Sum256([]byte(codeVerifier))
+	return base64.RawURLEncoding.EncodeToString(hash[:])
+}
+
 // --------------------------------------------------------------------------------
 // Validation Helpers
 // --------------------------------------------------------------------------------
@@ -172,6 +224,9 @@ func (m *Manager) ValidateState(state State) error {
 		if !hmac.Equal([]byte(expected), []byte(actual)) {
 			return ErrInvalidAntiforgeryToken
 		}
+		if m.Provider != nil && !hmac.Equal([]byte(m.nonce(state)), []byte(state.Nonce)) {
+			return ErrInvalidNonce
+		}
 	}
 	return nil
 }
@@ -197,13 +252,63 @@ func (m *Manager) ValidateProfile(p *Profile) error {
 // --------------------------------------------------------------------------------
 
 func (m *Manager) conf(r *http.Request) *oauth2.Config {
+	endpoint := google.Endpoint
+	if m.Provider != nil {
+		endpoint = m.Provider.Endpoint()
+	}
 	return &oauth2.Config{
 		ClientID:     m.ClientID,
 		ClientSecret: m.ClientSecret,
 		RedirectURL:  m.getRedirectURI(r),
-		Scopes:       m.Scopes,
-		Endpoint:     google.Endpoint,
+		Scopes:       m.scopes(),
+		Endpoint:     endpoint,
+	}
+}
+
+// scopes returns the manager scopes; OpenID Connect providers require the `openid` scope.
+func (m *Manager) scopes() []string {
+	if m.Provider == nil {
+		return m.Scopes
+	}
+	if len(m.Scopes) == 0 {
+		return DefaultScopes
+	}
+	if !containsString(m.Scopes, "openid") {
+		return append([]string{"openid"}, m.Scopes...)
+	}
+	return m.Scopes
+}
+
+// finishProvider verifies the id token from an OpenID Connect provider and maps its claims to the profile.
+func (m *Manager) finishProvider(ctx context.Context, tok *oauth2.Token, result *Result) error {
+	rawIDToken, _ := tok.Extra("id_token").(string)
+	if rawIDToken == "" {
+		return ex.New(ErrIDTokenMissing)
 	}
+	if result.State.Nonce == "" {
+		return ex.New(ErrInvalidNonce, ex.OptMessage("state nonce is missing"))
+	}
+	claims, err := m.Provider.VerifyIDToken(ctx, rawIDToken, m.ClientID, result.State.Nonce)
+	if err != nil {
+		return err
+	}
+	result.Response.IDToken = rawIDToken
+	result.Claims = Values(claims)
+	result.Profile = m.ClaimMapOrDefault().Profile(claims)
+	return nil
+}
+
+// nonce returns the id token nonce for a state.
+// It is derived from the state token so the nonce cannot be swapped out of the state.
+func (m *Manager) nonce(state State) string {
+	return m.hash("nonce:" + state.Token)
+}
+
+// codeVerifier returns the pkce code verifier for a state.
+// It is derived from the state token so it does not need to be stored, and cannot be
+// computed by anyone who sees the state without the secret.
+func (m *Manager) codeVerifier(state State) string {
+	return base64.RawURLEncoding.EncodeToString(m.hmac([]byte("pkce:" + state.Token)))
 }
 
 func (m *Manager) getRedirectURI(r *http.Request) string {
@@ -1,11 +1,16 @@
 package oauth
 
-import "github.com/blend/go-sdk/r2"
+import (
+	"context"
+
+	"github.com/blend/go-sdk/r2"
+)
 
 // Option is an option for oauth managers.
 type Option func(*Manager) error
 
 // OptConfig sets a manager based on a config.
+// If the config has an issuer, the provider is discovered within the config discovery timeout.
 func OptConfig(cfg Config) Option {
 	return func(m *Manager) error {
 		secret, err := cfg.DecodeSecret()
@@ -18,6 +23,19 @@ func OptConfig(cfg Config) Option {
 		m.Scopes = cfg.ScopesOrDefault()
 		m.ClientID = cfg.ClientID
 		m.ClientSecret = cfg.ClientSecret
+		m.PKCE = cfg.PKCE
+		if !cfg.ClaimMap.IsZero() {
+			m.ClaimMap = cfg.ClaimMap
+		}
+		if cfg.Issuer != "" {
+			ctx, cancel := context.WithTimeout(context.Background(), cfg.DiscoveryTimeoutOrDefault())
+			defer cancel()
+			provider, err := DiscoverProvider(ctx, cfg.Issuer)
+			if err != nil {
+				return err
+			}
+			m.Provider = provider
+		}
 		return nil
 	}
 }
@@ -87,3 +105,29 @@ func OptTracer(tracer Tracer) Option {
 		return nil
 	}
 }
+
+// OptProvider sets the manager OpenID Connect provider.
+// If it is set, the provider endpoints are used instead of google, and profiles are
+// read from the verified id token claims.
+func OptProvider(provider *Provider) Option {
+	return func(m *Manager) error {
+		m.Provider = provider
+		return nil
+	}
+}
+
+// OptPKCE sets if the manager uses proof key for code exchange.
+func OptPKCE(pkce bool) Option {
+	return func(m *Manager) error {
+		m.PKCE = pkce
+		return nil
+	}
+}
+
+// OptClaimMap sets the manager id token claim map.
+func OptClaimMap(claimMap ClaimMap) Option {
+	return func(m *Manager) error {
+		m.ClaimMap = claimMap
+		return nil
+	}
+}
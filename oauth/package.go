@@ -1,2 +1,5 @@
 // Package oauth implements some helper wrappers ontop of the existing google implementation of oauth.
+//
+// It also supports generic OpenID Connect providers (see `DiscoverProvider` and `OptProvider`), and the
+// client credentials flow for service to service requests (see `NewClientCredentials`).
 package oauth
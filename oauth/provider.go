@@ -0,0 +1,205 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/jwt"
+	"github.com/blend/go-sdk/r2"
+	"golang.org/x/oauth2"
+)
+
+// DiscoveryPath is the path of the OpenID Connect discovery document relative to the issuer.
+const DiscoveryPath = "/.well-known/openid-configuration"
+
+// DefaultProviderKeysRefreshInterval is the minimum time between refreshing the provider keys
+// when an id token is signed with an unknown key.
+const DefaultProviderKeysRefreshInterval = time.Minute
+
+// IDTokenSigningMethods are the id token signing methods accepted by providers.
+var IDTokenSigningMethods = []string{
+	jwt.SigningMethodNameRS256,
+	jwt.SigningMethodNameRS384,
+	jwt.SigningMethodNameRS512,
+	jwt.SigningMethodNameES256,
+	jwt.SigningMethodNameES384,
+	jwt.SigningMethodNameES512,
+}
+
+// Discovery is an OpenID Connect discovery document.
+type Discovery struct {
+	Issuer                        string   `json:"issuer"`
+	AuthorizationEndpoint         string   `json:"authorization_endpoint"`
+	TokenEndpoint                 string   `json:"token_endpoint"`
+	UserInfoEndpoint              string   `json:"userinfo_endpoint,omitempty"`
+	JWKSURI                       string   `json:"jwks_uri"`
+	ScopesSupported               []string `json:"scopes_supported,omitempty"`
+	ClaimsSupported               []string `json:"claims_supported,omitempty"`
+	CodeChallengeMethodsSupported []string `json:"code_challenge_methods_supported,omitempty"`
+}
+
+// DiscoverProvider loads the discovery document and keys for an issuer.
+func DiscoverProvider(ctx context.Context, issuer string, defaults ...r2.Option) (*Provider, error) {
+	issuer = strings.TrimSuffix(issuer, "/")
+	var discovery Discovery
+	if err := fetchJSON(ctx, issuer+DiscoveryPath, defaults, &discovery); err != nil {
+		return nil, ex.New(ErrProviderDiscovery, ex.OptMessagef("issuer: %s", issuer), ex.OptInner(err))
+	}
+	if strings.TrimSuffix(discovery.Issuer, "/") != issuer {
+		return nil, ex.New(ErrProviderDiscovery, ex.OptMessagef("discovery issuer %q does not match %q", discovery.Issuer, issuer))
+	}
+	provider := &Provider{
+		Discovery:       discovery,
+		RequestDefaults: defaults,
+	}
+	if err := provider.RefreshKeys(ctx); err != nil {
+		return nil, err
+	}
+	return provider, nil
+}
+
+// Provider is an OpenID Connect identity provider.
+type Provider struct {
+	Discovery Discovery
+	// RequestDefaults are options applied to requests to the provider.
+	RequestDefaults []r2.Option
+	// KeysRefreshInterval is the minimum time between refreshing the keys.
+	KeysRefreshInterval time.Duration
+
+	sync.Mutex
+	keys          jwt.JWKS
+	keysRefreshed time.Time
+}
+
+// Endpoint returns the provider oauth2 endpoint.
+func (p *Provider) Endpoint() oauth2.Endpoint {
+	return oauth2.Endpoint{
+		AuthURL:  p.Discovery.AuthorizationEndpoint,
+		TokenURL: p.Discovery.TokenEndpoint,
+	}
+}
+
+// Keys returns the current provider keys.
+func (p *Provider) Keys() jwt.JWKS {
+	p.Lock()
+	defer p.Unlock()
+	return p.keys
+}
+
+// RefreshKeys fetches the provider keys from the discovery `jwks_uri`.
+func (p *Provider) RefreshKeys(ctx context.Context) error {
+	var keys jwt.JWKS
+	if err := fetchJSON(ctx, p.Discovery.JWKSURI, p.RequestDefaults, &keys); err != nil {
+		return ex.New(ErrProviderKeys, ex.OptInner(err))
+	}
+	p.Lock()
+	p.keys = keys
+	p.keysRefreshed = time.Now().UTC()
+	p.Unlock()
+	return nil
+}
+
+// KeysRefreshIntervalOrDefault returns the keys refresh interval or a default.
+func (p *Provider) KeysRefreshIntervalOrDefault() time.Duration {
+	if p.KeysRefreshInterval > 0 {
+		return p.KeysRefreshInterval
+	}
+	return DefaultProviderKeysRefreshInterval
+}
+
+// Keyfunc returns a jwt keyfunc that looks up keys by the token `kid`.
+// If the key is not found, the keys are refreshed (at most once per refresh interval)
+// to pick up keys the provider has rotated in.
+func (p *Provider) Keyfunc(ctx context.Context) jwt.Keyfunc {
+	return func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		keys := p.Keys()
+		if _, ok := keys.Key(kid); !ok && p.shouldRefreshKeys() {
+			if err := p.RefreshKeys(ctx); err != nil {
+				return nil, err
+			}
+			keys = p.Keys()
+		}
+		return keys.Keyfunc(token)
+	}
+}
+
+// VerifyIDToken verifies an id token signature and its `iss`, `aud`, `azp`, `exp` and `nonce` claims,
+// and returns its claims.
+func (p *Provider) VerifyIDToken(ctx context.Context, rawIDToken, clientID, nonce string) (jwt.MapClaims, error) {
+	claims := jwt.MapClaims{}
+	parser := jwt.Parser{ValidMethods: IDTokenSigningMethods}
+	if _, err := parser.ParseWithClaims(rawIDToken, claims, p.Keyfunc(ctx)); err != nil {
+		return nil, ex.New(ErrInvalidIDToken, ex.OptInner(err))
+	}
+	if !claims.VerifyExpiresAt(jwt.TimeFunc().Unix(), true) {
+		return nil, ex.New(ErrInvalidIDToken, ex.OptMessage("exp claim is missing"))
+	}
+	if !claims.VerifyIssuer(p.Discovery.Issuer, true) {
+		return nil, ex.New(ErrInvalidIDToken, ex.OptMessage("iss claim does not match the issuer"))
+	}
+	audiences := claimStrings(claims["aud"])
+	if !containsString(audiences, clientID) {
+		return nil, ex.New(ErrInvalidIDToken, ex.OptMessage("aud claim does not include the client id"))
+	}
+	if azp, ok := claims["azp"].(string); (ok || len(audiences) > 1) && azp != clientID {
+		return nil, ex.New(ErrInvalidIDToken, ex.OptMessage("azp claim does not match the client id"))
+	}
+	if tokenNonce, _ := claims["nonce"].(string); tokenNonce != nonce {
+		return nil, ex.New(ErrInvalidNonce)
+	}
+	return claims, nil
+}
+
+func (p *Provider) shouldRefreshKeys() bool {
+	p.Lock()
+	defer p.Unlock()
+	return time.Now().UTC().Sub(p.keysRefreshed) >= p.KeysRefreshIntervalOrDefault()
+}
+
+func fetchJSON(ctx context.Context, url string, defaults []r2.Option, output interface{}) error {
+	res, err := r2.New(url, append(defaults,
+		r2.OptGet(),
+		r2.OptContext(ctx),
+	)...).Do()
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return ex.New(ErrProviderResponseStatus, ex.OptMessagef("url: %s, status code: %d", url, res.StatusCode))
+	}
+	return ex.New(json.NewDecoder(res.Body).Decode(output))
+}
+
+// claimStrings returns a claim that can be a string or an array of strings as a slice.
+func claimStrings(claim interface{}) []string {
+	switch typed := claim.(type) {
+	case string:
+		return []string{typed}
+	case []interface{}:
+		var output []string
+		for _, value := range typed {
+			if str, ok := value.(string); ok {
+				output = append(output, str)
+			}
+		}
+		return output
+	default:
+		return nil
+	}
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,321 @@
+package oauth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/jwt"
+)
+
+// testIdentityProvider is a minimal OpenID Connect provider.
+type testIdentityProvider struct {
+	sync.Mutex
+	*httptest.Server
+
+	Key        *rsa.PrivateKey
+	KID        string
+	KeyFetches int
+	// Codes are the issued authorization codes, mapped to the authorization request.
+	Codes map[string]url.Values
+}
+
+func newTestIdentityProvider(t *testing.T) *testIdentityProvider {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	idp := &testIdentityProvider{
+		Key:   key,
+		KID:   "key-1",
+		Codes: make(map[string]url.Values),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc(DiscoveryPath, func(rw http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(rw).Encode(Discovery{
+			Issuer:                idp.URL,
+			AuthorizationEndpoint: idp.URL + "/authorize",
+			TokenEndpoint:         idp.URL + "/token",
+			JWKSURI:               idp.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(rw http.ResponseWriter, r *http.Request) {
+		idp.Lock()
+		defer idp.Unlock()
+		idp.KeyFetches++
+		jwk, _ := jwt.NewJWK(idp.KID, jwt.SigningMethodNameRS256, &idp.Key.PublicKey)
+		json.NewEncoder(rw).Encode(jwt.JWKS{Keys: []jwt.JWK{jwk}})
+	})
+	mux.HandleFunc("/token", func(rw http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		idp.Lock()
+		authorization, ok := idp.Codes[r.PostForm.Get("code")]
+		idp.Unlock()
+		if !ok {
+			http.Error(rw, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+			return
+		}
+		if challenge := authorization.Get("code_challenge"); challenge != "" && CodeChallengeS256(r.PostForm.Get("code_verifier")) != challenge {
+			http.Error(rw, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+			return
+		}
+		claims := jwt.MapClaims{
+			"sub":   "user-1234",
+			"email": "bailey@foo.com",
+			"name":  "Bailey Dog",
+		}
+		if nonce := authorization.Get("nonce"); nonce != "" {
+			claims["nonce"] = nonce
+		}
+		rw.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(rw).Encode(map[string]interface{}{
+			"access_token": "access-token",
+			"token_type":   "bearer",
+			"expires_in":   3600,
+			"id_token":     idp.IDToken(authorization.Get("client_id"), claims),
+		})
+	})
+	idp.Server = httptest.NewServer(mux)
+	return idp
+}
+
+// Authorize simulates the user completing the authorization request, and returns the redirect.
+func (idp *testIdentityProvider) Authorize(oauthURL string) *http.Request {
+	parsed, _ := url.Parse(oauthURL)
+	query := parsed.Query()
+	idp.Lock()
+	code := "code-" + query.Get("state")[:8]
+	idp.Codes[code] = query
+	idp.Unlock()
+	redirect, _ := url.Parse(query.Get("redirect_uri"))
+	redirect.RawQuery = url.Values{"code": {code}, "state": {query.Get("state")}}.Encode()
+	return &http.Request{Method: http.MethodGet, URL: redirect, Header: http.Header{}}
+}
+
+// IDToken returns a signed id token.
+func (idp *testIdentityProvider) IDToken(audience string, claims jwt.MapClaims) string {
+	idp.Lock()
+	defer idp.Unlock()
+	tokenClaims := jwt.MapClaims{
+		"iss": idp.URL,
+		"aud": audience,
+		"iat": time.Now().Unix(),
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}
+	for key, value := range claims {
+		tokenClaims[key] = value
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, tokenClaims)
+	token.Header["kid"] = idp.KID
+	signed, _ := token.SignedString(idp.Key)
+	return signed
+}
+
+func TestManagerFinishProvider(t *testing.T) {
+	assert := assert.New(t)
+
+	idp := newTestIdentityProvider(t)
+	defer idp.Close()
+
+	provider, err := DiscoverProvider(context.Background(), idp.URL+"/")
+	assert.Nil(err)
+	assert.Equal(idp.URL+"/token", provider.Endpoint().TokenURL)
+	assert.Len(provider.Keys().Keys, 1)
+
+	m, err := New(
+		OptProvider(provider),
+		OptClientID("test-client"),
+		OptClientSecret("test-secret"),
+		OptSecret([]byte("test-key")),
+		OptPKCE(true),
+		OptRedirectURI("https://app.com/oauth/callback"),
+		OptHostedDomain("foo.com"),
+	)
+	assert.Nil(err)
+
+	oauthURL, err := m.OAuthURL(&http.Request{URL: &url.URL{}})
+	assert.Nil(err)
+	parsed, err := url.Parse(oauthURL)
+	assert.Nil(err)
+	assert.Equal(idp.URL+"/authorize", parsed.Scheme+"://"+parsed.Host+parsed.Path)
+	assert.NotEmpty(parsed.Query().Get("nonce"))
+	assert.NotEmpty(parsed.Query().Get("code_challenge"))
+	assert.Equal("S256", parsed.Query().Get("code_challenge_method"))
+	assert.Empty(parsed.Query().Get("hd"))
+	assert.Equal("openid email profile", parsed.Query().Get("scope"))
+
+	result, err := m.Finish(idp.Authorize(oauthURL))
+	assert.Nil(err)
+	assert.Equal("access-token", result.Response.AccessToken)
+	assert.NotEmpty(result.Response.IDToken)
+	assert.Equal("user-1234", result.Profile.ID)
+	assert.Equal("bailey@foo.com", result.Profile.Email)
+	assert.Equal("Bailey Dog", result.Profile.Name)
+	assert.Equal("test-client", result.Claims["aud"])
+	assert.Equal(parsed.Query().Get("nonce"), result.State.Nonce)
+	assert.Nil(m.ValidateProfile(&result.Profile))
+}
+
+func TestManagerFinishProviderPKCE(t *testing.T) {
+	assert := assert.New(t)
+
+	idp := newTestIdentityProvider(t)
+	defer idp.Close()
+
+	provider, err := DiscoverProvider(context.Background(), idp.URL)
+	assert.Nil(err)
+	m := MustNew(OptProvider(provider), OptClientID("test-client"), OptSecret([]byte("test-key")), OptPKCE(true))
+
+	oauthURL, err := m.OAuthURL(&http.Request{URL: &url.URL{}})
+	assert.Nil(err)
+	redirect := idp.Authorize(oauthURL)
+
+	// the code exchange fails without the code verifier.
+	m.PKCE = false
+	_, err = m.Finish(redirect)
+	assert.Equal(ErrFailedCodeExchange, ex.ErrClass(err))
+
+	m.PKCE = true
+	_, err = m.Finish(redirect)
+	assert.Nil(err)
+
+	m.Secret = nil
+	_, err = m.OAuthURL(&http.Request{URL: &url.URL{}})
+	assert.Equal(ErrSecretRequired, ex.ErrClass(err))
+}
+
+func TestManagerValidateStateNonce(t *testing.T) {
+	assert := assert.New(t)
+
+	m := MustNew(OptProvider(&Provider{}), OptSecret([]byte("test-key")))
+	state := m.CreateState()
+	assert.NotEmpty(state.Nonce)
+	assert.Nil(m.ValidateState(state))
+
+	state.Nonce = "not-the-nonce"
+	assert.Equal(ErrInvalidNonce, m.ValidateState(state))
+}
+
+func TestProviderVerifyIDToken(t *testing.T) {
+	assert := assert.New(t)
+
+	idp := newTestIdentityProvider(t)
+	defer idp.Close()
+
+	provider, err := DiscoverProvider(context.Background(), idp.URL)
+	assert.Nil(err)
+
+	claims, err := provider.VerifyIDToken(context.Background(), idp.IDToken("test-client", jwt.MapClaims{"nonce": "n"}), "test-client", "n")
+	assert.Nil(err)
+	assert.Equal("n", claims["nonce"])
+
+	_, err = provider.VerifyIDToken(context.Background(), idp.IDToken("test-client", jwt.MapClaims{"nonce": "n"}), "test-client", "other")
+	assert.Equal(ErrInvalidNonce, ex.ErrClass(err))
+
+	_, err = provider.VerifyIDToken(context.Background(), idp.IDToken("other-client", nil), "test-client", "")
+	assert.Equal(ErrInvalidIDToken, ex.ErrClass(err))
+
+	_, err = provider.VerifyIDToken(context.Background(), idp.IDToken("test-client", jwt.MapClaims{"iss": "https://not-the-issuer"}), "test-client", "")
+	assert.Equal(ErrInvalidIDToken, ex.ErrClass(err))
+
+	_, err = provider.VerifyIDToken(context.Background(), idp.IDToken("test-client", jwt.MapClaims{"exp": time.Now().Add(-time.Minute).Unix()}), "test-client", "")
+	assert.Equal(ErrInvalidIDToken, ex.ErrClass(err))
+
+	// multiple audiences require the authorized party to be the client.
+	_, err = provider.VerifyIDToken(context.Background(), idp.IDToken("", jwt.MapClaims{"aud": []string{"test-client", "other-client"}}), "test-client", "")
+	assert.Equal(ErrInvalidIDToken, ex.ErrClass(err))
+	_, err = provider.VerifyIDToken(context.Background(), idp.IDToken("", jwt.MapClaims{"aud": []string{"test-client", "other-client"}, "azp": "test-client"}), "test-client", "")
+	assert.Nil(err)
+
+	// hmac signed tokens are rejected.
+	hmacToken, err := jwt.NewWithClaims(jwt.SigningMethodHMAC256, jwt.MapClaims{"iss": idp.URL, "aud": "test-client"}).SignedString([]byte("test-secret"))
+	assert.Nil(err)
+	_, err = provider.VerifyIDToken(context.Background(), hmacToken, "test-client", "")
+	assert.Equal(ErrInvalidIDToken, ex.ErrClass(err))
+}
+
+func TestProviderKeyRotation(t *testing.T) {
+	assert := assert.New(t)
+
+	idp := newTestIdentityProvider(t)
+	defer idp.Close()
+
+	provider, err := DiscoverProvider(context.Background(), idp.URL)
+	assert.Nil(err)
+	provider.KeysRefreshInterval = time.Nanosecond
+	assert.Equal(1, idp.KeyFetches)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(err)
+	idp.Lock()
+	idp.Key, idp.KID = key, "key-2"
+	idp.Unlock()
+
+	_, err = provider.VerifyIDToken(context.Background(), idp.IDToken("test-client", nil), "test-client", "")
+	assert.Nil(err)
+	assert.Equal(2, idp.KeyFetches)
+	_, ok := provider.Keys().Key("key-2")
+	assert.True(ok)
+}
+
+func TestDiscoverProviderIssuerMismatch(t *testing.T) {
+	assert := assert.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(rw).Encode(Discovery{Issuer: "https://not-the-issuer"})
+	}))
+	defer server.Close()
+
+	_, err := DiscoverProvider(context.Background(), server.URL)
+	assert.Equal(ErrProviderDiscovery, ex.ErrClass(err))
+}
+
+func TestOptConfigDiscoveryTimeout(t *testing.T) {
+	assert := assert.New(t)
+
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-done:
+		}
+	}))
+	defer server.Close()
+	defer close(done)
+
+	started := time.Now()
+	_, err := New(OptConfig(Config{Issuer: server.URL, DiscoveryTimeout: 50 * time.Millisecond}))
+	assert.Equal(ErrProviderDiscovery, ex.ErrClass(err))
+	assert.True(time.Since(started) < 5*time.Second)
+}
+
+func TestClaimMapProfile(t *testing.T) {
+	assert := assert.New(t)
+
+	claims := map[string]interface{}{
+		"sub":            "user-1234",
+		"email":          "bailey@foo.com",
+		"email_verified": "true",
+		"upn":            "bailey@corp.foo.com",
+		"employee_id":    float64(1234),
+	}
+	profile := DefaultClaimMap.Profile(claims)
+	assert.Equal("user-1234", profile.ID)
+	assert.Equal("bailey@foo.com", profile.Email)
+	assert.True(profile.VerifiedEmail)
+
+	profile = ClaimMap{ID: "employee_id", Email: "upn"}.Profile(claims)
+	assert.Equal("1234", profile.ID)
+	assert.Equal("bailey@corp.foo.com", profile.Email)
+	assert.False(profile.VerifiedEmail)
+	assert.Empty(profile.Name)
+}
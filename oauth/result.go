@@ -8,6 +8,8 @@ type Result struct {
 	Response Response
 	Profile  Profile
 	State    State
+	// Claims are the verified id token claims if the manager uses an OpenID Connect provider.
+	Claims Values
 }
 
 // Response is the response details from the oauth exchange.
@@ -16,4 +18,5 @@ type Response struct {
 	TokenType    string
 	RefreshToken string
 	Expiry       time.Time
+	IDToken      string
 }
@@ -17,6 +17,9 @@ type State struct {
 	SecureToken string
 	// RedirectURI is the redirect uri.
 	RedirectURI string
+	// Nonce is sent with the authorization request to an OpenID Connect provider,
+	// and must match the `nonce` claim of the returned id token.
+	Nonce string
 	// Extra includes other state you might need to encode.
 	Extra map[string]interface{}
 }
@@ -0,0 +1,20 @@
+package openapi
+
+import "github.com/blend/go-sdk/ex"
+
+const (
+	// ErrUnsupportedVersion is returned if a spec is not an OpenAPI 3 spec.
+	ErrUnsupportedVersion ex.Class = "openapi; unsupported openapi version"
+	// ErrInvalidSpec is returned if a client cannot be generated for a spec, e.g. if a reference cannot be resolved.
+	ErrInvalidSpec ex.Class = "openapi; invalid spec"
+	// ErrInvalidSource is returned if the generated client is not valid go source.
+	ErrInvalidSource ex.Class = "openapi; generated source is invalid"
+)
+
+// IsErrInvalidSpec returns if an error is an invalid spec error.
+func IsErrInvalidSpec(err error) bool {
+	if err == nil {
+		return false
+	}
+	return ex.Is(err, ErrInvalidSpec)
+}
@@ -0,0 +1,757 @@
+package openapi
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/blend/go-sdk/ex"
+)
+
+const contentTypeJSON = "application/json"
+
+// methods are the http methods in the order operations are generated for a path.
+var methods = []string{"GET", "HEAD", "OPTIONS", "POST", "PUT", "PATCH", "DELETE"}
+
+// Generate generates a client for a given spec.
+func Generate(spec *Spec, packageName string) ([]byte, error) {
+	g := &generator{
+		spec:     spec,
+		declared: make(map[string]bool),
+	}
+	if err := g.generate(); err != nil {
+		return nil, err
+	}
+
+	output := new(bytes.Buffer)
+	fmt.Fprintf(output, "// Code generated by apiclient; DO NOT EDIT.\n\n")
+	if spec.Info.Title != "" {
+		fmt.Fprintf(output, "// Package %s is a client for the %s api.\n", packageName, spec.Info.Title)
+	}
+	fmt.Fprintf(output, "package %s\n\n", packageName)
+	fmt.Fprintf(output, "import (\n")
+	fmt.Fprintf(output, "\t\"context\"\n\t\"encoding/json\"\n\t\"fmt\"\n\t\"io\"\n\t\"net/url\"\n\t\"strings\"\n")
+	if g.usesTime {
+		fmt.Fprintf(output, "\t\"time\"\n")
+	}
+	fmt.Fprintf(output, "\n\t\"github.com/blend/go-sdk/ex\"\n\t\"github.com/blend/go-sdk/r2\"\n)\n\n")
+	output.WriteString(clientPreamble)
+	output.Write(g.types.Bytes())
+	output.Write(g.methods.Bytes())
+
+	formatted, err := format.Source(output.Bytes())
+	if err != nil {
+		return nil, ex.New(ErrInvalidSource, ex.OptInner(err))
+	}
+	return formatted, nil
+}
+
+// clientPreamble is the client type and the helpers shared by the generated methods.
+const clientPreamble = `// New returns a new client for a given base url.
+// The default options are applied to every request the client makes.
+func New(baseURL string, defaults ...r2.Option) *Client {
+	return &Client{
+		BaseURL:  baseURL,
+		Defaults: defaults,
+	}
+}
+
+// Client is an api client.
+type Client struct {
+	BaseURL  string
+	Defaults r2.Defaults
+}
+
+// APIError is returned if the api responds with a non-2xx status code.
+// Value is set to the typed error response if the spec declares one for the status code.
+type APIError struct {
+	StatusCode int
+	Body       []byte
+	Value      interface{}
+}
+
+// Error implements error.
+func (e *APIError) Error() string {
+	return fmt.Sprintf("api error; status code: %d; body: %s", e.StatusCode, string(e.Body))
+}
+
+// request returns a new request with the client defaults, the given options and the per call options.
+func (c *Client) request(ctx context.Context, path string, options []r2.Option, callOptions []r2.Option) *r2.Request {
+	prefix := ""
+	if parsed, err := url.Parse(c.BaseURL); err == nil {
+		prefix = strings.TrimSuffix(parsed.EscapedPath(), "/")
+	}
+	all := append(r2.Defaults{}, c.Defaults...)
+	all = append(all, r2.OptContext(ctx), r2.OptEscapedPath(prefix+path))
+	all = append(all, options...)
+	all = append(all, callOptions...)
+	return r2.New(c.BaseURL, all...)
+}
+
+// send sends a request and decodes the response into the output, or returns an *APIError.
+func (c *Client) send(req *r2.Request, output interface{}, errorOutput func(int) interface{}) error {
+	var raw json.RawMessage
+	res, err := req.JSONWithResponse(&raw)
+	if res == nil {
+		return err
+	}
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		apiErr := &APIError{StatusCode: res.StatusCode, Body: []byte(raw)}
+		if errorOutput != nil {
+			if value := errorOutput(res.StatusCode); value != nil && len(raw) > 0 && json.Unmarshal(raw, value) == nil {
+				apiErr.Value = value
+			}
+		}
+		return apiErr
+	}
+	if err != nil && !ex.Is(err, io.EOF) {
+		return err
+	}
+	if output == nil || len(raw) == 0 {
+		return nil
+	}
+	return ex.New(json.Unmarshal(raw, output))
+}
+
+`
+
+type generator struct {
+	spec     *Spec
+	types    bytes.Buffer
+	methods  bytes.Buffer
+	declared map[string]bool
+	usesTime bool
+}
+
+func (g *generator) generate() error {
+	for _, name := range sortedKeys(g.spec.Components.Schemas) {
+		if err := g.declareSchema(exportedName(name), g.spec.Components.Schemas[name]); err != nil {
+			return err
+		}
+	}
+	paths := make([]string, 0, len(g.spec.Paths))
+	for path := range g.spec.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		item := g.spec.Paths[path]
+		operations := item.Operations()
+		for _, method := range methods {
+			if operation, ok := operations[method]; ok {
+				if err := g.generateOperation(method, path, item, operation); err != nil {
+					return ex.New(ErrInvalidSpec, ex.OptMessagef("%s %s", method, path), ex.OptInner(err))
+				}
+			}
+		}
+	}
+	return nil
+}
+
+//
+// types
+//
+
+// declareSchema declares a named type for a schema.
+func (g *generator) declareSchema(name string, schema *Schema) error {
+	if g.declared[name] {
+		return nil
+	}
+	g.declared[name] = true
+
+	if schema.Ref != "" {
+		target, err := g.typeExpr(schema, name)
+		if err != nil {
+			return err
+		}
+		writeComment(&g.types, name, schema.Description)
+		fmt.Fprintf(&g.types, "type %s = %s\n\n", name, target)
+		return nil
+	}
+
+	if schema.Type == "string" && len(schema.Enum) > 0 {
+		writeComment(&g.types, name, schema.Description)
+		fmt.Fprintf(&g.types, "type %s string\n\n", name)
+		fmt.Fprintf(&g.types, "// %s values.\nconst (\n", name)
+		for _, value := range schema.Enum {
+			fmt.Fprintf(&g.types, "\t%s%s %s = %s\n", name, exportedName(fmt.Sprint(value)), name, strconv.Quote(fmt.Sprint(value)))
+		}
+		fmt.Fprintf(&g.types, ")\n\n")
+		return nil
+	}
+
+	if !isStruct(schema) {
+		target, err := g.typeExpr(schema, name+"Item")
+		if err != nil {
+			return err
+		}
+		writeComment(&g.types, name, schema.Description)
+		fmt.Fprintf(&g.types, "type %s %s\n\n", name, target)
+		return nil
+	}
+
+	// declare the fields first so nested types are written before they're referenced.
+	fields := new(bytes.Buffer)
+	for _, property := range sortedKeys(schema.Properties) {
+		propertySchema := schema.Properties[property]
+		fieldType, err := g.typeExpr(propertySchema, name+exportedName(property))
+		if err != nil {
+			return err
+		}
+		required := schema.IsRequired(property)
+		if !required && g.isPointable(propertySchema) {
+			fieldType = "*" + fieldType
+		}
+		tag := property
+		if !required {
+			tag += ",omitempty"
+		}
+		if propertySchema.Description != "" {
+			writeComment(fields, "", propertySchema.Description)
+		}
+		fmt.Fprintf(fields, "%s %s `json:%s`\n", exportedName(property), fieldType, strconv.Quote(tag))
+	}
+	writeComment(&g.types, name, schema.Description)
+	fmt.Fprintf(&g.types, "type %s struct {\n%s}\n\n", name, fields.String())
+	return nil
+}
+
+// typeExpr returns the go type expression for a schema, declaring named types
+// for inline objects using the name hint.
+func (g *generator) typeExpr(schema *Schema, nameHint string) (string, error) {
+	if schema == nil {
+		return "interface{}", nil
+	}
+	if schema.Ref != "" {
+		name, err := refName(schema.Ref, "#/components/schemas/")
+		if err != nil {
+			return "", err
+		}
+		if _, ok := g.spec.Components.Schemas[name]; !ok {
+			return "", ex.New(ErrInvalidSpec, ex.OptMessagef("schema not found: %s", schema.Ref))
+		}
+		return exportedName(name), nil
+	}
+	switch schema.Type {
+	case "string":
+		switch schema.Format {
+		case "date-time":
+			g.usesTime = true
+			return "time.Time", nil
+		case "byte":
+			return "[]byte", nil
+		}
+		return "string", nil
+	case "integer":
+		if schema.Format == "int32" {
+			return "int32", nil
+		}
+		return "int64", nil
+	case "number":
+		if schema.Format == "float" {
+			return "float32", nil
+		}
+		return "float64", nil
+	case "boolean":
+		return "bool", nil
+	case "array":
+		itemType, err := g.typeExpr(schema.Items, nameHint+"Item")
+		if err != nil {
+			return "", err
+		}
+		return "[]" + itemType, nil
+	}
+	if isStruct(schema) {
+		if err := g.declareSchema(nameHint, schema); err != nil {
+			return "", err
+		}
+		return nameHint, nil
+	}
+	if additional, ok := schema.AdditionalPropertiesSchema(); ok {
+		valueType, err := g.typeExpr(additional, nameHint+"Value")
+		if err != nil {
+			return "", err
+		}
+		return "map[string]" + valueType, nil
+	}
+	if schema.Type == "object" {
+		return "map[string]interface{}", nil
+	}
+	return "interface{}", nil
+}
+
+// isPointable returns if optional values of the schema should be pointers.
+func (g *generator) isPointable(schema *Schema) bool {
+	if schema.Ref != "" {
+		name, err := refName(schema.Ref, "#/components/schemas/")
+		if err != nil {
+			return false
+		}
+		if target, ok := g.spec.Components.Schemas[name]; ok {
+			return g.isPointable(target)
+		}
+		return false
+	}
+	switch schema.Type {
+	case "array":
+		return false
+	case "string":
+		return schema.Format != "byte"
+	case "integer", "number", "boolean":
+		return true
+	}
+	if isStruct(schema) {
+		return true
+	}
+	return false
+}
+
+func isStruct(schema *Schema) bool {
+	return schema.Ref == "" && (schema.Type == "object" || schema.Type == "") && len(schema.Properties) > 0
+}
+
+//
+// operations
+//
+
+type operationParam struct {
+	Parameter
+	GoName  string
+	ArgName string
+	Type    string
+}
+
+type operationResponse struct {
+	Code string
+	Type string
+}
+
+func (g *generator) generateOperation(method, path string, item PathItem, operation *Operation) error {
+	name := operationName(method, path, operation)
+
+	params, err := g.resolveParameters(item.Parameters, operation.Parameters)
+	if err != nil {
+		return err
+	}
+
+	var pathParams, otherParams []operationParam
+	for _, param := range params {
+		fieldType, err := g.typeExpr(param.Schema, name+exportedName(param.Name))
+		if err != nil {
+			return err
+		}
+		op := operationParam{
+			Parameter: param,
+			GoName:    exportedName(param.Name),
+			ArgName:   unexportedName(param.Name),
+			Type:      fieldType,
+		}
+		switch param.In {
+		case "path":
+			pathParams = append(pathParams, op)
+		case "query", "header":
+			otherParams = append(otherParams, op)
+		}
+	}
+	pathParams, err = orderPathParams(path, pathParams)
+	if err != nil {
+		return err
+	}
+
+	var bodyType string
+	if operation.RequestBody != nil {
+		body, err := g.resolveRequestBody(*operation.RequestBody)
+		if err != nil {
+			return err
+		}
+		if media, ok := body.Content[contentTypeJSON]; ok {
+			if bodyType, err = g.typeExpr(media.Schema, name+"Request"); err != nil {
+				return err
+			}
+		}
+	}
+
+	var successType string
+	var successByValue bool
+	var errorResponses []operationResponse
+	for _, code := range sortedKeys(operation.Responses) {
+		response, err := g.resolveResponse(operation.Responses[code])
+		if err != nil {
+			return err
+		}
+		media, ok := response.Content[contentTypeJSON]
+		if !ok || media.Schema == nil {
+			continue
+		}
+		if strings.HasPrefix(code, "2") {
+			if successType == "" {
+				if successType, err = g.typeExpr(media.Schema, name+"Response"); err != nil {
+					return err
+				}
+				successByValue = !g.isPointable(media.Schema)
+			}
+			continue
+		}
+		responseType, err := g.typeExpr(media.Schema, name+errorTypeSuffix(code))
+		if err != nil {
+			return err
+		}
+		errorResponses = append(errorResponses, operationResponse{Code: code, Type: responseType})
+	}
+
+	if len(otherParams) > 0 {
+		fields := new(bytes.Buffer)
+		for _, param := range otherParams {
+			fieldType := param.Type
+			if !param.Required && g.isPointable(param.Schema) {
+				fieldType = "*" + fieldType
+			}
+			if param.Description != "" {
+				writeComment(fields, "", param.Description)
+			}
+			fmt.Fprintf(fields, "%s %s\n", param.GoName, fieldType)
+		}
+		fmt.Fprintf(&g.types, "// %sParams are the query and header parameters for `%s`.\n", name, name)
+		fmt.Fprintf(&g.types, "type %sParams struct {\n%s}\n\n", name, fields.String())
+	}
+
+	// signature
+	args := []string{"ctx context.Context"}
+	for _, param := range pathParams {
+		args = append(args, param.ArgName+" "+param.Type)
+	}
+	if len(otherParams) > 0 {
+		args = append(args, "params "+name+"Params")
+	}
+	if bodyType != "" {
+		args = append(args, "body "+bodyType)
+	}
+	args = append(args, "options ...r2.Option")
+
+	var returns string
+	switch {
+	case successType == "":
+		returns = "error"
+	case successByValue:
+		returns = "(" + successType + ", error)"
+	default:
+		returns = "(*" + successType + ", error)"
+	}
+
+	summary := operation.Summary
+	if summary == "" {
+		summary = operation.Description
+	}
+	fmt.Fprintf(&g.methods, "// %s calls `%s %s`.\n", name, method, path)
+	writeDescription(&g.methods, summary)
+	if operation.Deprecated {
+		fmt.Fprintf(&g.methods, "//\n// Deprecated: this operation is deprecated by the api.\n")
+	}
+	fmt.Fprintf(&g.methods, "func (c *Client) %s(%s) %s {\n", name, strings.Join(args, ", "), returns)
+
+	// path
+	pathFormat, pathArgs := formatPath(path, pathParams)
+	if len(pathArgs) > 0 {
+		fmt.Fprintf(&g.methods, "path := fmt.Sprintf(%s, %s)\n", strconv.Quote(pathFormat), strings.Join(pathArgs, ", "))
+	} else {
+		fmt.Fprintf(&g.methods, "path := %s\n", strconv.Quote(pathFormat))
+	}
+
+	// request options
+	fmt.Fprintf(&g.methods, "requestOptions := []r2.Option{r2.OptMethod(%s)}\n", strconv.Quote(method))
+	var hasQuery bool
+	for _, param := range otherParams {
+		if param.In == "query" {
+			hasQuery = true
+		}
+	}
+	if hasQuery {
+		fmt.Fprintf(&g.methods, "query := url.Values{}\n")
+	}
+	for _, param := range otherParams {
+		field := "params." + param.GoName
+		var set string
+		if param.In == "query" {
+			set = fmt.Sprintf("query.Add(%s, fmt.Sprint(%%s))\n", strconv.Quote(param.Name))
+		} else {
+			set = fmt.Sprintf("requestOptions = append(requestOptions, r2.OptHeaderValue(%s, fmt.Sprint(%%s)))\n", strconv.Quote(param.Name))
+		}
+		pointer := !param.Required && g.isPointable(param.Schema)
+		switch {
+		case strings.HasPrefix(param.Type, "[]") && param.Type != "[]byte":
+			fmt.Fprintf(&g.methods, "for _, value := range %s {\n", field)
+			fmt.Fprintf(&g.methods, set, "value")
+			fmt.Fprintf(&g.methods, "}\n")
+		case pointer:
+			fmt.Fprintf(&g.methods, "if %s != nil {\n", field)
+			fmt.Fprintf(&g.methods, set, "*"+field)
+			fmt.Fprintf(&g.methods, "}\n")
+		default:
+			fmt.Fprintf(&g.methods, set, field)
+		}
+	}
+	if hasQuery {
+		fmt.Fprintf(&g.methods, "requestOptions = append(requestOptions, r2.OptQuery(query))\n")
+	}
+	if bodyType != "" {
+		fmt.Fprintf(&g.methods, "requestOptions = append(requestOptions, r2.OptJSONBody(body))\n")
+	}
+	fmt.Fprintf(&g.methods, "req := c.request(ctx, path, requestOptions, options)\n")
+
+	// error outputs
+	errorOutput := "nil"
+	if len(errorResponses) > 0 {
+		var cases, defaultType string
+		for _, response := range errorResponses {
+			condition, ok := statusCondition(response.Code)
+			if !ok {
+				defaultType = response.Type
+				continue
+			}
+			cases += fmt.Sprintf("case %s:\nreturn new(%s)\n", condition, response.Type)
+		}
+		errorOutput = "func(statusCode int) interface{} {\n"
+		if cases != "" {
+			errorOutput += "switch {\n" + cases + "}\n"
+		}
+		if defaultType != "" {
+			errorOutput += fmt.Sprintf("return new(%s)\n}", defaultType)
+		} else {
+			errorOutput += "return nil\n}"
+		}
+	}
+
+	// send
+	switch {
+	case successType == "":
+		fmt.Fprintf(&g.methods, "return c.send(req, nil, %s)\n", errorOutput)
+	case !successByValue:
+		fmt.Fprintf(&g.methods, "var output %s\n", successType)
+		fmt.Fprintf(&g.methods, "if err := c.send(req, &output, %s); err != nil {\nreturn nil, err\n}\n", errorOutput)
+		fmt.Fprintf(&g.methods, "return &output, nil\n")
+	default:
+		fmt.Fprintf(&g.methods, "var output %s\n", successType)
+		fmt.Fprintf(&g.methods, "if err := c.send(req, &output, %s); err != nil {\nreturn nil, err\n}\n", errorOutput)
+		fmt.Fprintf(&g.methods, "return output, nil\n")
+	}
+	fmt.Fprintf(&g.methods, "}\n\n")
+	return nil
+}
+
+func (g *generator) resolveParameters(pathLevel, operationLevel []Parameter) ([]Parameter, error) {
+	var output []Parameter
+	index := make(map[string]int)
+	for _, param := range append(append([]Parameter{}, pathLevel...), operationLevel...) {
+		if param.Ref != "" {
+			name, err := refName(param.Ref, "#/components/parameters/")
+			if err != nil {
+				return nil, err
+			}
+			resolved, ok := g.spec.Components.Parameters[name]
+			if !ok {
+				return nil, ex.New(ErrInvalidSpec, ex.OptMessagef("parameter not found: %s", param.Ref))
+			}
+			param = resolved
+		}
+		if param.In == "path" {
+			param.Required = true
+		}
+		key := param.In + ":" + param.Name
+		if existing, ok := index[key]; ok {
+			output[existing] = param
+			continue
+		}
+		index[key] = len(output)
+		output = append(output, param)
+	}
+	return output, nil
+}
+
+func (g *generator) resolveRequestBody(body RequestBody) (RequestBody, error) {
+	if body.Ref == "" {
+		return body, nil
+	}
+	name, err := refName(body.Ref, "#/components/requestBodies/")
+	if err != nil {
+		return body, err
+	}
+	resolved, ok := g.spec.Components.RequestBodies[name]
+	if !ok {
+		return body, ex.New(ErrInvalidSpec, ex.OptMessagef("request body not found: %s", body.Ref))
+	}
+	return resolved, nil
+}
+
+func (g *generator) resolveResponse(response Response) (Response, error) {
+	if response.Ref == "" {
+		return response, nil
+	}
+	name, err := refName(response.Ref, "#/components/responses/")
+	if err != nil {
+		return response, err
+	}
+	resolved, ok := g.spec.Components.Responses[name]
+	if !ok {
+		return response, ex.New(ErrInvalidSpec, ex.OptMessagef("response not found: %s", response.Ref))
+	}
+	return resolved, nil
+}
+
+//
+// helpers
+//
+
+// operationName returns the method name for an operation, falling back to the
+// method and path if the operation has no id.
+func operationName(method, path string, operation *Operation) string {
+	if operation.OperationID != "" {
+		return exportedName(operation.OperationID)
+	}
+	return exportedName(strings.ToLower(method) + " " + strings.NewReplacer("{", " by ", "}", " ").Replace(path))
+}
+
+// orderPathParams orders path params in the order they appear in the path template.
+func orderPathParams(path string, params []operationParam) ([]operationParam, error) {
+	var output []operationParam
+	for _, segment := range pathTemplateNames(path) {
+		var found bool
+		for _, param := range params {
+			if param.Name == segment {
+				output = append(output, param)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, ex.New(ErrInvalidSpec, ex.OptMessagef("path parameter not declared: %s", segment))
+		}
+	}
+	return output, nil
+}
+
+// formatPath returns the escaped path as a format string and the arguments for it.
+// The arguments escape the parameter values so they cannot add path segments or a query.
+func formatPath(path string, params []operationParam) (string, []string) {
+	segments := strings.Split(path, "/")
+	for index, segment := range segments {
+		segments[index] = escapePathTemplate(segment)
+	}
+	format := strings.Replace(strings.Join(segments, "/"), "%", "%%", -1)
+
+	var args []string
+	for _, param := range params {
+		format = strings.Replace(format, "{"+param.Name+"}", "%s", 1)
+		args = append(args, fmt.Sprintf("url.PathEscape(fmt.Sprint(%s))", param.ArgName))
+	}
+	return format, args
+}
+
+// escapePathTemplate escapes a path segment, leaving its `{name}` templates as is.
+func escapePathTemplate(segment string) string {
+	var output strings.Builder
+	for {
+		start := strings.Index(segment, "{")
+		if start < 0 {
+			break
+		}
+		end := strings.Index(segment[start:], "}")
+		if end < 0 {
+			break
+		}
+		output.WriteString(url.PathEscape(segment[:start]))
+		output.WriteString(segment[start : start+end+1])
+		segment = segment[start+end+1:]
+	}
+	output.WriteString(url.PathEscape(segment))
+	return output.String()
+}
+
+func pathTemplateNames(path string) (names []string) {
+	for {
+		start := strings.Index(path, "{")
+		if start < 0 {
+			return
+		}
+		end := strings.Index(path[start:], "}")
+		if end < 0 {
+			return
+		}
+		names = append(names, path[start+1:start+end])
+		path = path[start+end+1:]
+	}
+}
+
+// statusCondition returns the switch condition for a response code, e.g. `404` or `4XX`.
+// It returns false for the `default` response.
+func statusCondition(code string) (string, bool) {
+	if len(code) == 3 && strings.HasSuffix(strings.ToUpper(code), "XX") {
+		class, err := strconv.Atoi(code[:1])
+		if err != nil {
+			return "", false
+		}
+		return fmt.Sprintf("statusCode >= %d && statusCode < %d", class*100, (class+1)*100), true
+	}
+	if _, err := strconv.Atoi(code); err == nil {
+		return "statusCode == " + code, true
+	}
+	return "", false
+}
+
+func refName(ref, prefix string) (string, error) {
+	if !strings.HasPrefix(ref, prefix) {
+		return "", ex.New(ErrInvalidSpec, ex.OptMessagef("unsupported reference: %s", ref))
+	}
+	return strings.TrimPrefix(ref, prefix), nil
+}
+
+// writeComment writes a doc comment for a generated type, or for a field if the name is empty.
+func writeComment(output *bytes.Buffer, name, description string) {
+	if name == "" {
+		for _, line := range strings.Split(strings.TrimSpace(description), "\n") {
+			fmt.Fprintf(output, "// %s\n", strings.TrimSpace(line))
+		}
+		return
+	}
+	fmt.Fprintf(output, "// %s is generated from the api spec.\n", name)
+	writeDescription(output, description)
+}
+
+// writeDescription writes a description as an additional paragraph of a doc comment.
+func writeDescription(output *bytes.Buffer, description string) {
+	description = strings.TrimSpace(description)
+	if description == "" {
+		return
+	}
+	fmt.Fprintf(output, "//\n")
+	for _, line := range strings.Split(description, "\n") {
+		fmt.Fprintf(output, "// %s\n", strings.TrimSpace(line))
+	}
+}
+
+// errorTypeSuffix returns the type name suffix for an inline error response schema.
+func errorTypeSuffix(code string) string {
+	if code == "default" {
+		return "DefaultError"
+	}
+	return strings.ToUpper(code) + "Error"
+}
+
+func sortedKeys(values interface{}) []string {
+	var keys []string
+	switch typed := values.(type) {
+	case map[string]*Schema:
+		for key := range typed {
+			keys = append(keys, key)
+		}
+	case map[string]Response:
+		for key := range typed {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,139 @@
+package openapi
+
+import (
+	"go/ast"
+	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/ioutil"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestGenerate(t *testing.T) {
+	assert := assert.New(t)
+
+	spec, err := ReadSpec("testdata/petstore.yml")
+	assert.Nil(err)
+
+	contents, err := Generate(spec, "petstore")
+	assert.Nil(err)
+	output := string(contents)
+
+	assert.Contains(output, "package petstore")
+	assert.Contains(output, "\"time\"")
+
+	// types
+	assert.Contains(output, "type Pet struct {")
+	assert.Contains(output, "ID      int64      `json:\"id\"`")
+	assert.Contains(output, "Tag     *string    `json:\"tag,omitempty\"`")
+	assert.Contains(output, "Owner   *PetOwner  `json:\"owner,omitempty\"`")
+	assert.Contains(output, "type Pets []Pet")
+	assert.Contains(output, "PetStatusAvailable PetStatus = \"available\"")
+	assert.Contains(output, "type ShowPetByID404Error struct {")
+
+	// methods
+	assert.Contains(output, "func (c *Client) ListPets(ctx context.Context, params ListPetsParams, options ...r2.Option) (Pets, error) {")
+	assert.Contains(output, "query.Add(\"limit\", fmt.Sprint(*params.Limit))")
+	assert.Contains(output, "r2.OptHeaderValue(\"X-Request-ID\", fmt.Sprint(*params.XRequestID))")
+	assert.Contains(output, "func (c *Client) CreatePets(ctx context.Context, body NewPet, options ...r2.Option) error {")
+	assert.Contains(output, "r2.OptJSONBody(body)")
+	assert.Contains(output, "case statusCode >= 400 && statusCode < 500:")
+	assert.Contains(output, "func (c *Client) ShowPetByID(ctx context.Context, petID string, options ...r2.Option) (*Pet, error) {")
+	assert.Contains(output, "path := fmt.Sprintf(\"/pets/%s\", url.PathEscape(fmt.Sprint(petID)))")
+	assert.Contains(output, "func (c *Client) DeletePet(ctx context.Context, petID string, options ...r2.Option) error {")
+	assert.Contains(output, "func (c *Client) PutOwnersByOwnerIDPetsByPetIDTags(ctx context.Context, ownerID string, petID int64, body map[string]string, options ...r2.Option) (map[string]string, error) {")
+	assert.Contains(output, "JSONWithResponse")
+}
+
+func TestGenerateTypeChecks(t *testing.T) {
+	assert := assert.New(t)
+
+	spec, err := ReadSpec("testdata/petstore.yml")
+	assert.Nil(err)
+	contents, err := Generate(spec, "petstore")
+	assert.Nil(err)
+
+	formatted, err := format.Source(contents)
+	assert.Nil(err)
+	assert.Equal(string(formatted), string(contents))
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "client.go", contents, parser.ParseComments)
+	assert.Nil(err)
+	config := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	_, err = config.Check("petstore", fset, []*ast.File{file}, nil)
+	assert.Nil(err)
+}
+
+func TestGenerateExample(t *testing.T) {
+	assert := assert.New(t)
+
+	// the example client has a round trip test against a server, so it must match the generator.
+	spec, err := ReadSpec("testdata/petstore.yml")
+	assert.Nil(err)
+	contents, err := Generate(spec, "petstore")
+	assert.Nil(err)
+	example, err := ioutil.ReadFile("../examples/openapi/petstore/client.go")
+	assert.Nil(err)
+	assert.Equal(string(example), string(contents), "the example client is out of date; run `go generate ./examples/openapi/petstore`")
+}
+
+func TestFormatPath(t *testing.T) {
+	assert := assert.New(t)
+
+	// the literal parts of the path are escaped when the client is generated.
+	format, args := formatPath("/files/{dir}/{name} 100%", []operationParam{
+		{Parameter: Parameter{Name: "dir"}, ArgName: "dir"},
+		{Parameter: Parameter{Name: "name"}, ArgName: "name"},
+	})
+	assert.Equal("/files/%s/%s%%20100%%25", format)
+	assert.Equal([]string{"url.PathEscape(fmt.Sprint(dir))", "url.PathEscape(fmt.Sprint(name))"}, args)
+}
+
+func TestGenerateInvalidRef(t *testing.T) {
+	assert := assert.New(t)
+
+	spec, err := ParseSpec([]byte(`
+openapi: 3.0.0
+paths:
+  /foo:
+    get:
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/Missing"
+`))
+	assert.Nil(err)
+	_, err = Generate(spec, "foo")
+	assert.True(IsErrInvalidSpec(err))
+}
+
+func TestParseSpecVersion(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := ParseSpec([]byte(`{"swagger": "2.0"}`))
+	assert.NotNil(err)
+}
+
+func TestNames(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("PetID", exportedName("petId"))
+	assert.Equal("ShowPetByID", exportedName("showPetById"))
+	assert.Equal("XRequestID", exportedName("X-Request-ID"))
+	assert.Equal("HTTPServer", exportedName("HTTPServer"))
+	assert.Equal("UserURL", exportedName("user_url"))
+	assert.Equal("X404", exportedName("404"))
+
+	assert.Equal("petID", unexportedName("petId"))
+	assert.Equal("id", unexportedName("ID"))
+	assert.Equal("typeParam", unexportedName("type"))
+	assert.Equal("bodyParam", unexportedName("body"))
+}
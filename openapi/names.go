@@ -0,0 +1,89 @@
+package openapi
+
+import (
+	"go/token"
+	"strings"
+	"unicode"
+)
+
+// initialisms are name tokens that are rendered in upper case, following the go style guide.
+var initialisms = map[string]bool{
+	"API": true, "ASCII": true, "CPU": true, "CSS": true, "DNS": true, "EOF": true,
+	"GUID": true, "HTML": true, "HTTP": true, "HTTPS": true, "ID": true, "IP": true,
+	"JSON": true, "JWT": true, "OS": true, "SQL": true, "SSH": true, "TLS": true,
+	"TTL": true, "UI": true, "UID": true, "URI": true, "URL": true, "UTC": true,
+	"UUID": true, "XML": true,
+}
+
+// reservedArgNames are the names used by generated method bodies.
+var reservedArgNames = map[string]bool{
+	"c": true, "ctx": true, "options": true, "params": true, "body": true,
+	"query": true, "output": true, "err": true, "req": true,
+}
+
+// exportedName returns an exported go identifier for a given spec name.
+func exportedName(name string) string {
+	var output strings.Builder
+	for _, part := range nameParts(name) {
+		if upper := strings.ToUpper(part); initialisms[upper] {
+			output.WriteString(upper)
+			continue
+		}
+		runes := []rune(strings.ToLower(part))
+		runes[0] = unicode.ToUpper(runes[0])
+		output.WriteString(string(runes))
+	}
+	result := output.String()
+	if result == "" {
+		return "X"
+	}
+	if unicode.IsDigit([]rune(result)[0]) {
+		return "X" + result
+	}
+	return result
+}
+
+// unexportedName returns an unexported go identifier for a given spec name that is safe
+// to use as a method argument.
+func unexportedName(name string) string {
+	parts := nameParts(name)
+	if len(parts) == 0 {
+		return "x"
+	}
+	result := strings.ToLower(parts[0]) + strings.TrimPrefix(exportedName(name), exportedName(parts[0]))
+	if unicode.IsDigit([]rune(result)[0]) {
+		result = "x" + result
+	}
+	if token.IsKeyword(result) || reservedArgNames[result] {
+		result = result + "Param"
+	}
+	return result
+}
+
+// nameParts splits a name on non alphanumeric characters and camel case boundaries.
+func nameParts(name string) (parts []string) {
+	var current []rune
+	flush := func() {
+		if len(current) > 0 {
+			parts = append(parts, string(current))
+			current = nil
+		}
+	}
+	runes := []rune(name)
+	for index, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			flush()
+			continue
+		}
+		if unicode.IsUpper(r) && len(current) > 0 {
+			previous := current[len(current)-1]
+			nextIsLower := index+1 < len(runes) && unicode.IsLower(runes[index+1])
+			if unicode.IsLower(previous) || unicode.IsDigit(previous) || (unicode.IsUpper(previous) && nextIsLower) {
+				flush()
+			}
+		}
+		current = append(current, r)
+	}
+	flush()
+	return
+}
@@ -0,0 +1,10 @@
+/*
+Package openapi generates typed `r2` api clients from OpenAPI 3 specs.
+
+The generated client has a method per operation that builds an `r2.Request` with `OptEscapedPath`,
+`OptQuery` and `OptJSONBody`, decodes the response with `JSONWithResponse`, and returns
+non-2xx responses as an `*APIError` holding the typed error response declared in the spec.
+
+Only the json subset of OpenAPI 3 is supported; `$ref`s must point to `#/components`.
+*/
+package openapi
@@ -0,0 +1,201 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/yaml"
+)
+
+// Spec is the subset of an OpenAPI 3 document the generator understands.
+type Spec struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+}
+
+// Info is the spec info block.
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Version     string `json:"version"`
+}
+
+// Components are the reusable spec components.
+type Components struct {
+	Schemas       map[string]*Schema     `json:"schemas"`
+	Parameters    map[string]Parameter   `json:"parameters"`
+	RequestBodies map[string]RequestBody `json:"requestBodies"`
+	Responses     map[string]Response    `json:"responses"`
+}
+
+// PathItem is the set of operations for a path.
+type PathItem struct {
+	Parameters []Parameter `json:"parameters"`
+	Get        *Operation  `json:"get"`
+	Put        *Operation  `json:"put"`
+	Post       *Operation  `json:"post"`
+	Delete     *Operation  `json:"delete"`
+	Patch      *Operation  `json:"patch"`
+	Head       *Operation  `json:"head"`
+	Options    *Operation  `json:"options"`
+}
+
+// Operations returns the operations for the path keyed by method.
+func (pi PathItem) Operations() map[string]*Operation {
+	output := make(map[string]*Operation)
+	for method, operation := range map[string]*Operation{
+		"GET":     pi.Get,
+		"PUT":     pi.Put,
+		"POST":    pi.Post,
+		"DELETE":  pi.Delete,
+		"PATCH":   pi.Patch,
+		"HEAD":    pi.Head,
+		"OPTIONS": pi.Options,
+	} {
+		if operation != nil {
+			output[method] = operation
+		}
+	}
+	return output
+}
+
+// Operation is an api operation.
+type Operation struct {
+	OperationID string              `json:"operationId"`
+	Summary     string              `json:"summary"`
+	Description string              `json:"description"`
+	Parameters  []Parameter         `json:"parameters"`
+	RequestBody *RequestBody        `json:"requestBody"`
+	Responses   map[string]Response `json:"responses"`
+	Deprecated  bool                `json:"deprecated"`
+}
+
+// Parameter is an operation parameter.
+type Parameter struct {
+	Ref         string  `json:"$ref"`
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description"`
+	Required    bool    `json:"required"`
+	Schema      *Schema `json:"schema"`
+}
+
+// RequestBody is an operation request body.
+type RequestBody struct {
+	Ref         string               `json:"$ref"`
+	Description string               `json:"description"`
+	Required    bool                 `json:"required"`
+	Content     map[string]MediaType `json:"content"`
+}
+
+// Response is an operation response.
+type Response struct {
+	Ref         string               `json:"$ref"`
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content"`
+}
+
+// MediaType is the schema for a given content type.
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Schema is a json schema.
+type Schema struct {
+	Ref                  string             `json:"$ref"`
+	Type                 string             `json:"type"`
+	Format               string             `json:"format"`
+	Description          string             `json:"description"`
+	Properties           map[string]*Schema `json:"properties"`
+	Required             []string           `json:"required"`
+	Items                *Schema            `json:"items"`
+	AdditionalProperties json.RawMessage    `json:"additionalProperties"`
+	Enum                 []interface{}      `json:"enum"`
+	Nullable             bool               `json:"nullable"`
+}
+
+// AdditionalPropertiesSchema returns the schema for additional properties if one is set.
+func (s Schema) AdditionalPropertiesSchema() (*Schema, bool) {
+	if len(s.AdditionalProperties) == 0 || string(s.AdditionalProperties) == "false" {
+		return nil, false
+	}
+	if string(s.AdditionalProperties) == "true" {
+		return &Schema{}, true
+	}
+	var schema Schema
+	if err := json.Unmarshal(s.AdditionalProperties, &schema); err != nil {
+		return nil, false
+	}
+	return &schema, true
+}
+
+// IsRequired returns if a property is required.
+func (s Schema) IsRequired(property string) bool {
+	for _, required := range s.Required {
+		if required == property {
+			return true
+		}
+	}
+	return false
+}
+
+// ReadSpec reads a spec from a json or yaml file.
+func ReadSpec(path string) (*Spec, error) {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSpec(contents)
+}
+
+// ParseSpec parses a spec from json or yaml.
+func ParseSpec(contents []byte) (*Spec, error) {
+	var raw interface{}
+	if err := yaml.Unmarshal(contents, &raw); err != nil {
+		return nil, err
+	}
+	normalized, err := json.Marshal(normalizeYAML(raw))
+	if err != nil {
+		return nil, err
+	}
+	var spec Spec
+	if err := json.Unmarshal(normalized, &spec); err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(spec.OpenAPI, "3.") {
+		return nil, ex.New(ErrUnsupportedVersion, ex.OptMessagef("version: %q", spec.OpenAPI))
+	}
+	return &spec, nil
+}
+
+// normalizeYAML converts the `map[interface{}]interface{}` values yaml produces
+// into `map[string]interface{}` so they can be marshalled as json.
+func normalizeYAML(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case map[interface{}]interface{}:
+		output := make(map[string]interface{}, len(typed))
+		for key, value := range typed {
+			output[fmt.Sprint(key)] = normalizeYAML(value)
+		}
+		return output
+	case map[string]interface{}:
+		output := make(map[string]interface{}, len(typed))
+		for key, value := range typed {
+			output[key] = normalizeYAML(value)
+		}
+		return output
+	case []interface{}:
+		output := make([]interface{}, len(typed))
+		for index, value := range typed {
+			output[index] = normalizeYAML(value)
+		}
+		return output
+	default:
+		return value
+	}
+}
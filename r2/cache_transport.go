@@ -0,0 +1,452 @@
+package r2
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/blend/go-sdk/cache"
+	"github.com/blend/go-sdk/ex"
+)
+
+var (
+	_ http.RoundTripper = (*CacheTransport)(nil)
+)
+
+// Cache statuses set in the `X-Cache` header of responses served by a cache transport.
+const (
+	// CacheStatusHit is set if a response was served from the cache without contacting the server.
+	CacheStatusHit = "hit"
+	// CacheStatusRevalidated is set if a cached response was revalidated with the server
+	// with a conditional request, i.e. the server responded with a 304.
+	CacheStatusRevalidated = "revalidated"
+)
+
+// DefaultCacheRevalidationWindow is the default time entries that can be revalidated are kept after they go stale.
+const DefaultCacheRevalidationWindow = time.Hour
+
+// NewCacheTransport returns a new http response caching transport.
+/*
+The cache transport should be shared between requests:
+
+	transport := r2.NewCacheTransport()
+	...
+	err := r2.New(configURL, r2.OptTransport(transport)).JSON(&config)
+
+The default store is a started `cache.LocalCache`, which sweeps entries once they expire. Entries are
+set with a ttl of their freshness lifetime, plus the revalidation window for entries that can be revalidated.
+Stores passed with `OptCacheStore` should likewise evict expired entries, e.g. a started local cache.
+*/
+func NewCacheTransport(options ...CacheTransportOption) *CacheTransport {
+	ct := &CacheTransport{
+		now: func() time.Time { return time.Now().UTC() },
+	}
+	for _, option := range options {
+		option(ct)
+	}
+	if ct.Store == nil {
+		store := cache.NewLocalCache()
+		go store.Start()
+		ct.Store = store
+	}
+	return ct
+}
+
+// CacheTransportOption is an option for cache transports.
+type CacheTransportOption func(*CacheTransport)
+
+// OptCacheStore sets the store cache entries are kept in.
+func OptCacheStore(store cache.Cache) CacheTransportOption {
+	return func(ct *CacheTransport) { ct.Store = store }
+}
+
+// OptCacheRevalidationWindow sets the time entries that can be revalidated are kept after they go stale.
+func OptCacheRevalidationWindow(window time.Duration) CacheTransportOption {
+	return func(ct *CacheTransport) { ct.RevalidationWindow = window }
+}
+
+// OptCacheTransport sets the inner transport requests are sent with.
+func OptCacheTransport(transport http.RoundTripper) CacheTransportOption {
+	return func(ct *CacheTransport) { ct.Transport = transport }
+}
+
+// CacheTransport is a round tripper that caches responses as a private cache, following RFC 7234.
+//
+// Responses to `GET` and `HEAD` requests are stored if they have explicit freshness information
+// (`Cache-Control: max-age` or `Expires`) or a validator (`ETag` or `Last-Modified`), and are not
+// marked `no-store`. Fresh responses are served from the cache; stale responses are revalidated
+// with `If-None-Match` or `If-Modified-Since`, and reused if the server responds with a 304.
+// Responses are only reused for requests that match the values of the headers named by `Vary`.
+// Responses to requests with an `Authorization` header are only stored if they are marked `public`,
+// `s-maxage` or `must-revalidate`, as the transport may be shared between credentials (RFC 7234 §3.2).
+//
+// Requests with `Cache-Control: no-store` bypass the cache, and requests with
+// `Cache-Control: no-cache` or `max-age=0` are always revalidated. Successful unsafe requests,
+// e.g. a `POST`, invalidate the entries for their url.
+type CacheTransport struct {
+	Store     cache.Cache
+	Transport http.RoundTripper
+	// RevalidationWindow is the time entries that can be revalidated are kept after they go stale.
+	RevalidationWindow time.Duration
+
+	now func() time.Time
+}
+
+// RevalidationWindowOrDefault returns the revalidation window or a default.
+func (ct *CacheTransport) RevalidationWindowOrDefault() time.Duration {
+	if ct.RevalidationWindow > 0 {
+		return ct.RevalidationWindow
+	}
+	return DefaultCacheRevalidationWindow
+}
+
+// CacheEntry is a stored response.
+type CacheEntry struct {
+	StatusCode   int
+	Header       http.Header
+	Body         []byte
+	Vary         map[string]string
+	RequestTime  time.Time
+	ResponseTime time.Time
+}
+
+// RoundTrip implements http.RoundTripper.
+func (ct *CacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	method := methodOrDefault(req.Method)
+	if method != MethodGet && method != MethodHead {
+		res, err := ct.transport().RoundTrip(req)
+		if err == nil && !isSafeMethod(method) && res.StatusCode < http.StatusBadRequest {
+			ct.invalidate(req)
+		}
+		return res, err
+	}
+
+	reqCacheControl := parseCacheControl(req.Header)
+	if _, ok := reqCacheControl["no-store"]; ok || isConditionalRequest(req) {
+		return ct.transport().RoundTrip(req)
+	}
+
+	key := cacheKey(method, req)
+	entry, ok := ct.get(key, req)
+	if !ok {
+		return ct.fetch(key, req)
+	}
+
+	now := ct.now()
+	if entry.isFresh(now, reqCacheControl) {
+		return entry.response(req, now, CacheStatusHit), nil
+	}
+	if !entry.canRevalidate() {
+		return ct.fetch(key, req)
+	}
+	return ct.revalidate(key, req, entry)
+}
+
+func (ct *CacheTransport) get(key string, req *http.Request) (*CacheEntry, bool) {
+	value, ok := ct.Store.Get(key)
+	if !ok {
+		return nil, false
+	}
+	entry, ok := value.(*CacheEntry)
+	if !ok || !entry.matchesVary(req) {
+		return nil, false
+	}
+	return entry, true
+}
+
+// fetch sends a request and stores the response if it is cacheable.
+func (ct *CacheTransport) fetch(key string, req *http.Request) (*http.Response, error) {
+	requestTime := ct.now()
+	res, err := ct.transport().RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	return ct.store(key, req, res, requestTime)
+}
+
+// revalidate sends a conditional request for a stale entry.
+func (ct *CacheTransport) revalidate(key string, req *http.Request, entry *CacheEntry) (*http.Response, error) {
+	conditional := req.WithContext(req.Context())
+	conditional.Header = cloneHeader(req.Header)
+	if etag := entry.Header.Get(HeaderETag); etag != "" {
+		conditional.Header.Set(HeaderIfNoneMatch, etag)
+	}
+	if lastModified := entry.Header.Get(HeaderLastModified); lastModified != "" {
+		conditional.Header.Set(HeaderIfModifiedSince, lastModified)
+	}
+
+	requestTime := ct.now()
+	res, err := ct.transport().RoundTrip(conditional)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusNotModified {
+		return ct.store(key, req, res, requestTime)
+	}
+	res.Body.Close()
+
+	updated := *entry
+	updated.Header = cloneHeader(entry.Header)
+	for header, values := range res.Header {
+		updated.Header[header] = append([]string(nil), values...)
+	}
+	updated.RequestTime = requestTime
+	updated.ResponseTime = ct.now()
+	ct.set(key, &updated)
+	return updated.response(req, updated.ResponseTime, CacheStatusRevalidated), nil
+}
+
+// store reads and stores a cacheable response, returning a response with a replayable body.
+func (ct *CacheTransport) store(key string, req *http.Request, res *http.Response, requestTime time.Time) (*http.Response, error) {
+	if !isCacheableResponse(req, res) {
+		ct.Store.Remove(key)
+		return res, nil
+	}
+	defer res.Body.Close()
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, ex.New(err)
+	}
+	res.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	entry := &CacheEntry{
+		StatusCode:   res.StatusCode,
+		Header:       cloneHeader(res.Header),
+		Body:         body,
+		Vary:         make(map[string]string),
+		RequestTime:  requestTime,
+		ResponseTime: ct.now(),
+	}
+	for _, header := range varyHeaders(res.Header) {
+		entry.Vary[header] = req.Header.Get(header)
+	}
+	ct.set(key, entry)
+	return res, nil
+}
+
+func (ct *CacheTransport) set(key string, entry *CacheEntry) {
+	ttl := entry.freshnessLifetime() - entry.currentAge(entry.ResponseTime)
+	if ttl < 0 {
+		ttl = 0
+	}
+	// entries that cannot be revalidated are only useful while they are fresh.
+	if entry.canRevalidate() {
+		ttl += ct.RevalidationWindowOrDefault()
+	}
+	if ttl > 0 {
+		ct.Store.Set(key, entry, cache.OptValueTTL(ttl))
+		return
+	}
+	ct.Store.Remove(key)
+}
+
+func (ct *CacheTransport) invalidate(req *http.Request) {
+	ct.Store.Remove(cacheKey(MethodGet, req))
+	ct.Store.Remove(cacheKey(MethodHead, req))
+}
+
+func (ct *CacheTransport) transport() http.RoundTripper {
+	if ct.Transport != nil {
+		return ct.Transport
+	}
+	return http.DefaultTransport
+}
+
+// isFresh returns if the entry can be served without revalidation.
+func (ce *CacheEntry) isFresh(now time.Time, reqCacheControl map[string]string) bool {
+	if _, ok := reqCacheControl["no-cache"]; ok {
+		return false
+	}
+	if _, ok := parseCacheControl(ce.Header)["no-cache"]; ok {
+		return false
+	}
+	age := ce.currentAge(now)
+	if maxAge, ok := cacheControlSeconds(reqCacheControl, "max-age"); ok && age > maxAge {
+		return false
+	}
+	return age < ce.freshnessLifetime()
+}
+
+// freshnessLifetime returns the freshness lifetime of the entry from `max-age` or `Expires`.
+func (ce *CacheEntry) freshnessLifetime() time.Duration {
+	if maxAge, ok := cacheControlSeconds(parseCacheControl(ce.Header), "max-age"); ok {
+		return maxAge
+	}
+	expiresHeader := ce.Header.Get(HeaderExpires)
+	if expiresHeader == "" {
+		return 0
+	}
+	expires, err := http.ParseTime(expiresHeader)
+	if err != nil {
+		return 0
+	}
+	return expires.Sub(ce.date())
+}
+
+// currentAge returns the age of the entry as of a given time.
+func (ce *CacheEntry) currentAge(now time.Time) time.Duration {
+	age := ce.ResponseTime.Sub(ce.date())
+	if age < 0 {
+		age = 0
+	}
+	if ageSeconds, err := strconv.ParseInt(ce.Header.Get(HeaderAge), 10, 64); err == nil {
+		if ageValue := time.Duration(ageSeconds)*time.Second + ce.ResponseTime.Sub(ce.RequestTime); ageValue > age {
+			age = ageValue
+		}
+	}
+	return age + now.Sub(ce.ResponseTime)
+}
+
+// date returns the `Date` header of the entry, or the response time if it is unset.
+func (ce *CacheEntry) date() time.Time {
+	if date, err := http.ParseTime(ce.Header.Get(HeaderDate)); err == nil {
+		return date
+	}
+	return ce.ResponseTime
+}
+
+func (ce *CacheEntry) canRevalidate() bool {
+	return ce.Header.Get(HeaderETag) != "" || ce.Header.Get(HeaderLastModified) != ""
+}
+
+func (ce *CacheEntry) matchesVary(req *http.Request) bool {
+	for header, value := range ce.Vary {
+		if req.Header.Get(header) != value {
+			return false
+		}
+	}
+	return true
+}
+
+func (ce *CacheEntry) response(req *http.Request, now time.Time, status string) *http.Response {
+	header := cloneHeader(ce.Header)
+	header.Set(HeaderAge, strconv.FormatInt(int64(ce.currentAge(now)/time.Second), 10))
+	header.Set(HeaderXCache, status)
+	return &http.Response{
+		Status:        strconv.Itoa(ce.StatusCode) + " " + http.StatusText(ce.StatusCode),
+		StatusCode:    ce.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(bytes.NewReader(ce.Body)),
+		ContentLength: int64(len(ce.Body)),
+		Request:       req,
+	}
+}
+
+//
+// internal helpers
+//
+
+// isCacheableResponse returns if a response to a request can be stored.
+func isCacheableResponse(req *http.Request, res *http.Response) bool {
+	switch res.StatusCode {
+	case http.StatusOK, http.StatusNonAuthoritativeInfo, http.StatusNoContent,
+		http.StatusMultipleChoices, http.StatusMovedPermanently,
+		http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusGone,
+		http.StatusRequestURITooLong, http.StatusNotImplemented:
+	default:
+		return false
+	}
+	cacheControl := parseCacheControl(res.Header)
+	if _, ok := cacheControl["no-store"]; ok {
+		return false
+	}
+	if req.Header.Get(HeaderAuthorization) != "" && !isSharedCacheable(cacheControl) {
+		return false
+	}
+	for _, header := range varyHeaders(res.Header) {
+		if header == "*" {
+			return false
+		}
+	}
+	if res.Header.Get(HeaderETag) != "" || res.Header.Get(HeaderLastModified) != "" {
+		return true
+	}
+	if _, ok := cacheControl["max-age"]; ok {
+		return true
+	}
+	return res.Header.Get(HeaderExpires) != ""
+}
+
+// isSharedCacheable returns if a response to an authorized request can be stored.
+func isSharedCacheable(cacheControl map[string]string) bool {
+	for _, directive := range []string{"public", "s-maxage", "must-revalidate"} {
+		if _, ok := cacheControl[directive]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func isSafeMethod(method string) bool {
+	switch method {
+	case MethodGet, MethodHead, MethodOptions, MethodTrace:
+		return true
+	default:
+		return false
+	}
+}
+
+// isConditionalRequest returns if the caller has set their own validators.
+func isConditionalRequest(req *http.Request) bool {
+	return req.Header.Get(HeaderIfNoneMatch) != "" || req.Header.Get(HeaderIfModifiedSince) != ""
+}
+
+func cacheKey(method string, req *http.Request) string {
+	return method + " " + req.URL.String()
+}
+
+// parseCacheControl parses the `Cache-Control` directives of a header, with lowercased names.
+func parseCacheControl(header http.Header) map[string]string {
+	directives := make(map[string]string)
+	for _, value := range header[HeaderCacheControl] {
+		for _, directive := range strings.Split(value, ",") {
+			directive = strings.TrimSpace(directive)
+			if directive == "" {
+				continue
+			}
+			name, arg := directive, ""
+			if index := strings.Index(directive, "="); index >= 0 {
+				name, arg = directive[:index], strings.Trim(directive[index+1:], `"`)
+			}
+			directives[strings.ToLower(strings.TrimSpace(name))] = arg
+		}
+	}
+	return directives
+}
+
+func cacheControlSeconds(directives map[string]string, name string) (time.Duration, bool) {
+	value, ok := directives[name]
+	if !ok {
+		return 0, false
+	}
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || seconds < 0 {
+		return 0, true
+	}
+	return time.Duration(seconds) * time.Second, true
+}
+
+func varyHeaders(header http.Header) (output []string) {
+	for _, value := range header[HeaderVary] {
+		for _, name := range strings.Split(value, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				output = append(output, http.CanonicalHeaderKey(name))
+			}
+		}
+	}
+	return
+}
+
+func cloneHeader(header http.Header) http.Header {
+	output := make(http.Header, len(header))
+	for key, values := range header {
+		output[key] = append([]string(nil), values...)
+	}
+	return output
+}
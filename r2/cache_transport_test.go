@@ -0,0 +1,286 @@
+package r2
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/cache"
+)
+
+func TestCacheTransportMaxAge(t *testing.T) {
+	assert := assert.New(t)
+
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		count := atomic.AddInt32(&hits, 1)
+		rw.Header().Set(HeaderCacheControl, "max-age=60")
+		fmt.Fprintf(rw, "response %d", count)
+	}))
+	defer server.Close()
+
+	now := time.Date(2020, 01, 02, 03, 04, 05, 0, time.UTC)
+	transport := NewCacheTransport()
+	transport.now = func() time.Time { return now }
+
+	contents, res, err := New(server.URL, OptTransport(transport)).BytesWithResponse()
+	assert.Nil(err)
+	assert.Equal("response 1", string(contents))
+	assert.Empty(res.Header.Get(HeaderXCache))
+
+	now = now.Add(30 * time.Second)
+	contents, res, err = New(server.URL, OptTransport(transport)).BytesWithResponse()
+	assert.Nil(err)
+	assert.Equal("response 1", string(contents))
+	assert.Equal(CacheStatusHit, res.Header.Get(HeaderXCache))
+	assert.Equal(1, atomic.LoadInt32(&hits))
+
+	// the request asks for a response no older than 10 seconds.
+	contents, err = New(server.URL, OptTransport(transport), OptHeaderValue(HeaderCacheControl, "max-age=10")).Bytes()
+	assert.Nil(err)
+	assert.Equal("response 2", string(contents))
+
+	now = now.Add(61 * time.Second)
+	contents, err = New(server.URL, OptTransport(transport)).Bytes()
+	assert.Nil(err)
+	assert.Equal("response 3", string(contents))
+	assert.Equal(3, atomic.LoadInt32(&hits))
+}
+
+func TestCacheTransportETag(t *testing.T) {
+	assert := assert.New(t)
+
+	var hits, notModified int32
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		rw.Header().Set(HeaderCacheControl, "no-cache")
+		rw.Header().Set(HeaderETag, `"v1"`)
+		if r.Header.Get(HeaderIfNoneMatch) == `"v1"` {
+			atomic.AddInt32(&notModified, 1)
+			rw.WriteHeader(http.StatusNotModified)
+			return
+		}
+		fmt.Fprint(rw, "config")
+	}))
+	defer server.Close()
+
+	transport := NewCacheTransport()
+	for x := 0; x < 3; x++ {
+		contents, res, err := New(server.URL, OptTransport(transport)).BytesWithResponse()
+		assert.Nil(err)
+		assert.Equal(http.StatusOK, res.StatusCode)
+		assert.Equal("config", string(contents))
+		if x > 0 {
+			assert.Equal(CacheStatusRevalidated, res.Header.Get(HeaderXCache))
+		}
+	}
+	assert.Equal(3, atomic.LoadInt32(&hits))
+	assert.Equal(2, atomic.LoadInt32(&notModified))
+}
+
+func TestCacheTransportRevalidationWindow(t *testing.T) {
+	assert := assert.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set(HeaderCacheControl, "max-age=60")
+		rw.Header().Set(HeaderETag, `"v1"`)
+		fmt.Fprint(rw, "config")
+	}))
+	defer server.Close()
+
+	store := cache.NewLocalCache()
+	transport := NewCacheTransport(OptCacheStore(store), OptCacheRevalidationWindow(time.Hour))
+	assert.Nil(New(server.URL, OptTransport(transport)).Discard())
+
+	value, ok := store.Data[MethodGet+" "+server.URL]
+	assert.True(ok)
+	// the entry expires once it has been stale for the revalidation window.
+	expires := time.Now().UTC().Add(time.Hour + time.Minute)
+	assert.InTimeDelta(expires, value.Expires, 5*time.Second)
+}
+
+func TestCacheTransportLastModified(t *testing.T) {
+	assert := assert.New(t)
+
+	var hits int32
+	now := time.Date(2020, 01, 02, 03, 04, 05, 0, time.UTC)
+	modified := now.Add(-time.Hour)
+	version := "v1"
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		rw.Header().Set(HeaderDate, now.Format(http.TimeFormat))
+		rw.Header().Set(HeaderCacheControl, "max-age=60")
+		rw.Header().Set(HeaderLastModified, modified.Format(http.TimeFormat))
+		if since, err := http.ParseTime(r.Header.Get(HeaderIfModifiedSince)); err == nil && !modified.After(since) {
+			rw.WriteHeader(http.StatusNotModified)
+			return
+		}
+		fmt.Fprint(rw, version)
+	}))
+	defer server.Close()
+
+	transport := NewCacheTransport()
+	transport.now = func() time.Time { return now }
+
+	contents, err := New(server.URL, OptTransport(transport)).Bytes()
+	assert.Nil(err)
+	assert.Equal("v1", string(contents))
+
+	now = now.Add(time.Minute)
+	contents, res, err := New(server.URL, OptTransport(transport)).BytesWithResponse()
+	assert.Nil(err)
+	assert.Equal("v1", string(contents))
+	assert.Equal(CacheStatusRevalidated, res.Header.Get(HeaderXCache))
+
+	// the revalidated entry is fresh again.
+	contents, res, err = New(server.URL, OptTransport(transport)).BytesWithResponse()
+	assert.Nil(err)
+	assert.Equal("v1", string(contents))
+	assert.Equal(CacheStatusHit, res.Header.Get(HeaderXCache))
+	assert.Equal(2, atomic.LoadInt32(&hits))
+
+	now = now.Add(time.Minute)
+	modified = modified.Add(time.Hour)
+	version = "v2"
+	contents, res, err = New(server.URL, OptTransport(transport)).BytesWithResponse()
+	assert.Nil(err)
+	assert.Equal("v2", string(contents))
+	assert.Empty(res.Header.Get(HeaderXCache))
+}
+
+func TestCacheTransportVary(t *testing.T) {
+	assert := assert.New(t)
+
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		rw.Header().Set(HeaderCacheControl, "max-age=60")
+		rw.Header().Set(HeaderVary, "Accept-Language")
+		fmt.Fprint(rw, r.Header.Get("Accept-Language"))
+	}))
+	defer server.Close()
+
+	transport := NewCacheTransport()
+	contents, err := New(server.URL, OptTransport(transport), OptHeaderValue("Accept-Language", "en")).Bytes()
+	assert.Nil(err)
+	assert.Equal("en", string(contents))
+
+	contents, err = New(server.URL, OptTransport(transport), OptHeaderValue("Accept-Language", "en")).Bytes()
+	assert.Nil(err)
+	assert.Equal("en", string(contents))
+	assert.Equal(1, atomic.LoadInt32(&hits))
+
+	contents, err = New(server.URL, OptTransport(transport), OptHeaderValue("Accept-Language", "fr")).Bytes()
+	assert.Nil(err)
+	assert.Equal("fr", string(contents))
+	assert.Equal(2, atomic.LoadInt32(&hits))
+}
+
+func TestCacheTransportNoStore(t *testing.T) {
+	assert := assert.New(t)
+
+	var hits int32
+	cacheControl := "no-store"
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		rw.Header().Set(HeaderCacheControl, cacheControl)
+		fmt.Fprint(rw, "OK!")
+	}))
+	defer server.Close()
+
+	store := cache.NewLocalCache()
+	transport := NewCacheTransport(OptCacheStore(store))
+	assert.Nil(New(server.URL, OptTransport(transport)).Discard())
+	assert.False(store.Has(MethodGet + " " + server.URL))
+
+	cacheControl = "max-age=60"
+	assert.Nil(New(server.URL, OptTransport(transport), OptHeaderValue(HeaderCacheControl, "no-store")).Discard())
+	assert.False(store.Has(MethodGet + " " + server.URL))
+	assert.Equal(2, atomic.LoadInt32(&hits))
+}
+
+func TestCacheTransportInvalidate(t *testing.T) {
+	assert := assert.New(t)
+
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		rw.Header().Set(HeaderCacheControl, "max-age=60")
+		fmt.Fprint(rw, "OK!")
+	}))
+	defer server.Close()
+
+	transport := NewCacheTransport()
+	assert.Nil(New(server.URL, OptTransport(transport)).Discard())
+	assert.Nil(New(server.URL, OptTransport(transport)).Discard())
+	assert.Equal(1, atomic.LoadInt32(&hits))
+
+	assert.Nil(New(server.URL, OptTransport(transport), OptPost()).Discard())
+	assert.Equal(2, atomic.LoadInt32(&hits))
+
+	assert.Nil(New(server.URL, OptTransport(transport)).Discard())
+	assert.Equal(3, atomic.LoadInt32(&hits))
+}
+
+func TestParseCacheControl(t *testing.T) {
+	assert := assert.New(t)
+
+	header := http.Header{}
+	header.Add(HeaderCacheControl, `Max-Age=60, no-cache`)
+	header.Add(HeaderCacheControl, `private="Set-Cookie"`)
+	directives := parseCacheControl(header)
+	assert.Equal("60", directives["max-age"])
+	assert.Equal("Set-Cookie", directives["private"])
+	_, ok := directives["no-cache"]
+	assert.True(ok)
+
+	maxAge, ok := cacheControlSeconds(directives, "max-age")
+	assert.True(ok)
+	assert.Equal(time.Minute, maxAge)
+}
+
+func TestCacheTransportAuthorization(t *testing.T) {
+	assert := assert.New(t)
+
+	var hits int32
+	cacheControl := "max-age=60"
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		count := atomic.AddInt32(&hits, 1)
+		rw.Header().Set(HeaderCacheControl, cacheControl)
+		fmt.Fprintf(rw, "%s %d", r.Header.Get(HeaderAuthorization), count)
+	}))
+	defer server.Close()
+
+	store := cache.NewLocalCache()
+	transport := NewCacheTransport(OptCacheStore(store))
+
+	// responses to authorized requests are not reused for other credentials.
+	contents, err := New(server.URL, OptTransport(transport), OptHeaderValue(HeaderAuthorization, "Bearer one")).Bytes()
+	assert.Nil(err)
+	assert.Equal("Bearer one 1", string(contents))
+	assert.False(store.Has(MethodGet + " " + server.URL))
+	contents, err = New(server.URL, OptTransport(transport), OptHeaderValue(HeaderAuthorization, "Bearer two")).Bytes()
+	assert.Nil(err)
+	assert.Equal("Bearer two 2", string(contents))
+
+	// unless they are marked public.
+	cacheControl = "public, max-age=60"
+	assert.Nil(New(server.URL, OptTransport(transport), OptHeaderValue(HeaderAuthorization, "Bearer one")).Discard())
+	assert.True(store.Has(MethodGet + " " + server.URL))
+	assert.Nil(New(server.URL, OptTransport(transport), OptHeaderValue(HeaderAuthorization, "Bearer two")).Discard())
+	assert.Equal(3, atomic.LoadInt32(&hits))
+}
+
+func TestNewCacheTransportStartsStore(t *testing.T) {
+	assert := assert.New(t)
+
+	transport := NewCacheTransport()
+	store, ok := transport.Store.(*cache.LocalCache)
+	assert.True(ok)
+	<-store.NotifyStarted()
+	assert.Nil(store.Stop())
+}
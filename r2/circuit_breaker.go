@@ -0,0 +1,325 @@
+package r2
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/logger"
+	"github.com/blend/go-sdk/stats"
+)
+
+// Circuit breaker defaults.
+const (
+	// DefaultCircuitBreakerConsecutiveFailures is the default number of consecutive failures that opens a circuit.
+	DefaultCircuitBreakerConsecutiveFailures = 5
+	// DefaultCircuitBreakerOpenTimeout is the default time a circuit stays open before allowing trial requests.
+	DefaultCircuitBreakerOpenTimeout = 30 * time.Second
+	// DefaultCircuitBreakerInterval is the default window over which failure ratios are calculated.
+	DefaultCircuitBreakerInterval = time.Minute
+	// DefaultCircuitBreakerHalfOpenRequests is the default number of trial requests allowed while half-open.
+	DefaultCircuitBreakerHalfOpenRequests = 1
+)
+
+// Circuit breaker metric and tag names.
+const (
+	MetricNameCircuitBreakerStateChange = "http.client.circuit_breaker.state_change"
+	MetricNameCircuitBreakerOpen        = "http.client.circuit_breaker.open"
+	MetricNameCircuitBreakerRejected    = "http.client.circuit_breaker.rejected"
+
+	TagHost         = "host"
+	TagCircuitState = "circuit_state"
+)
+
+// CircuitState is the state of a circuit.
+type CircuitState string
+
+// Circuit states.
+const (
+	CircuitStateClosed   CircuitState = "closed"
+	CircuitStateOpen     CircuitState = "open"
+	CircuitStateHalfOpen CircuitState = "half-open"
+)
+
+// NewCircuitBreaker returns a new circuit breaker.
+/*
+A circuit breaker tracks the health of each host it sees separately, and should be shared
+between requests, e.g. with `r2.Defaults`:
+
+	breaker := r2.NewCircuitBreaker(r2.OptCircuitBreakerFailureRatio(0.5, 20))
+	defaults := r2.Defaults{r2.OptCircuitBreaker(breaker)}
+*/
+func NewCircuitBreaker(options ...CircuitBreakerOption) *CircuitBreaker {
+	cb := &CircuitBreaker{
+		ConsecutiveFailures: DefaultCircuitBreakerConsecutiveFailures,
+		OpenTimeout:         DefaultCircuitBreakerOpenTimeout,
+		Interval:            DefaultCircuitBreakerInterval,
+		HalfOpenRequests:    DefaultCircuitBreakerHalfOpenRequests,
+		circuits:            make(map[string]*circuit),
+		now:                 func() time.Time { return time.Now().UTC() },
+	}
+	for _, option := range options {
+		option(cb)
+	}
+	return cb
+}
+
+// CircuitBreakerOption is an option for circuit breakers.
+type CircuitBreakerOption func(*CircuitBreaker)
+
+// OptCircuitBreakerConsecutiveFailures sets the number of consecutive failures that opens a circuit.
+// A value of zero disables the consecutive failure trigger.
+func OptCircuitBreakerConsecutiveFailures(failures int) CircuitBreakerOption {
+	return func(cb *CircuitBreaker) { cb.ConsecutiveFailures = failures }
+}
+
+// OptCircuitBreakerFailureRatio sets the failure ratio that opens a circuit, once at least
+// `minRequests` requests have been made within the interval.
+func OptCircuitBreakerFailureRatio(ratio float64, minRequests int) CircuitBreakerOption {
+	return func(cb *CircuitBreaker) {
+		cb.FailureRatio = ratio
+		cb.MinRequests = minRequests
+	}
+}
+
+// OptCircuitBreakerInterval sets the window over which failure ratios are calculated.
+func OptCircuitBreakerInterval(interval time.Duration) CircuitBreakerOption {
+	return func(cb *CircuitBreaker) { cb.Interval = interval }
+}
+
+// OptCircuitBreakerOpenTimeout sets how long a circuit stays open before allowing trial requests.
+func OptCircuitBreakerOpenTimeout(timeout time.Duration) CircuitBreakerOption {
+	return func(cb *CircuitBreaker) { cb.OpenTimeout = timeout }
+}
+
+// OptCircuitBreakerHalfOpenRequests sets the number of successful trial requests required to close a circuit.
+func OptCircuitBreakerHalfOpenRequests(requests int) CircuitBreakerOption {
+	return func(cb *CircuitBreaker) { cb.HalfOpenRequests = requests }
+}
+
+// OptCircuitBreakerIsFailure sets the function used to classify attempts as failures.
+func OptCircuitBreakerIsFailure(isFailure func(*http.Response, error) bool) CircuitBreakerOption {
+	return func(cb *CircuitBreaker) { cb.IsFailure = isFailure }
+}
+
+// OptCircuitBreakerLog sets the logger circuit state changes are triggered on.
+func OptCircuitBreakerLog(log logger.Triggerable) CircuitBreakerOption {
+	return func(cb *CircuitBreaker) { cb.Log = log }
+}
+
+// OptCircuitBreakerStats sets the stats collector for circuit state changes and rejected requests.
+func OptCircuitBreakerStats(collector stats.Collector) CircuitBreakerOption {
+	return func(cb *CircuitBreaker) { cb.Stats = collector }
+}
+
+// CircuitBreaker fails requests fast when a host is unhealthy.
+//
+// Each host starts closed. A closed circuit opens when either `ConsecutiveFailures` attempts
+// fail in a row, or when `FailureRatio` of the attempts within `Interval` fail (once at least `MinRequests`
+// have been made). An open circuit rejects requests with `ErrCircuitOpen` until `OpenTimeout` elapses,
+// after which it is half-open and lets `HalfOpenRequests` trial requests through. If they all succeed
+// the circuit closes, and if any fail it opens again.
+type CircuitBreaker struct {
+	ConsecutiveFailures int
+	FailureRatio        float64
+	MinRequests         int
+	Interval            time.Duration
+	OpenTimeout         time.Duration
+	HalfOpenRequests    int
+	// IsFailure classifies attempts as failures.
+	// If unset, connection errors and 5xx responses are failures.
+	IsFailure func(*http.Response, error) bool
+
+	Log   logger.Triggerable
+	Stats stats.Collector
+
+	sync.Mutex
+	circuits map[string]*circuit
+	now      func() time.Time
+}
+
+type circuit struct {
+	state               CircuitState
+	generation          uint64
+	openedAt            time.Time
+	windowStart         time.Time
+	requests            int
+	failures            int
+	consecutiveFailures int
+	halfOpenInFlight    int
+	halfOpenSuccesses   int
+}
+
+// State returns the current state of the circuit for a host.
+func (cb *CircuitBreaker) State(host string) CircuitState {
+	cb.Lock()
+	defer cb.Unlock()
+	c, ok := cb.circuits[host]
+	if !ok {
+		return CircuitStateClosed
+	}
+	cb.advance(context.Background(), host, c)
+	return c.state
+}
+
+// Allow returns an error if a request to a given host should be rejected.
+// If it returns nil, the caller must call `Record` with the outcome of the request
+// and the returned generation of the circuit.
+func (cb *CircuitBreaker) Allow(ctx context.Context, host string) (generation uint64, err error) {
+	cb.Lock()
+	defer cb.Unlock()
+
+	c := cb.circuit(host)
+	cb.advance(ctx, host, c)
+	switch c.state {
+	case CircuitStateOpen:
+		cb.rejected(host)
+		return c.generation, ex.New(ErrCircuitOpen, ex.OptMessagef("host: %s", host))
+	case CircuitStateHalfOpen:
+		if c.halfOpenInFlight+c.halfOpenSuccesses >= cb.halfOpenRequests() {
+			cb.rejected(host)
+			return c.generation, ex.New(ErrCircuitOpen, ex.OptMessagef("host: %s; half-open", host))
+		}
+		c.halfOpenInFlight++
+	}
+	return c.generation, nil
+}
+
+// Record records the outcome of a request to a given host, allowed in a given generation of the circuit.
+// Requests that fail because their context was canceled are not counted, nor are requests
+// allowed before the circuit last changed state, e.g. requests that were in flight when it opened
+// are not counted as half-open trials.
+func (cb *CircuitBreaker) Record(ctx context.Context, host string, generation uint64, res *http.Response, err error) {
+	canceled := err != nil && ctx.Err() != nil
+	failed := cb.isFailure(res, err)
+
+	cb.Lock()
+	defer cb.Unlock()
+
+	c := cb.circuit(host)
+	cb.advance(ctx, host, c)
+	if generation != c.generation {
+		return
+	}
+	switch c.state {
+	case CircuitStateHalfOpen:
+		if c.halfOpenInFlight > 0 {
+			c.halfOpenInFlight--
+		}
+		if canceled {
+			return
+		}
+		if failed {
+			cb.transition(ctx, host, c, CircuitStateOpen)
+			return
+		}
+		c.halfOpenSuccesses++
+		if c.halfOpenSuccesses >= cb.halfOpenRequests() {
+			cb.transition(ctx, host, c, CircuitStateClosed)
+		}
+	case CircuitStateClosed:
+		if canceled {
+			return
+		}
+		c.requests++
+		if !failed {
+			c.consecutiveFailures = 0
+			return
+		}
+		c.failures++
+		c.consecutiveFailures++
+		if cb.ConsecutiveFailures > 0 && c.consecutiveFailures >= cb.ConsecutiveFailures {
+			cb.transition(ctx, host, c, CircuitStateOpen)
+			return
+		}
+		if cb.FailureRatio > 0 && c.requests >= cb.MinRequests && float64(c.failures)/float64(c.requests) >= cb.FailureRatio {
+			cb.transition(ctx, host, c, CircuitStateOpen)
+		}
+	}
+}
+
+// circuit returns the circuit for a host, creating it if it doesn't exist.
+// It and the other helpers below assume the lock is held.
+func (cb *CircuitBreaker) circuit(host string) *circuit {
+	c, ok := cb.circuits[host]
+	if !ok {
+		c = &circuit{state: CircuitStateClosed, windowStart: cb.now()}
+		cb.circuits[host] = c
+	}
+	return c
+}
+
+// advance moves open circuits to half-open after the open timeout,
+// and resets the counts of closed circuits after each interval.
+func (cb *CircuitBreaker) advance(ctx context.Context, host string, c *circuit) {
+	now := cb.now()
+	switch c.state {
+	case CircuitStateOpen:
+		if now.Sub(c.openedAt) >= cb.OpenTimeout {
+			cb.transition(ctx, host, c, CircuitStateHalfOpen)
+		}
+	case CircuitStateClosed:
+		if cb.Interval > 0 && now.Sub(c.windowStart) >= cb.Interval {
+			c.windowStart = now
+			c.requests = 0
+			c.failures = 0
+		}
+	}
+}
+
+func (cb *CircuitBreaker) transition(ctx context.Context, host string, c *circuit, to CircuitState) {
+	from := c.state
+	now := cb.now()
+
+	c.state = to
+	c.generation++
+	c.windowStart = now
+	c.requests = 0
+	c.failures = 0
+	c.consecutiveFailures = 0
+	c.halfOpenInFlight = 0
+	c.halfOpenSuccesses = 0
+	if to == CircuitStateOpen {
+		c.openedAt = now
+	}
+
+	if cb.Log != nil {
+		cb.Log.Trigger(ctx, NewCircuitBreakerEvent(host, from, to))
+	}
+	if cb.Stats != nil {
+		tags := []string{
+			stats.Tag(TagHost, host),
+		}
+		cb.Stats.Increment(MetricNameCircuitBreakerStateChange, append(tags, stats.Tag(TagCircuitState, string(to)))...)
+		if to == CircuitStateClosed {
+			cb.Stats.Gauge(MetricNameCircuitBreakerOpen, 0, tags...)
+		} else {
+			cb.Stats.Gauge(MetricNameCircuitBreakerOpen, 1, tags...)
+		}
+	}
+}
+
+func (cb *CircuitBreaker) rejected(host string) {
+	if cb.Stats != nil {
+		cb.Stats.Increment(MetricNameCircuitBreakerRejected, stats.Tag(TagHost, host))
+	}
+}
+
+func (cb *CircuitBreaker) halfOpenRequests() int {
+	if cb.HalfOpenRequests > 0 {
+		return cb.HalfOpenRequests
+	}
+	return DefaultCircuitBreakerHalfOpenRequests
+}
+
+func (cb *CircuitBreaker) isFailure(res *http.Response, err error) bool {
+	if cb.IsFailure != nil {
+		return cb.IsFailure(res, err)
+	}
+	if err != nil {
+		return true
+	}
+	return res != nil && res.StatusCode >= http.StatusInternalServerError
+}
@@ -0,0 +1,47 @@
+package r2
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/blend/go-sdk/logger"
+)
+
+const (
+	// FlagCircuitBreaker is a logger event flag for circuit state changes.
+	FlagCircuitBreaker = "http.client.circuit_breaker"
+)
+
+// NewCircuitBreakerEvent returns a new circuit breaker event.
+func NewCircuitBreakerEvent(host string, from, to CircuitState) *CircuitBreakerEvent {
+	return &CircuitBreakerEvent{
+		EventMeta: logger.NewEventMeta(FlagCircuitBreaker),
+		Host:      host,
+		From:      from,
+		To:        to,
+	}
+}
+
+// CircuitBreakerEvent is an event triggered when the circuit for a host changes state.
+type CircuitBreakerEvent struct {
+	*logger.EventMeta
+
+	Host string
+	From CircuitState
+	To   CircuitState
+}
+
+// WriteText writes the event to a text writer.
+func (e *CircuitBreakerEvent) WriteText(tf logger.TextFormatter, wr io.Writer) {
+	io.WriteString(wr, fmt.Sprintf("%s circuit %s => %s", e.Host, e.From, e.To))
+}
+
+// MarshalJSON implements json.Marshaler.
+func (e *CircuitBreakerEvent) MarshalJSON() ([]byte, error) {
+	return json.Marshal(logger.MergeDecomposed(e.EventMeta.Decompose(), map[string]interface{}{
+		"host": e.Host,
+		"from": e.From,
+		"to":   e.To,
+	}))
+}
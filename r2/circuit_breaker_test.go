@@ -0,0 +1,229 @@
+package r2
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/logger"
+	"github.com/blend/go-sdk/stats"
+)
+
+type mockClock struct {
+	now time.Time
+}
+
+func (mc *mockClock) Now() time.Time { return mc.now }
+
+func newTestCircuitBreaker(options ...CircuitBreakerOption) (*CircuitBreaker, *mockClock) {
+	clock := &mockClock{now: time.Date(2019, 10, 1, 12, 0, 0, 0, time.UTC)}
+	cb := NewCircuitBreaker(options...)
+	cb.now = clock.Now
+	return cb, clock
+}
+
+var (
+	responseOK    = &http.Response{StatusCode: http.StatusOK}
+	responseError = &http.Response{StatusCode: http.StatusInternalServerError}
+)
+
+func TestCircuitBreakerConsecutiveFailures(t *testing.T) {
+	assert := assert.New(t)
+
+	cb, clock := newTestCircuitBreaker(
+		OptCircuitBreakerConsecutiveFailures(3),
+		OptCircuitBreakerOpenTimeout(time.Minute),
+	)
+	ctx := context.Background()
+
+	for x := 0; x < 2; x++ {
+		generation, err := cb.Allow(ctx, "foo.com")
+		assert.Nil(err)
+		cb.Record(ctx, "foo.com", generation, responseError, nil)
+	}
+	generation, err := cb.Allow(ctx, "foo.com")
+	assert.Nil(err)
+	cb.Record(ctx, "foo.com", generation, responseOK, nil)
+	assert.Equal(CircuitStateClosed, cb.State("foo.com"))
+
+	for x := 0; x < 3; x++ {
+		generation, err = cb.Allow(ctx, "foo.com")
+		assert.Nil(err)
+		cb.Record(ctx, "foo.com", generation, nil, fmt.Errorf("connection refused"))
+	}
+	assert.Equal(CircuitStateOpen, cb.State("foo.com"))
+	_, err = cb.Allow(ctx, "foo.com")
+	assert.True(IsErrCircuitOpen(err))
+
+	// other hosts are unaffected
+	_, err = cb.Allow(ctx, "bar.com")
+	assert.Nil(err)
+	assert.Equal(CircuitStateClosed, cb.State("bar.com"))
+
+	clock.now = clock.now.Add(time.Minute)
+	assert.Equal(CircuitStateHalfOpen, cb.State("foo.com"))
+	generation, err = cb.Allow(ctx, "foo.com")
+	assert.Nil(err)
+	_, err = cb.Allow(ctx, "foo.com")
+	assert.True(IsErrCircuitOpen(err))
+	cb.Record(ctx, "foo.com", generation, responseOK, nil)
+	assert.Equal(CircuitStateClosed, cb.State("foo.com"))
+}
+
+func TestCircuitBreakerHalfOpenFailure(t *testing.T) {
+	assert := assert.New(t)
+
+	cb, clock := newTestCircuitBreaker(
+		OptCircuitBreakerConsecutiveFailures(1),
+		OptCircuitBreakerHalfOpenRequests(2),
+	)
+	ctx := context.Background()
+
+	generation, err := cb.Allow(ctx, "foo.com")
+	assert.Nil(err)
+	cb.Record(ctx, "foo.com", generation, responseError, nil)
+	assert.Equal(CircuitStateOpen, cb.State("foo.com"))
+
+	clock.now = clock.now.Add(DefaultCircuitBreakerOpenTimeout)
+	generation, err = cb.Allow(ctx, "foo.com")
+	assert.Nil(err)
+	_, err = cb.Allow(ctx, "foo.com")
+	assert.Nil(err)
+	cb.Record(ctx, "foo.com", generation, responseOK, nil)
+	assert.Equal(CircuitStateHalfOpen, cb.State("foo.com"))
+	cb.Record(ctx, "foo.com", generation, responseError, nil)
+	assert.Equal(CircuitStateOpen, cb.State("foo.com"))
+}
+
+func TestCircuitBreakerStaleGeneration(t *testing.T) {
+	assert := assert.New(t)
+
+	cb, clock := newTestCircuitBreaker(OptCircuitBreakerConsecutiveFailures(1))
+	ctx := context.Background()
+
+	// a slow request is allowed while the circuit is closed.
+	stale, err := cb.Allow(ctx, "foo.com")
+	assert.Nil(err)
+
+	generation, err := cb.Allow(ctx, "foo.com")
+	assert.Nil(err)
+	cb.Record(ctx, "foo.com", generation, responseError, nil)
+	assert.Equal(CircuitStateOpen, cb.State("foo.com"))
+
+	clock.now = clock.now.Add(DefaultCircuitBreakerOpenTimeout)
+	trial, err := cb.Allow(ctx, "foo.com")
+	assert.Nil(err)
+
+	// the slow request finishing is not counted as the half-open trial.
+	cb.Record(ctx, "foo.com", stale, responseOK, nil)
+	assert.Equal(CircuitStateHalfOpen, cb.State("foo.com"))
+	cb.Record(ctx, "foo.com", stale, responseError, nil)
+	assert.Equal(CircuitStateHalfOpen, cb.State("foo.com"))
+
+	cb.Record(ctx, "foo.com", trial, responseOK, nil)
+	assert.Equal(CircuitStateClosed, cb.State("foo.com"))
+}
+
+func TestCircuitBreakerFailureRatio(t *testing.T) {
+	assert := assert.New(t)
+
+	cb, clock := newTestCircuitBreaker(
+		OptCircuitBreakerConsecutiveFailures(0),
+		OptCircuitBreakerFailureRatio(0.5, 4),
+		OptCircuitBreakerInterval(time.Minute),
+	)
+	ctx := context.Background()
+
+	cb.Record(ctx, "foo.com", 0, responseError, nil)
+	cb.Record(ctx, "foo.com", 0, responseOK, nil)
+	cb.Record(ctx, "foo.com", 0, responseError, nil)
+	assert.Equal(CircuitStateClosed, cb.State("foo.com"))
+
+	// the window resets after the interval
+	clock.now = clock.now.Add(time.Minute)
+	cb.Record(ctx, "foo.com", 0, responseOK, nil)
+	cb.Record(ctx, "foo.com", 0, responseOK, nil)
+	cb.Record(ctx, "foo.com", 0, responseError, nil)
+	assert.Equal(CircuitStateClosed, cb.State("foo.com"))
+	cb.Record(ctx, "foo.com", 0, responseError, nil)
+	assert.Equal(CircuitStateOpen, cb.State("foo.com"))
+}
+
+func TestCircuitBreakerIgnoresCanceled(t *testing.T) {
+	assert := assert.New(t)
+
+	cb, _ := newTestCircuitBreaker(OptCircuitBreakerConsecutiveFailures(1))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	cb.Record(ctx, "foo.com", 0, nil, context.Canceled)
+	assert.Equal(CircuitStateClosed, cb.State("foo.com"))
+}
+
+func TestCircuitBreakerEvents(t *testing.T) {
+	assert := assert.New(t)
+
+	log, err := logger.New(logger.OptAll())
+	assert.Nil(err)
+	defer log.Close()
+
+	events := make(chan *CircuitBreakerEvent, 1)
+	log.Listen(FlagCircuitBreaker, "test", func(_ context.Context, e logger.Event) {
+		events <- e.(*CircuitBreakerEvent)
+	})
+	collector := stats.NewMockCollector()
+	collector.Events = make(chan stats.MockMetric, 3)
+
+	cb, _ := newTestCircuitBreaker(
+		OptCircuitBreakerConsecutiveFailures(1),
+		OptCircuitBreakerLog(log),
+		OptCircuitBreakerStats(collector),
+	)
+	cb.Record(context.Background(), "foo.com", 0, responseError, nil)
+	_, err = cb.Allow(context.Background(), "foo.com")
+	assert.NotNil(err)
+
+	e := <-events
+	assert.Equal("foo.com", e.Host)
+	assert.Equal(CircuitStateClosed, e.From)
+	assert.Equal(CircuitStateOpen, e.To)
+
+	metric := <-collector.Events
+	assert.Equal(MetricNameCircuitBreakerStateChange, metric.Name)
+	assert.Equal([]string{stats.Tag(TagHost, "foo.com"), stats.Tag(TagCircuitState, string(CircuitStateOpen))}, metric.Tags)
+	metric = <-collector.Events
+	assert.Equal(MetricNameCircuitBreakerOpen, metric.Name)
+	assert.Equal(1.0, metric.Gauge)
+	metric = <-collector.Events
+	assert.Equal(MetricNameCircuitBreakerRejected, metric.Name)
+}
+
+func TestRequestCircuitBreaker(t *testing.T) {
+	assert := assert.New(t)
+
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	cb := NewCircuitBreaker(OptCircuitBreakerConsecutiveFailures(2))
+	defaults := Defaults{
+		OptCircuitBreaker(cb),
+		OptRetry(RetryPolicy{MaxAttempts: 5, InitialBackoff: time.Millisecond}),
+	}
+
+	res, err := New(server.URL, defaults...).DiscardWithResponse()
+	assert.True(IsErrCircuitOpen(err), "the retry should be rejected once the circuit opens")
+	assert.Nil(res)
+
+	err = New(server.URL, defaults...).Discard()
+	assert.True(IsErrCircuitOpen(err))
+	assert.Equal(2, atomic.LoadInt32(&calls))
+}
@@ -15,13 +15,41 @@ const (
 	MethodDelete = "DELETE"
 	// MethodOptions is a method.
 	MethodOptions = "OPTIONS"
+	// MethodHead is a method.
+	MethodHead = "HEAD"
+	// MethodTrace is a method.
+	MethodTrace = "TRACE"
 )
 
 const (
+	// HeaderAuthorization is a http header.
+	HeaderAuthorization = "Authorization"
 	// HeaderConnection is a http header.
 	HeaderConnection = "Connection"
 	// HeaderContentType is a http header.
 	HeaderContentType = "Content-Type"
+	// HeaderRetryAfter is a http header.
+	HeaderRetryAfter = "Retry-After"
+	// HeaderCacheControl is a http header.
+	HeaderCacheControl = "Cache-Control"
+	// HeaderETag is a http header.
+	HeaderETag = "ETag"
+	// HeaderLastModified is a http header.
+	HeaderLastModified = "Last-Modified"
+	// HeaderIfNoneMatch is a http header.
+	HeaderIfNoneMatch = "If-None-Match"
+	// HeaderIfModifiedSince is a http header.
+	HeaderIfModifiedSince = "If-Modified-Since"
+	// HeaderExpires is a http header.
+	HeaderExpires = "Expires"
+	// HeaderDate is a http header.
+	HeaderDate = "Date"
+	// HeaderAge is a http header.
+	HeaderAge = "Age"
+	// HeaderVary is a http header.
+	HeaderVary = "Vary"
+	// HeaderXCache is a http header set on responses served by a cache transport.
+	HeaderXCache = "X-Cache"
 )
 
 const (
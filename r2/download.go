@@ -0,0 +1,190 @@
+package r2
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/blend/go-sdk/ex"
+)
+
+// HeaderRange, HeaderContentRange and HeaderIfRange are http headers used for resumable downloads.
+const (
+	HeaderRange        = "Range"
+	HeaderContentRange = "Content-Range"
+	HeaderIfRange      = "If-Range"
+)
+
+// DownloadProgress is a callback that is called as a download is written.
+// Total is -1 if the size of the download is unknown.
+type DownloadProgress func(written, total int64)
+
+// DownloadOption is an option for downloads.
+type DownloadOption func(*DownloadOptions)
+
+// DownloadOptions are options for downloads.
+type DownloadOptions struct {
+	// Resume indicates that if the destination file exists, the download should resume
+	// from the end of the file with a `Range` header.
+	Resume bool
+	// IfRange is the validator of the response that started the file, sent as an `If-Range` header
+	// when resuming so that the server sends the full content if it has changed since.
+	IfRange string
+	// Progress is an optional progress callback.
+	Progress DownloadProgress
+}
+
+// OptDownloadResume sets if downloads should resume from the end of an existing file.
+func OptDownloadResume(resume bool) DownloadOption {
+	return func(do *DownloadOptions) { do.Resume = resume }
+}
+
+// OptDownloadIfRange sets the validator sent as an `If-Range` header when resuming.
+// It should be the `DownloadValidator` of the response that started the file.
+func OptDownloadIfRange(validator string) DownloadOption {
+	return func(do *DownloadOptions) { do.IfRange = validator }
+}
+
+// OptDownloadProgress sets the download progress callback.
+func OptDownloadProgress(progress DownloadProgress) DownloadOption {
+	return func(do *DownloadOptions) { do.Progress = progress }
+}
+
+// Download streams the response body to a file at a given path, and returns the response metadata.
+//
+// If resuming is enabled and the file exists, the request is sent with a `Range` header for the
+// rest of the file, and an `If-Range` header if a validator is set with `OptDownloadIfRange`.
+// If the server responds with the full content instead of a partial response, the file is overwritten.
+// Non-2xx responses, and partial responses that do not start at the end of the file, return an error
+// and leave the file untouched.
+func (r Request) Download(path string, options ...DownloadOption) (*http.Response, error) {
+	defer r.Close()
+
+	var opts DownloadOptions
+	for _, option := range options {
+		option(&opts)
+	}
+
+	var offset int64
+	if opts.Resume {
+		if info, err := os.Stat(path); err == nil && info.Size() > 0 {
+			offset = info.Size()
+			header := make(http.Header)
+			for key, values := range r.Request.Header {
+				header[key] = values
+			}
+			header.Set(HeaderRange, fmt.Sprintf("bytes=%d-", offset))
+			if opts.IfRange != "" {
+				header.Set(HeaderIfRange, opts.IfRange)
+			}
+			r.Request.Header = header
+		}
+	}
+
+	res, err := r.Do()
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	flags := os.O_CREATE | os.O_WRONLY
+	switch {
+	case offset > 0 && res.StatusCode == http.StatusRequestedRangeNotSatisfiable:
+		// the file is already complete.
+		if total, ok := contentRangeTotal(res); ok && total == offset {
+			if opts.Progress != nil {
+				opts.Progress(offset, offset)
+			}
+			return res, nil
+		}
+		return res, ex.New(ErrDownloadFailed, ex.OptMessagef("status code: %d", res.StatusCode))
+	case offset > 0 && res.StatusCode == http.StatusPartialContent:
+		if start, ok := contentRangeStart(res); !ok || start != offset {
+			return res, ex.New(ErrDownloadFailed, ex.OptMessagef("content range: %q, offset: %d", res.Header.Get(HeaderContentRange), offset))
+		}
+		flags |= os.O_APPEND
+	case res.StatusCode >= http.StatusOK && res.StatusCode < http.StatusMultipleChoices:
+		offset = 0
+		flags |= os.O_TRUNC
+	default:
+		return res, ex.New(ErrDownloadFailed, ex.OptMessagef("status code: %d", res.StatusCode))
+	}
+
+	total := int64(-1)
+	if res.ContentLength >= 0 {
+		total = offset + res.ContentLength
+	}
+
+	f, err := os.OpenFile(path, flags, 0644)
+	if err != nil {
+		return res, ex.New(err)
+	}
+	defer f.Close()
+
+	var dst io.Writer = f
+	if opts.Progress != nil {
+		dst = &progressWriter{Writer: f, written: offset, total: total, progress: opts.Progress}
+	}
+	if _, err := io.Copy(dst, res.Body); err != nil {
+		return res, ex.New(err)
+	}
+	return res, nil
+}
+
+type progressWriter struct {
+	io.Writer
+	written  int64
+	total    int64
+	progress DownloadProgress
+}
+
+func (pw *progressWriter) Write(p []byte) (int, error) {
+	n, err := pw.Writer.Write(p)
+	pw.written += int64(n)
+	pw.progress(pw.written, pw.total)
+	return n, err
+}
+
+// DownloadValidator returns the validator of a download response to resume it with `OptDownloadIfRange`.
+// It returns the `ETag` if it is a strong validator, the `Last-Modified` time otherwise, or an empty string.
+func DownloadValidator(res *http.Response) string {
+	if etag := res.Header.Get(HeaderETag); etag != "" && !strings.HasPrefix(etag, "W/") {
+		return etag
+	}
+	return res.Header.Get(HeaderLastModified)
+}
+
+// contentRangeStart returns the first byte position from a `Content-Range` header, e.g. `bytes 1000-1999/2000`.
+func contentRangeStart(res *http.Response) (int64, bool) {
+	value := strings.TrimSpace(res.Header.Get(HeaderContentRange))
+	if !strings.HasPrefix(value, "bytes ") {
+		return 0, false
+	}
+	value = strings.TrimSpace(strings.TrimPrefix(value, "bytes "))
+	index := strings.Index(value, "-")
+	if index < 0 {
+		return 0, false
+	}
+	start, err := strconv.ParseInt(value[:index], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return start, true
+}
+
+// contentRangeTotal returns the total size from a `Content-Range` header, e.g. `bytes */1234`.
+func contentRangeTotal(res *http.Response) (int64, bool) {
+	value := res.Header.Get(HeaderContentRange)
+	index := strings.LastIndex(value, "/")
+	if index < 0 {
+		return 0, false
+	}
+	total, err := strconv.ParseInt(value[index+1:], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return total, true
+}
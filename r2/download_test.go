@@ -0,0 +1,208 @@
+package r2
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func downloadTestServer(contents []byte, ranges *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/file" {
+			http.NotFound(rw, r)
+			return
+		}
+		if ranges != nil {
+			*ranges = append(*ranges, r.Header.Get(HeaderRange))
+		}
+		http.ServeContent(rw, r, "file", time.Time{}, bytes.NewReader(contents))
+	}))
+}
+
+func TestRequestDownload(t *testing.T) {
+	assert := assert.New(t)
+
+	contents := []byte(strings.Repeat("download contents\n", 1024))
+	server := downloadTestServer(contents, nil)
+	defer server.Close()
+
+	tempDir, err := ioutil.TempDir("", "r2_download")
+	assert.Nil(err)
+	defer os.RemoveAll(tempDir)
+	path := filepath.Join(tempDir, "file")
+
+	var lastWritten, lastTotal int64
+	res, err := New(server.URL, OptPath("/file")).Download(path, OptDownloadProgress(func(written, total int64) {
+		lastWritten, lastTotal = written, total
+	}))
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, res.StatusCode)
+	assert.Equal(len(contents), lastWritten)
+	assert.Equal(len(contents), lastTotal)
+
+	written, err := ioutil.ReadFile(path)
+	assert.Nil(err)
+	assert.Equal(contents, written)
+}
+
+func TestRequestDownloadResume(t *testing.T) {
+	assert := assert.New(t)
+
+	contents := []byte(strings.Repeat("download contents\n", 1024))
+	var ranges []string
+	server := downloadTestServer(contents, &ranges)
+	defer server.Close()
+
+	tempDir, err := ioutil.TempDir("", "r2_download")
+	assert.Nil(err)
+	defer os.RemoveAll(tempDir)
+	path := filepath.Join(tempDir, "file")
+	assert.Nil(ioutil.WriteFile(path, contents[:1000], 0644))
+
+	var lastWritten, lastTotal int64
+	res, err := New(server.URL, OptPath("/file")).Download(path,
+		OptDownloadResume(true),
+		OptDownloadProgress(func(written, total int64) {
+			lastWritten, lastTotal = written, total
+		}),
+	)
+	assert.Nil(err)
+	assert.Equal(http.StatusPartialContent, res.StatusCode)
+	assert.Equal([]string{"bytes=1000-"}, ranges)
+	assert.Equal(len(contents), lastWritten)
+	assert.Equal(len(contents), lastTotal)
+
+	written, err := ioutil.ReadFile(path)
+	assert.Nil(err)
+	assert.Equal(contents, written)
+
+	// the file is complete, so resuming again should not change it.
+	_, err = New(server.URL, OptPath("/file")).Download(path, OptDownloadResume(true))
+	assert.Nil(err)
+	assert.Equal([]string{"bytes=1000-", "bytes=18432-"}, ranges)
+	written, err = ioutil.ReadFile(path)
+	assert.Nil(err)
+	assert.Equal(contents, written)
+}
+
+func TestRequestDownloadResumeDisabled(t *testing.T) {
+	assert := assert.New(t)
+
+	contents := []byte("download contents")
+	var ranges []string
+	server := downloadTestServer(contents, &ranges)
+	defer server.Close()
+
+	tempDir, err := ioutil.TempDir("", "r2_download")
+	assert.Nil(err)
+	defer os.RemoveAll(tempDir)
+	path := filepath.Join(tempDir, "file")
+	assert.Nil(ioutil.WriteFile(path, []byte("stale contents that are longer"), 0644))
+
+	_, err = New(server.URL, OptPath("/file")).Download(path)
+	assert.Nil(err)
+	assert.Equal([]string{""}, ranges)
+
+	written, err := ioutil.ReadFile(path)
+	assert.Nil(err)
+	assert.Equal(contents, written)
+}
+
+func TestRequestDownloadFailed(t *testing.T) {
+	assert := assert.New(t)
+
+	server := downloadTestServer([]byte("contents"), nil)
+	defer server.Close()
+
+	tempDir, err := ioutil.TempDir("", "r2_download")
+	assert.Nil(err)
+	defer os.RemoveAll(tempDir)
+	path := filepath.Join(tempDir, "file")
+
+	res, err := New(server.URL, OptPath("/not-found")).Download(path)
+	assert.True(IsErrDownloadFailed(err))
+	assert.Equal(http.StatusNotFound, res.StatusCode)
+	_, err = os.Stat(path)
+	assert.True(os.IsNotExist(err))
+}
+
+func TestRequestDownloadResumeIfRange(t *testing.T) {
+	assert := assert.New(t)
+
+	contents := []byte(strings.Repeat("download contents\n", 1024))
+	var ifRanges []string
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		ifRanges = append(ifRanges, r.Header.Get(HeaderIfRange))
+		rw.Header().Set(HeaderETag, `"v2"`)
+		http.ServeContent(rw, r, "file", time.Time{}, bytes.NewReader(contents))
+	}))
+	defer server.Close()
+
+	tempDir, err := ioutil.TempDir("", "r2_download")
+	assert.Nil(err)
+	defer os.RemoveAll(tempDir)
+	path := filepath.Join(tempDir, "file")
+
+	// the file was started from a different version, so the full content is sent.
+	assert.Nil(ioutil.WriteFile(path, []byte("stale contents"), 0644))
+	res, err := New(server.URL).Download(path, OptDownloadResume(true), OptDownloadIfRange(`"v1"`))
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, res.StatusCode)
+	assert.Equal(`"v2"`, DownloadValidator(res))
+	written, err := ioutil.ReadFile(path)
+	assert.Nil(err)
+	assert.Equal(contents, written)
+
+	assert.Nil(ioutil.WriteFile(path, contents[:1000], 0644))
+	res, err = New(server.URL).Download(path, OptDownloadResume(true), OptDownloadIfRange(`"v2"`))
+	assert.Nil(err)
+	assert.Equal(http.StatusPartialContent, res.StatusCode)
+	assert.Equal([]string{`"v1"`, `"v2"`}, ifRanges)
+	written, err = ioutil.ReadFile(path)
+	assert.Nil(err)
+	assert.Equal(contents, written)
+}
+
+func TestRequestDownloadResumeContentRangeMismatch(t *testing.T) {
+	assert := assert.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set(HeaderContentRange, "bytes 0-7/8")
+		rw.WriteHeader(http.StatusPartialContent)
+		rw.Write([]byte("contents"))
+	}))
+	defer server.Close()
+
+	tempDir, err := ioutil.TempDir("", "r2_download")
+	assert.Nil(err)
+	defer os.RemoveAll(tempDir)
+	path := filepath.Join(tempDir, "file")
+	assert.Nil(ioutil.WriteFile(path, []byte("cont"), 0644))
+
+	_, err = New(server.URL).Download(path, OptDownloadResume(true))
+	assert.True(IsErrDownloadFailed(err))
+	written, err := ioutil.ReadFile(path)
+	assert.Nil(err)
+	assert.Equal("cont", string(written))
+}
+
+func TestDownloadValidator(t *testing.T) {
+	assert := assert.New(t)
+
+	res := &http.Response{Header: http.Header{}}
+	assert.Empty(DownloadValidator(res))
+	res.Header.Set(HeaderLastModified, "Mon, 02 Jan 2006 15:04:05 GMT")
+	assert.Equal("Mon, 02 Jan 2006 15:04:05 GMT", DownloadValidator(res))
+	res.Header.Set(HeaderETag, `W/"weak"`)
+	assert.Equal("Mon, 02 Jan 2006 15:04:05 GMT", DownloadValidator(res))
+	res.Header.Set(HeaderETag, `"strong"`)
+	assert.Equal(`"strong"`, DownloadValidator(res))
+}
@@ -0,0 +1,44 @@
+package r2
+
+import (
+	"net/url"
+
+	"github.com/blend/go-sdk/ex"
+)
+
+const (
+	// ErrCircuitOpen is returned if a request is rejected because the circuit breaker for its host is open.
+	ErrCircuitOpen ex.Class = "r2; circuit breaker is open"
+	// ErrFixtureNotMatched is returned by a strict fixture transport if a request does not match a fixture.
+	ErrFixtureNotMatched ex.Class = "r2; request does not match a fixture"
+	// ErrDownloadFailed is returned by downloads if the response is not successful.
+	ErrDownloadFailed ex.Class = "r2; download failed"
+)
+
+// IsErrCircuitOpen returns if an error is a circuit open error.
+func IsErrCircuitOpen(err error) bool {
+	if err == nil {
+		return false
+	}
+	return ex.Is(err, ErrCircuitOpen)
+}
+
+// IsErrFixtureNotMatched returns if an error is a fixture not matched error.
+// It also unwraps the `*url.Error` the http client wraps transport errors with.
+func IsErrFixtureNotMatched(err error) bool {
+	if err == nil {
+		return false
+	}
+	if typed, ok := err.(*url.Error); ok {
+		err = typed.Err
+	}
+	return ex.Is(err, ErrFixtureNotMatched)
+}
+
+// IsErrDownloadFailed returns if an error is a download failed error.
+func IsErrDownloadFailed(err error) bool {
+	if err == nil {
+		return false
+	}
+	return ex.Is(err, ErrDownloadFailed)
+}
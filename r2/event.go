@@ -43,6 +43,8 @@ type Event struct {
 	Response *http.Response
 	// The response body.
 	Body []byte
+	// Attempt is the attempt number if the request was sent with a retry policy.
+	Attempt int
 }
 
 // WriteText writes the event to a text writer.
@@ -52,6 +54,9 @@ func (e *Event) WriteText(tf logger.TextFormatter, wr io.Writer) {
 	} else if e.Request != nil {
 		io.WriteString(wr, fmt.Sprintf("%s %s", e.Request.Method, e.Request.URL.String()))
 	}
+	if e.Attempt > 1 {
+		io.WriteString(wr, fmt.Sprintf(" (attempt %d)", e.Attempt))
+	}
 	if e.Body != nil {
 		io.WriteString(wr, logger.Newline)
 		io.WriteString(wr, string(e.Body))
@@ -87,6 +92,9 @@ func (e *Event) MarshalJSON() ([]byte, error) {
 	if e.Body != nil {
 		output["body"] = string(e.Body)
 	}
+	if e.Attempt > 0 {
+		output["attempt"] = e.Attempt
+	}
 
 	return json.Marshal(logger.MergeDecomposed(e.EventMeta.Decompose(), output))
 }
@@ -105,7 +113,8 @@ type EventJSONSchema struct {
 		ContentLength int                 `json:"contentLength"`
 		Headers       map[string][]string `json:"headers"`
 	} `json:"res"`
-	Body string `json:"body"`
+	Body    string `json:"body"`
+	Attempt int    `json:"attempt"`
 }
 
 func tryHeader(headers http.Header, keys ...string) string {
@@ -51,3 +51,10 @@ func OptEventBody(body []byte) EventOption {
 		e.Body = body
 	}
 }
+
+// OptEventAttempt sets the attempt number.
+func OptEventAttempt(attempt int) EventOption {
+	return func(e *Event) {
+		e.Attempt = attempt
+	}
+}
@@ -0,0 +1,181 @@
+package r2
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"unicode/utf8"
+)
+
+// FixtureBodyEncodingBase64 is the body encoding used for bodies that are not valid utf-8.
+const FixtureBodyEncodingBase64 = "base64"
+
+// FixtureRedacted is the value redacted headers are replaced with.
+const FixtureRedacted = "REDACTED"
+
+// DefaultFixtureRedactHeaders are the headers redacted from recorded fixtures by default.
+var DefaultFixtureRedactHeaders = []string{
+	"Authorization",
+	"Proxy-Authorization",
+	"Cookie",
+	"Set-Cookie",
+}
+
+// DefaultFixtureRedactQuery are the query parameters redacted from recorded fixtures by default.
+var DefaultFixtureRedactQuery = []string{
+	"access_token",
+	"id_token",
+	"refresh_token",
+	"client_secret",
+	"api_key",
+}
+
+// DefaultFixtureMatchers are the matchers used to match requests to fixtures by default.
+var DefaultFixtureMatchers = []FixtureMatcher{
+	FixtureMatchMethod,
+	FixtureMatchPath,
+	FixtureMatchQuery,
+}
+
+// Fixture is a recorded request and response pair.
+type Fixture struct {
+	Request  FixtureRequest  `json:"request"`
+	Response FixtureResponse `json:"response"`
+}
+
+// FixtureRequest is the recorded request of a fixture.
+type FixtureRequest struct {
+	Method       string      `json:"method"`
+	URL          string      `json:"url"`
+	Header       http.Header `json:"header,omitempty"`
+	Body         string      `json:"body,omitempty"`
+	BodyEncoding string      `json:"bodyEncoding,omitempty"`
+}
+
+// ParsedURL returns the parsed url of the request.
+func (fr FixtureRequest) ParsedURL() *url.URL {
+	parsed, err := url.Parse(fr.URL)
+	if err != nil {
+		return &url.URL{}
+	}
+	return parsed
+}
+
+// BodyBytes returns the decoded request body.
+func (fr FixtureRequest) BodyBytes() []byte {
+	return decodeFixtureBody(fr.Body, fr.BodyEncoding)
+}
+
+// FixtureResponse is the recorded response of a fixture.
+type FixtureResponse struct {
+	StatusCode   int         `json:"statusCode"`
+	Header       http.Header `json:"header,omitempty"`
+	Body         string      `json:"body,omitempty"`
+	BodyEncoding string      `json:"bodyEncoding,omitempty"`
+}
+
+// BodyBytes returns the decoded response body.
+func (fr FixtureResponse) BodyBytes() []byte {
+	return decodeFixtureBody(fr.Body, fr.BodyEncoding)
+}
+
+// HTTPResponse returns the response as an http response for a given request.
+func (fr FixtureResponse) HTTPResponse(req *http.Request) *http.Response {
+	body := fr.BodyBytes()
+	header := http.Header{}
+	for key, values := range fr.Header {
+		header[key] = append([]string(nil), values...)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", fr.StatusCode, http.StatusText(fr.StatusCode)),
+		StatusCode:    fr.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// FixtureMatcher returns if a request matches a recorded fixture request.
+type FixtureMatcher func(req *http.Request, body []byte, fixture FixtureRequest) bool
+
+// FixtureMatchMethod matches fixtures on the request method.
+func FixtureMatchMethod(req *http.Request, _ []byte, fixture FixtureRequest) bool {
+	return methodOrDefault(req.Method) == methodOrDefault(fixture.Method)
+}
+
+// FixtureMatchPath matches fixtures on the request host and path.
+func FixtureMatchPath(req *http.Request, _ []byte, fixture FixtureRequest) bool {
+	fixtureURL := fixture.ParsedURL()
+	return req.URL.Host == fixtureURL.Host && req.URL.Path == fixtureURL.Path
+}
+
+// FixtureMatchQuery matches fixtures on the request query, ignoring the order of the parameters.
+// Parameters that were redacted when the fixture was recorded match any value.
+func FixtureMatchQuery(req *http.Request, _ []byte, fixture FixtureRequest) bool {
+	query := req.URL.Query()
+	fixtureQuery := fixture.ParsedURL().Query()
+	for key, values := range fixtureQuery {
+		if isFixtureRedacted(values) && len(query[key]) == len(values) {
+			query[key] = values
+		}
+	}
+	return query.Encode() == fixtureQuery.Encode()
+}
+
+// FixtureMatchBody matches fixtures on the request body.
+func FixtureMatchBody(_ *http.Request, body []byte, fixture FixtureRequest) bool {
+	return bytes.Equal(body, fixture.BodyBytes())
+}
+
+// FixtureMatchHeader returns a matcher that matches fixtures on the given request headers.
+func FixtureMatchHeader(keys ...string) FixtureMatcher {
+	return func(req *http.Request, _ []byte, fixture FixtureRequest) bool {
+		for _, key := range keys {
+			if req.Header.Get(key) != fixture.Header.Get(key) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+func isFixtureRedacted(values []string) bool {
+	for _, value := range values {
+		if value != FixtureRedacted {
+			return false
+		}
+	}
+	return len(values) > 0
+}
+
+func methodOrDefault(method string) string {
+	if method == "" {
+		return MethodGet
+	}
+	return method
+}
+
+func encodeFixtureBody(body []byte) (string, string) {
+	if utf8.Valid(body) {
+		return string(body), ""
+	}
+	return base64.StdEncoding.EncodeToString(body), FixtureBodyEncodingBase64
+}
+
+func decodeFixtureBody(body, encoding string) []byte {
+	if encoding == FixtureBodyEncodingBase64 {
+		decoded, err := base64.StdEncoding.DecodeString(body)
+		if err != nil {
+			return nil
+		}
+		return decoded
+	}
+	return []byte(body)
+}
@@ -0,0 +1,293 @@
+package r2
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/blend/go-sdk/ex"
+)
+
+var (
+	_ http.RoundTripper = (*FixtureTransport)(nil)
+)
+
+// FixtureMode is the mode of a fixture transport.
+type FixtureMode string
+
+// Fixture modes.
+const (
+	// FixtureModeReplay serves responses from the recorded fixtures.
+	FixtureModeReplay FixtureMode = "replay"
+	// FixtureModeRecord sends requests to the real server and records them.
+	FixtureModeRecord FixtureMode = "record"
+)
+
+// NewFixtureTransport returns a new fixture transport for a given golden file.
+/*
+In replay mode (the default) the golden file is loaded and must exist. In record mode,
+requests are sent with the inner transport and recorded; call `Save` once the test is done
+to write the golden file.
+
+	transport, err := r2.NewFixtureTransport("testdata/users.json", r2.OptFixtureMode(r2.FixtureModeRecord))
+	...
+	defer transport.Save()
+	err = r2.New(server.URL, r2.OptTransport(transport)).JSON(&users)
+*/
+func NewFixtureTransport(path string, options ...FixtureTransportOption) (*FixtureTransport, error) {
+	ft := &FixtureTransport{
+		Path:          path,
+		Mode:          FixtureModeReplay,
+		Strict:        true,
+		Matchers:      DefaultFixtureMatchers,
+		RedactHeaders: DefaultFixtureRedactHeaders,
+		RedactQuery:   DefaultFixtureRedactQuery,
+	}
+	for _, option := range options {
+		option(ft)
+	}
+	if ft.Mode == FixtureModeReplay {
+		if err := ft.load(); err != nil {
+			return nil, err
+		}
+	}
+	return ft, nil
+}
+
+// FixtureTransportOption is an option for fixture transports.
+type FixtureTransportOption func(*FixtureTransport)
+
+// OptFixtureMode sets the fixture transport mode.
+func OptFixtureMode(mode FixtureMode) FixtureTransportOption {
+	return func(ft *FixtureTransport) { ft.Mode = mode }
+}
+
+// OptFixtureTransport sets the inner transport requests are sent with when recording,
+// or when a request is unmatched and the transport is not strict.
+func OptFixtureTransport(transport http.RoundTripper) FixtureTransportOption {
+	return func(ft *FixtureTransport) { ft.Transport = transport }
+}
+
+// OptFixtureStrict sets if unmatched requests fail with `ErrFixtureNotMatched`,
+// or are sent with the inner transport.
+func OptFixtureStrict(strict bool) FixtureTransportOption {
+	return func(ft *FixtureTransport) { ft.Strict = strict }
+}
+
+// OptFixtureMatchers sets the matchers used to match requests to fixtures.
+func OptFixtureMatchers(matchers ...FixtureMatcher) FixtureTransportOption {
+	return func(ft *FixtureTransport) { ft.Matchers = matchers }
+}
+
+// OptFixtureRedactHeaders sets the headers that are redacted from recorded fixtures.
+func OptFixtureRedactHeaders(headers ...string) FixtureTransportOption {
+	return func(ft *FixtureTransport) { ft.RedactHeaders = headers }
+}
+
+// OptFixtureRedactQuery sets the query parameters that are redacted from recorded fixtures.
+func OptFixtureRedactQuery(params ...string) FixtureTransportOption {
+	return func(ft *FixtureTransport) { ft.RedactQuery = params }
+}
+
+// FixtureTransport is a round tripper that records requests and responses to a golden file,
+// and replays them in tests.
+//
+// When replaying, each request is matched against the fixtures in order; fixtures are used
+// once each, except that the last matching fixture is reused once all the matches are used.
+type FixtureTransport struct {
+	Path          string
+	Mode          FixtureMode
+	Strict        bool
+	Transport     http.RoundTripper
+	Matchers      []FixtureMatcher
+	RedactHeaders []string
+	RedactQuery   []string
+
+	sync.Mutex
+	Fixtures []Fixture
+	used     []bool
+}
+
+// RoundTrip implements http.RoundTripper.
+func (ft *FixtureTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req, body, err := cloneRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	if ft.Mode == FixtureModeRecord {
+		return ft.record(req, body)
+	}
+	if fixture, ok := ft.match(req, body); ok {
+		return fixture.Response.HTTPResponse(req), nil
+	}
+	if ft.Strict {
+		return nil, ex.New(ErrFixtureNotMatched, ex.OptMessagef("%s %s", methodOrDefault(req.Method), ft.redactURL(req.URL)))
+	}
+	return ft.transport().RoundTrip(req)
+}
+
+// Unused returns the fixtures that have not been matched by a request.
+func (ft *FixtureTransport) Unused() (output []Fixture) {
+	ft.Lock()
+	defer ft.Unlock()
+	for index, fixture := range ft.Fixtures {
+		if !ft.used[index] {
+			output = append(output, fixture)
+		}
+	}
+	return
+}
+
+// Save writes the recorded fixtures to the golden file.
+func (ft *FixtureTransport) Save() error {
+	ft.Lock()
+	defer ft.Unlock()
+
+	contents, err := json.MarshalIndent(ft.Fixtures, "", "  ")
+	if err != nil {
+		return ex.New(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(ft.Path), 0755); err != nil {
+		return ex.New(err)
+	}
+	return ex.New(ioutil.WriteFile(ft.Path, append(contents, '\n'), 0644))
+}
+
+func (ft *FixtureTransport) load() error {
+	contents, err := ioutil.ReadFile(ft.Path)
+	if err != nil {
+		return ex.New(err)
+	}
+	var fixtures []Fixture
+	if err := json.Unmarshal(contents, &fixtures); err != nil {
+		return ex.New(err, ex.OptMessagef("fixture path: %s", ft.Path))
+	}
+	ft.Fixtures = fixtures
+	ft.used = make([]bool, len(fixtures))
+	return nil
+}
+
+func (ft *FixtureTransport) record(req *http.Request, body []byte) (*http.Response, error) {
+	res, err := ft.transport().RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	resBody, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, ex.New(err)
+	}
+	res.Body = ioutil.NopCloser(bytes.NewReader(resBody))
+
+	var fixture Fixture
+	fixture.Request.Method = methodOrDefault(req.Method)
+	fixture.Request.URL = ft.redactURL(req.URL)
+	fixture.Request.Header = ft.redact(req.Header)
+	fixture.Request.Body, fixture.Request.BodyEncoding = encodeFixtureBody(body)
+	fixture.Response.StatusCode = res.StatusCode
+	fixture.Response.Header = ft.redact(res.Header)
+	fixture.Response.Body, fixture.Response.BodyEncoding = encodeFixtureBody(resBody)
+
+	ft.Lock()
+	ft.Fixtures = append(ft.Fixtures, fixture)
+	ft.used = append(ft.used, true)
+	ft.Unlock()
+	return res, nil
+}
+
+func (ft *FixtureTransport) match(req *http.Request, body []byte) (Fixture, bool) {
+	ft.Lock()
+	defer ft.Unlock()
+
+	last := -1
+	for index, fixture := range ft.Fixtures {
+		if !ft.matches(req, body, fixture.Request) {
+			continue
+		}
+		if !ft.used[index] {
+			ft.used[index] = true
+			return fixture, true
+		}
+		last = index
+	}
+	if last >= 0 {
+		return ft.Fixtures[last], true
+	}
+	return Fixture{}, false
+}
+
+func (ft *FixtureTransport) matches(req *http.Request, body []byte, fixture FixtureRequest) bool {
+	for _, matcher := range ft.Matchers {
+		if !matcher(req, body, fixture) {
+			return false
+		}
+	}
+	return true
+}
+
+func (ft *FixtureTransport) redact(header http.Header) http.Header {
+	if len(header) == 0 {
+		return nil
+	}
+	output := make(http.Header, len(header))
+	for key, values := range header {
+		output[key] = append([]string(nil), values...)
+	}
+	for _, key := range ft.RedactHeaders {
+		if output.Get(key) != "" {
+			output.Set(key, FixtureRedacted)
+		}
+	}
+	return output
+}
+
+func (ft *FixtureTransport) redactURL(u *url.URL) string {
+	query := u.Query()
+	var redacted bool
+	for _, key := range ft.RedactQuery {
+		if values, ok := query[key]; ok {
+			for index := range values {
+				values[index] = FixtureRedacted
+			}
+			redacted = true
+		}
+	}
+	if !redacted {
+		return u.String()
+	}
+	output := *u
+	output.RawQuery = query.Encode()
+	return output.String()
+}
+
+func (ft *FixtureTransport) transport() http.RoundTripper {
+	if ft.Transport != nil {
+		return ft.Transport
+	}
+	return http.DefaultTransport
+}
+
+// cloneRequestBody reads the request body and returns a copy of the request with a body that can be read again,
+// leaving the caller's request unchanged.
+func cloneRequestBody(req *http.Request) (*http.Request, []byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return req, nil, nil
+	}
+	body, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, nil, ex.New(err)
+	}
+	clone := req.WithContext(req.Context())
+	clone.Body = ioutil.NopCloser(bytes.NewReader(body))
+	clone.GetBody = func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(body)), nil
+	}
+	return clone, body, nil
+}
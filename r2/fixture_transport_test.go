@@ -0,0 +1,197 @@
+package r2
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func mockServerEcho() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "secret"})
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, "%s %s?%s %s", r.Method, r.URL.Path, r.URL.RawQuery, string(body))
+	}))
+}
+
+func TestFixtureTransportRecordReplay(t *testing.T) {
+	assert := assert.New(t)
+
+	tempDir, err := ioutil.TempDir("", "r2-fixtures")
+	assert.Nil(err)
+	defer os.RemoveAll(tempDir)
+	path := filepath.Join(tempDir, "testdata", "fixtures.json")
+
+	server := mockServerEcho()
+	remoteURL := server.URL
+
+	recorder, err := NewFixtureTransport(path, OptFixtureMode(FixtureModeRecord))
+	assert.Nil(err)
+
+	contents, err := New(remoteURL,
+		OptTransport(recorder),
+		OptPath("/foo"),
+		OptQueryValue("a", "1"),
+		OptHeaderValue("Authorization", "Bearer token"),
+	).Bytes()
+	assert.Nil(err)
+	assert.Equal("GET /foo?a=1 ", string(contents))
+
+	contents, err = New(remoteURL,
+		OptTransport(recorder),
+		OptPost(),
+		OptPath("/bar"),
+		OptBody(ioutil.NopCloser(strings.NewReader("hello"))),
+	).Bytes()
+	assert.Nil(err)
+	assert.Equal("POST /bar? hello", string(contents))
+	assert.Nil(recorder.Save())
+	server.Close()
+
+	golden, err := ioutil.ReadFile(path)
+	assert.Nil(err)
+	assert.NotContains(string(golden), "Bearer token")
+	assert.NotContains(string(golden), "secret")
+	assert.Contains(string(golden), FixtureRedacted)
+
+	replay, err := NewFixtureTransport(path, OptFixtureMatchers(append(DefaultFixtureMatchers, FixtureMatchBody)...))
+	assert.Nil(err)
+	assert.Len(replay.Fixtures, 2)
+	assert.Len(replay.Unused(), 2)
+
+	contents, res, err := New(remoteURL,
+		OptTransport(replay),
+		OptPost(),
+		OptPath("/bar"),
+		OptBody(ioutil.NopCloser(strings.NewReader("hello"))),
+	).BytesWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, res.StatusCode)
+	assert.Equal("POST /bar? hello", string(contents))
+	assert.Len(replay.Unused(), 1)
+
+	contents, err = New(remoteURL, OptTransport(replay), OptPath("/foo"), OptQueryValue("a", "1")).Bytes()
+	assert.Nil(err)
+	assert.Equal("GET /foo?a=1 ", string(contents))
+	assert.Empty(replay.Unused())
+
+	// fixtures are reused once they have all been matched
+	contents, err = New(remoteURL, OptTransport(replay), OptPath("/foo"), OptQueryValue("a", "1")).Bytes()
+	assert.Nil(err)
+	assert.Equal("GET /foo?a=1 ", string(contents))
+
+	_, err = New(remoteURL, OptTransport(replay), OptPath("/foo"), OptQueryValue("a", "2")).Bytes()
+	assert.True(IsErrFixtureNotMatched(err))
+
+	_, err = New(remoteURL,
+		OptTransport(replay),
+		OptPost(),
+		OptPath("/bar"),
+		OptBody(ioutil.NopCloser(strings.NewReader("goodbye"))),
+	).Bytes()
+	assert.True(IsErrFixtureNotMatched(err))
+}
+
+func TestFixtureTransportRedactQuery(t *testing.T) {
+	assert := assert.New(t)
+
+	server := mockServerOK()
+	recorder, err := NewFixtureTransport("", OptFixtureMode(FixtureModeRecord))
+	assert.Nil(err)
+	_, err = New(server.URL, OptTransport(recorder), OptQueryValue("a", "1"), OptQueryValue("access_token", "secret")).Bytes()
+	assert.Nil(err)
+	server.Close()
+
+	assert.Len(recorder.Fixtures, 1)
+	assert.NotContains(recorder.Fixtures[0].Request.URL, "secret")
+	assert.Contains(recorder.Fixtures[0].Request.URL, "access_token="+FixtureRedacted)
+	assert.Contains(recorder.Fixtures[0].Request.URL, "a=1")
+
+	// redacted parameters match any value when replaying.
+	replay := &FixtureTransport{Strict: true, Matchers: DefaultFixtureMatchers, Fixtures: recorder.Fixtures, used: make([]bool, 1)}
+	contents, err := New(server.URL, OptTransport(replay), OptQueryValue("a", "1"), OptQueryValue("access_token", "other")).Bytes()
+	assert.Nil(err)
+	assert.Equal("OK!\n", string(contents))
+
+	_, err = New(server.URL, OptTransport(replay), OptQueryValue("a", "1")).Bytes()
+	assert.True(IsErrFixtureNotMatched(err))
+}
+
+func TestFixtureTransportRequestUnchanged(t *testing.T) {
+	assert := assert.New(t)
+
+	server := mockServerEcho()
+	defer server.Close()
+
+	recorder, err := NewFixtureTransport("", OptFixtureMode(FixtureModeRecord))
+	assert.Nil(err)
+	req, err := http.NewRequest(MethodPost, server.URL, strings.NewReader("hello"))
+	assert.Nil(err)
+	body := req.Body
+	res, err := recorder.RoundTrip(req)
+	assert.Nil(err)
+	defer res.Body.Close()
+	contents, err := ioutil.ReadAll(res.Body)
+	assert.Nil(err)
+	assert.Equal("POST /? hello", string(contents))
+	assert.True(body == req.Body)
+	assert.True(res.Request != req)
+}
+
+func TestFixtureTransportNotStrict(t *testing.T) {
+	assert := assert.New(t)
+
+	tempFile, err := ioutil.TempFile("", "r2-fixtures")
+	assert.Nil(err)
+	defer os.Remove(tempFile.Name())
+	_, err = tempFile.WriteString("[]")
+	assert.Nil(err)
+	assert.Nil(tempFile.Close())
+
+	server := mockServerOK()
+	defer server.Close()
+
+	replay, err := NewFixtureTransport(tempFile.Name(), OptFixtureStrict(false))
+	assert.Nil(err)
+	contents, err := New(server.URL, OptTransport(replay)).Bytes()
+	assert.Nil(err)
+	assert.Equal("OK!\n", string(contents))
+}
+
+func TestFixtureTransportMissingFile(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := NewFixtureTransport("testdata/does-not-exist.json")
+	assert.NotNil(err)
+}
+
+func TestFixtureBodyEncoding(t *testing.T) {
+	assert := assert.New(t)
+
+	body, encoding := encodeFixtureBody([]byte("hello"))
+	assert.Equal("hello", body)
+	assert.Empty(encoding)
+
+	binary := []byte{0xff, 0xfe, 0x00}
+	body, encoding = encodeFixtureBody(binary)
+	assert.Equal(FixtureBodyEncodingBase64, encoding)
+	assert.Equal(binary, decodeFixtureBody(body, encoding))
+}
+
+func TestFixtureMatchHeader(t *testing.T) {
+	assert := assert.New(t)
+
+	req, _ := http.NewRequest(MethodGet, "http://foo.com", nil)
+	req.Header.Set("X-Tenant", "a")
+	matcher := FixtureMatchHeader("X-Tenant")
+	assert.True(matcher(req, nil, FixtureRequest{Header: http.Header{"X-Tenant": []string{"a"}}}))
+	assert.False(matcher(req, nil, FixtureRequest{Header: http.Header{"X-Tenant": []string{"b"}}}))
+}
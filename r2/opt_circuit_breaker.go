@@ -0,0 +1,10 @@
+package r2
+
+// OptCircuitBreaker sets the circuit breaker for the request.
+// The circuit breaker is keyed by the request host, and should be shared between requests.
+func OptCircuitBreaker(cb *CircuitBreaker) Option {
+	return func(r *Request) error {
+		r.CircuitBreaker = cb
+		return nil
+	}
+}
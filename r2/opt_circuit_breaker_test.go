@@ -0,0 +1,15 @@
+package r2
+
+import (
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestOptCircuitBreaker(t *testing.T) {
+	assert := assert.New(t)
+
+	cb := NewCircuitBreaker()
+	r := New("http://foo.com", OptCircuitBreaker(cb))
+	assert.True(cb == r.CircuitBreaker)
+}
@@ -0,0 +1,19 @@
+package r2
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/blend/go-sdk/webutil"
+)
+
+// OptHMACSignature signs requests with an HMAC-SHA512 signature of the request timestamp,
+// method, path and body, set in the `X-Signature` and `X-Signature-Timestamp` headers.
+//
+// Signed requests can be verified with `web.HMACSignatureRequired`, or `webutil.VerifyRequestSignature`.
+func OptHMACSignature(key []byte) Option {
+	return OptRequestSigner(func(req *http.Request, body []byte) error {
+		webutil.SignRequest(req, key, body, time.Now().UTC())
+		return nil
+	})
+}
@@ -0,0 +1,49 @@
+package r2
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/webutil"
+)
+
+func TestOptHMACSignature(t *testing.T) {
+	assert := assert.New(t)
+
+	key := []byte("test-key")
+	var attempts int
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		attempts++
+		body, _ := ioutil.ReadAll(req.Body)
+		if err := webutil.VerifyRequestSignature(req, body, time.Now().UTC(), webutil.DefaultSignatureTolerance, key); err != nil {
+			rw.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	res, err := New(server.URL+"/webhooks?source=test",
+		OptPost(),
+		OptBody(ioutil.NopCloser(bytes.NewBufferString(`{"event":"created"}`))),
+		OptHMACSignature(key),
+	).Do()
+	assert.Nil(err)
+	defer res.Body.Close()
+	assert.Equal(http.StatusOK, res.StatusCode)
+
+	res, err = New(server.URL+"/webhooks",
+		OptPost(),
+		OptBody(ioutil.NopCloser(bytes.NewBufferString(`{"event":"created"}`))),
+		OptHMACSignature([]byte("wrong-key")),
+	).Do()
+	assert.Nil(err)
+	defer res.Body.Close()
+	assert.Equal(http.StatusUnauthorized, res.StatusCode)
+	assert.Equal(2, attempts)
+}
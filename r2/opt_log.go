@@ -16,7 +16,8 @@ const (
 func OptLog(log logger.Log) Option {
 	return OptOnRequest(func(req *http.Request) error {
 		event := NewEvent(Flag,
-			OptEventRequest(req))
+			OptEventRequest(req),
+			OptEventAttempt(GetAttempt(req.Context())))
 		log.Trigger(req.Context(), event)
 		return nil
 	})
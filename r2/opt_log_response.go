@@ -19,7 +19,8 @@ func OptLogResponse(log logger.Log) Option {
 		event := NewEvent(FlagResponse,
 			OptEventStarted(started),
 			OptEventRequest(req),
-			OptEventResponse(res))
+			OptEventResponse(res),
+			OptEventAttempt(GetAttempt(req.Context())))
 
 		log.Trigger(req.Context(), event)
 		return nil
@@ -48,7 +49,8 @@ func OptLogResponseWithBody(log logger.Log) Option {
 			OptEventStarted(started),
 			OptEventRequest(req),
 			OptEventResponse(res),
-			OptEventBody(buffer.Bytes()))
+			OptEventBody(buffer.Bytes()),
+			OptEventAttempt(GetAttempt(req.Context())))
 
 		log.Trigger(req.Context(), event)
 		return nil
@@ -0,0 +1,172 @@
+package r2
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/blend/go-sdk/ex"
+)
+
+// OptMultipart sets the request body to a multipart/form-data body made up of the given parts.
+/*
+The body is streamed as it is sent, so file parts are not buffered in memory; because of this
+the request is sent with chunked transfer encoding. Note that setting a retry policy will buffer
+the body in full so it can be replayed.
+
+	r2.New(uploadURL,
+		r2.OptPost(),
+		r2.OptMultipart(
+			r2.MultipartField("name", "report"),
+			r2.MultipartFileFromPath("file", "/tmp/report.csv"),
+		),
+	)
+*/
+func OptMultipart(parts ...MultipartPart) Option {
+	return func(r *Request) error {
+		body := newMultipartBody(parts)
+		if r.Header == nil {
+			r.Header = make(http.Header)
+		}
+		r.Header.Set(HeaderContentType, body.writer.FormDataContentType())
+		r.Body = body
+		r.ContentLength = -1
+		return nil
+	}
+}
+
+// MultipartField returns a multipart form field part.
+func MultipartField(name, value string) MultipartPart {
+	return MultipartPart{
+		FieldName: name,
+		Open: func() (io.ReadCloser, error) {
+			return ioutil.NopCloser(strings.NewReader(value)), nil
+		},
+	}
+}
+
+// MultipartFile returns a multipart file part that reads from a given reader.
+// If the reader is also an io.Closer, it is closed once it has been written.
+func MultipartFile(fieldName, fileName string, contents io.Reader) MultipartPart {
+	return MultipartPart{
+		FieldName: fieldName,
+		FileName:  fileName,
+		Open: func() (io.ReadCloser, error) {
+			if typed, ok := contents.(io.ReadCloser); ok {
+				return typed, nil
+			}
+			return ioutil.NopCloser(contents), nil
+		},
+	}
+}
+
+// MultipartFileFromPath returns a multipart file part that reads a file from disk.
+// The file is opened when the part is written.
+func MultipartFileFromPath(fieldName, path string) MultipartPart {
+	return MultipartPart{
+		FieldName: fieldName,
+		FileName:  filepath.Base(path),
+		Open: func() (io.ReadCloser, error) {
+			return os.Open(path)
+		},
+	}
+}
+
+// MultipartPart is a part of a multipart body.
+type MultipartPart struct {
+	// FieldName is the form field name of the part.
+	FieldName string
+	// FileName is the file name of the part; if it is set the part is a file.
+	FileName string
+	// ContentType is the content type of a file part.
+	// It defaults to `application/octet-stream`.
+	ContentType string
+	// Open returns the contents of the part.
+	Open func() (io.ReadCloser, error)
+}
+
+// writeTo writes the part to a multipart writer.
+func (mp MultipartPart) writeTo(mw *multipart.Writer) error {
+	header := make(textproto.MIMEHeader)
+	if mp.FileName != "" {
+		header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`, escapeQuotes(mp.FieldName), escapeQuotes(mp.FileName)))
+		contentType := mp.ContentType
+		if contentType == "" {
+			contentType = ContentTypeApplicationOctetStream
+		}
+		header.Set(HeaderContentType, contentType)
+	} else {
+		header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"`, escapeQuotes(mp.FieldName)))
+	}
+	partWriter, err := mw.CreatePart(header)
+	if err != nil {
+		return ex.New(err)
+	}
+	contents, err := mp.Open()
+	if err != nil {
+		return ex.New(err)
+	}
+	defer contents.Close()
+	if _, err := io.Copy(partWriter, contents); err != nil {
+		return ex.New(err)
+	}
+	return nil
+}
+
+// newMultipartBody returns a new multipart body.
+// The parts are written to a pipe by a goroutine that starts on the first read.
+func newMultipartBody(parts []MultipartPart) *multipartBody {
+	reader, writer := io.Pipe()
+	return &multipartBody{
+		parts:  parts,
+		reader: reader,
+		pipe:   writer,
+		writer: multipart.NewWriter(writer),
+	}
+}
+
+type multipartBody struct {
+	parts  []MultipartPart
+	reader *io.PipeReader
+	pipe   *io.PipeWriter
+	writer *multipart.Writer
+	start  sync.Once
+}
+
+// Read implements io.Reader.
+func (mb *multipartBody) Read(p []byte) (int, error) {
+	mb.start.Do(func() {
+		go func() {
+			mb.pipe.CloseWithError(mb.write())
+		}()
+	})
+	return mb.reader.Read(p)
+}
+
+// Close implements io.Closer.
+// If the body is closed before it is fully read, the writing goroutine is stopped.
+func (mb *multipartBody) Close() error {
+	return mb.reader.Close()
+}
+
+func (mb *multipartBody) write() error {
+	for _, part := range mb.parts {
+		if err := part.writeTo(mb.writer); err != nil {
+			return err
+		}
+	}
+	return ex.New(mb.writer.Close())
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+func escapeQuotes(s string) string {
+	return quoteEscaper.Replace(s)
+}
@@ -0,0 +1,107 @@
+package r2
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/ex"
+)
+
+func TestOptMultipart(t *testing.T) {
+	assert := assert.New(t)
+
+	tempDir, err := ioutil.TempDir("", "r2_multipart")
+	assert.Nil(err)
+	defer os.RemoveAll(tempDir)
+	filePath := filepath.Join(tempDir, "report.csv")
+	assert.Nil(ioutil.WriteFile(filePath, []byte("a,b,c\n1,2,3\n"), 0644))
+
+	var contentLength int64
+	var transferEncoding []string
+	var name, file, stream, streamContentType string
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		contentLength = r.ContentLength
+		transferEncoding = r.TransferEncoding
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+		name = r.FormValue("name")
+
+		f, header, err := r.FormFile("file")
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+		defer f.Close()
+		contents, _ := ioutil.ReadAll(f)
+		file = header.Filename + ":" + string(contents)
+
+		s, header, err := r.FormFile("stream")
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+		defer s.Close()
+		contents, _ = ioutil.ReadAll(s)
+		stream = header.Filename + ":" + string(contents)
+		streamContentType = header.Header.Get(HeaderContentType)
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	streamPart := MultipartFile("stream", "data.txt", strings.NewReader("streamed contents"))
+	streamPart.ContentType = "text/plain"
+	res, err := New(server.URL,
+		OptPost(),
+		OptMultipart(
+			MultipartField("name", "report"),
+			MultipartFileFromPath("file", filePath),
+			streamPart,
+		),
+	).Do()
+	assert.Nil(err)
+	defer res.Body.Close()
+	assert.Equal(http.StatusOK, res.StatusCode)
+
+	assert.Equal(-1, contentLength)
+	assert.Equal([]string{"chunked"}, transferEncoding)
+	assert.Equal("report", name)
+	assert.Equal("report.csv:a,b,c\n1,2,3\n", file)
+	assert.Equal("data.txt:streamed contents", stream)
+	assert.Equal("text/plain", streamContentType)
+}
+
+func TestOptMultipartOpenError(t *testing.T) {
+	assert := assert.New(t)
+
+	r := New("http://localhost/upload", OptMultipart(MultipartFileFromPath("file", "/not/a/real/file")))
+	assert.Nil(r.Err)
+	assert.True(strings.HasPrefix(r.Request.Header.Get(HeaderContentType), "multipart/form-data; boundary="))
+
+	_, err := ioutil.ReadAll(r.Request.Body)
+	assert.NotNil(err)
+	assert.True(os.IsNotExist(ex.ErrClass(err)))
+}
+
+func TestOptMultipartClose(t *testing.T) {
+	assert := assert.New(t)
+
+	reader, writer := io.Pipe()
+	defer writer.Close()
+	r := New("http://localhost/upload", OptMultipart(MultipartFile("file", "file.txt", reader)))
+
+	buf := make([]byte, 8)
+	_, err := r.Request.Body.Read(buf)
+	assert.Nil(err)
+	assert.Nil(r.Request.Body.Close())
+	_, err = r.Request.Body.Read(buf)
+	assert.Equal(io.ErrClosedPipe, err)
+}
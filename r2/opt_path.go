@@ -26,3 +26,22 @@ func OptPathf(format string, args ...interface{}) Option {
 		return nil
 	}
 }
+
+// OptEscapedPath sets the url path from an escaped path.
+//
+// Use it instead of `OptPath` for paths with escaped segments, e.g. values escaped with `url.PathEscape`,
+// so escaped slashes are sent as is and the path is not escaped twice.
+func OptEscapedPath(escaped string) Option {
+	return func(r *Request) error {
+		if r.URL == nil {
+			r.URL = &url.URL{}
+		}
+		path, err := url.PathUnescape(escaped)
+		if err != nil {
+			return err
+		}
+		r.URL.Path = path
+		r.URL.RawPath = escaped
+		return nil
+	}
+}
@@ -27,3 +27,19 @@ func TestOptPathf(t *testing.T) {
 	OptPathf("/not-foo/%s", "bar")(&unset)
 	assert.Equal("/not-foo/bar", unset.URL.Path)
 }
+
+func TestOptEscapedPath(t *testing.T) {
+	assert := assert.New(t)
+
+	r := New("http://foo.com", OptEscapedPath("/pets/a%20b%2Fc"))
+	assert.Nil(r.Err)
+	assert.Equal("/pets/a b/c", r.Request.URL.Path)
+	assert.Equal("/pets/a%20b%2Fc", r.Request.URL.EscapedPath())
+	assert.Equal("http://foo.com/pets/a%20b%2Fc", r.Request.URL.String())
+
+	var unset Request
+	assert.Nil(OptEscapedPath("/not-foo")(&unset))
+	assert.Equal("/not-foo", unset.URL.Path)
+
+	assert.NotNil(OptEscapedPath("/not-valid%zz")(&unset))
+}
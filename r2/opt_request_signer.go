@@ -0,0 +1,57 @@
+package r2
+
+import (
+	"net/http"
+
+	"github.com/blend/go-sdk/ex"
+)
+
+// RequestSigner signs a request given its body, typically by setting headers.
+// The request is a copy that is safe to modify.
+type RequestSigner func(req *http.Request, body []byte) error
+
+// OptRequestSigner signs requests as they are sent, so each attempt of a retried request
+// is signed with a fresh timestamp.
+//
+// It wraps the client transport, and so should be applied after any transport options.
+// Request bodies are buffered so they can be signed.
+func OptRequestSigner(signer RequestSigner) Option {
+	return func(r *Request) error {
+		var client http.Client
+		if r.Client != nil {
+			client = *r.Client
+		}
+		client.Transport = &signingTransport{
+			Signer:    signer,
+			Transport: client.Transport,
+		}
+		r.Client = &client
+		return nil
+	}
+}
+
+// signingTransport signs requests before sending them with an inner transport.
+type signingTransport struct {
+	Signer    RequestSigner
+	Transport http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (st *signingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	cloned, body, err := cloneRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	signed := cloned.WithContext(cloned.Context())
+	signed.Header = cloneHeader(req.Header)
+	if body != nil {
+		signed.ContentLength = int64(len(body))
+	}
+	if err := st.Signer(signed, body); err != nil {
+		return nil, ex.New(err)
+	}
+	if st.Transport != nil {
+		return st.Transport.RoundTrip(signed)
+	}
+	return http.DefaultTransport.RoundTrip(signed)
+}
@@ -0,0 +1,10 @@
+package r2
+
+// OptRetry sets the retry policy for the request.
+// Request bodies are buffered in memory so they can be replayed for each attempt.
+func OptRetry(policy RetryPolicy) Option {
+	return func(r *Request) error {
+		r.Retry = &policy
+		return nil
+	}
+}
@@ -0,0 +1,15 @@
+package r2
+
+import (
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestOptRetry(t *testing.T) {
+	assert := assert.New(t)
+
+	r := New("http://foo.com", OptRetry(RetryPolicy{MaxAttempts: 5}))
+	assert.NotNil(r.Retry)
+	assert.Equal(5, r.Retry.MaxAttempts)
+}
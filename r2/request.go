@@ -54,6 +54,12 @@ type Request struct {
 	OnRequest []OnRequestListener
 	// OnResponse is an array of response lifecycle hooks used for logging.
 	OnResponse []OnResponseListener
+	// Retry is an optional retry policy.
+	// If it is set, the tracer and listeners are called for each attempt.
+	Retry *RetryPolicy
+	// CircuitBreaker is an optional circuit breaker.
+	// If it is set, attempts to hosts with open circuits fail fast with `ErrCircuitOpen`.
+	CircuitBreaker *CircuitBreaker
 }
 
 // Do executes the request.
@@ -69,38 +75,67 @@ func (r Request) Do() (*http.Response, error) {
 		}
 	}
 
-	var err error
+	if r.Retry != nil {
+		return r.doWithRetry()
+	}
+	res, _, err := r.send(&r.Request, nil)
+	return res, err
+}
+
+// send makes a single attempt of the request, calling the tracer and listeners.
+// If `canRetry` returns true for the attempt, listener errors that pass through the
+// attempt error are ignored, and the caller is expected to retry the request.
+func (r Request) send(req *http.Request, canRetry func(*http.Response, error) bool) (res *http.Response, retry bool, err error) {
 	started := time.Now().UTC()
 
 	var finisher TraceFinisher
 	if r.Tracer != nil {
-		finisher = r.Tracer.Start(&r.Request)
+		finisher = r.Tracer.Start(req)
 	}
 
 	for _, listener := range r.OnRequest {
-		if err = listener(&r.Request); err != nil {
-			return nil, err
+		if err = listener(req); err != nil {
+			return nil, false, err
 		}
 	}
 
-	var res *http.Response
-	if r.Client != nil {
-		res, err = r.Client.Do(&r.Request)
-	} else {
-		res, err = http.DefaultClient.Do(&r.Request)
+	var generation uint64
+	if r.CircuitBreaker != nil {
+		generation, err = r.CircuitBreaker.Allow(req.Context(), req.URL.Host)
+	}
+	if err == nil {
+		if r.Client != nil {
+			res, err = r.Client.Do(req)
+		} else {
+			res, err = http.DefaultClient.Do(req)
+		}
+		if r.CircuitBreaker != nil {
+			r.CircuitBreaker.Record(req.Context(), req.URL.Host, generation, res, err)
+		}
 	}
 	if finisher != nil {
-		finisher.Finish(&r.Request, res, started, err)
+		finisher.Finish(req, res, started, err)
 	}
+	retry = canRetry != nil && canRetry(res, err)
 	for _, listener := range r.OnResponse {
-		if err = listener(&r.Request, res, started, err); err != nil {
-			return nil, err
+		listenerErr := listener(req, res, started, err)
+		if retry {
+			if listenerErr != nil && listenerErr != err {
+				return nil, false, listenerErr
+			}
+			continue
+		}
+		if err = listenerErr; err != nil {
+			return nil, false, err
 		}
 	}
+	if retry {
+		return res, true, err
+	}
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
-	return res, nil
+	return res, false, nil
 }
 
 // Close closes the request if there is a closer specified.
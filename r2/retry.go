@@ -0,0 +1,65 @@
+package r2
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/blend/go-sdk/ex"
+)
+
+// doWithRetry sends the request, retrying failed attempts according to the retry policy.
+func (r Request) doWithRetry() (*http.Response, error) {
+	policy := *r.Retry
+	maxAttempts := policy.MaxAttemptsOrDefault()
+
+	// buffer the body so it can be replayed for each attempt.
+	var body []byte
+	if r.Request.Body != nil && r.Request.Body != http.NoBody {
+		var err error
+		body, err = ioutil.ReadAll(r.Request.Body)
+		r.Request.Body.Close()
+		if err != nil {
+			return nil, ex.New(err)
+		}
+	}
+
+	ctx := r.Request.Context()
+	for attempt := 1; ; attempt++ {
+		req := r.Request.WithContext(WithAttempt(ctx, attempt))
+		if body != nil {
+			req.Body = ioutil.NopCloser(bytes.NewReader(body))
+			req.GetBody = func() (io.ReadCloser, error) {
+				return ioutil.NopCloser(bytes.NewReader(body)), nil
+			}
+		}
+
+		var backoff time.Duration
+		canRetry := func(res *http.Response, err error) (ok bool) {
+			if attempt >= maxAttempts || !policy.Retryable(req, res, err) {
+				return false
+			}
+			backoff, ok = policy.Backoff(attempt, res)
+			return
+		}
+
+		res, retry, err := r.send(req, canRetry)
+		if !retry {
+			return res, err
+		}
+		if res != nil {
+			io.Copy(ioutil.Discard, res.Body)
+			res.Body.Close()
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ex.New(ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package r2
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Retry policy defaults.
+const (
+	// DefaultRetryMaxAttempts is the default maximum number of attempts, including the first.
+	DefaultRetryMaxAttempts = 3
+	// DefaultRetryInitialBackoff is the default backoff before the first retry.
+	DefaultRetryInitialBackoff = 100 * time.Millisecond
+	// DefaultRetryMaxBackoff is the default maximum backoff between attempts.
+	DefaultRetryMaxBackoff = 10 * time.Second
+	// DefaultRetryMultiplier is the default backoff multiplier.
+	DefaultRetryMultiplier = 2.0
+)
+
+// RetryPolicy governs how requests are retried.
+//
+// By default, requests are retried on connection errors, 429s and 5xx responses (except 501s),
+// and only if the request method is idempotent. Requests rejected by a circuit breaker are not retried.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts, including the first attempt.
+	MaxAttempts int
+	// InitialBackoff is the backoff before the first retry.
+	InitialBackoff time.Duration
+	// MaxBackoff is the maximum backoff between attempts.
+	// If a `Retry-After` header asks for a longer delay, the request is not retried.
+	MaxBackoff time.Duration
+	// Multiplier is the factor the backoff is multiplied by after each attempt.
+	Multiplier float64
+	// DisableJitter disables randomizing the backoff.
+	DisableJitter bool
+	// RetryNonIdempotent allows requests with non-idempotent methods (e.g. POST) to be retried.
+	RetryNonIdempotent bool
+	// ShouldRetry optionally overrides which responses and errors are retried.
+	ShouldRetry func(*http.Response, error) bool
+}
+
+// MaxAttemptsOrDefault returns the max attempts or a default.
+func (rp RetryPolicy) MaxAttemptsOrDefault() int {
+	if rp.MaxAttempts > 0 {
+		return rp.MaxAttempts
+	}
+	return DefaultRetryMaxAttempts
+}
+
+// InitialBackoffOrDefault returns the initial backoff or a default.
+func (rp RetryPolicy) InitialBackoffOrDefault() time.Duration {
+	if rp.InitialBackoff > 0 {
+		return rp.InitialBackoff
+	}
+	return DefaultRetryInitialBackoff
+}
+
+// MaxBackoffOrDefault returns the max backoff or a default.
+func (rp RetryPolicy) MaxBackoffOrDefault() time.Duration {
+	if rp.MaxBackoff > 0 {
+		return rp.MaxBackoff
+	}
+	return DefaultRetryMaxBackoff
+}
+
+// MultiplierOrDefault returns the multiplier or a default.
+func (rp RetryPolicy) MultiplierOrDefault() float64 {
+	if rp.Multiplier > 0 {
+		return rp.Multiplier
+	}
+	return DefaultRetryMultiplier
+}
+
+// Retryable returns if a given attempt can be retried.
+func (rp RetryPolicy) Retryable(req *http.Request, res *http.Response, err error) bool {
+	if !rp.RetryNonIdempotent && !IsIdempotent(req.Method) {
+		return false
+	}
+	if err != nil && (req.Context().Err() != nil || IsErrCircuitOpen(err)) {
+		return false
+	}
+	if rp.ShouldRetry != nil {
+		return rp.ShouldRetry(res, err)
+	}
+	if err != nil {
+		return true
+	}
+	if res == nil {
+		return false
+	}
+	return res.StatusCode == http.StatusTooManyRequests ||
+		(res.StatusCode >= http.StatusInternalServerError && res.StatusCode != http.StatusNotImplemented)
+}
+
+// Backoff returns the delay before the next attempt, given the attempt that just finished.
+// It returns false if the response asks the client to wait longer than the max backoff.
+func (rp RetryPolicy) Backoff(attempt int, res *http.Response) (time.Duration, bool) {
+	maxBackoff := rp.MaxBackoffOrDefault()
+	backoff := time.Duration(float64(rp.InitialBackoffOrDefault()) * math.Pow(rp.MultiplierOrDefault(), float64(attempt-1)))
+	if backoff > maxBackoff || backoff <= 0 {
+		backoff = maxBackoff
+	}
+	if !rp.DisableJitter {
+		backoff = backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+	}
+	if retryAfter, ok := ParseRetryAfter(res); ok {
+		if retryAfter > maxBackoff {
+			return 0, false
+		}
+		if retryAfter > backoff {
+			backoff = retryAfter
+		}
+	}
+	return backoff, true
+}
+
+// IsIdempotent returns if a method is idempotent, and can be safely retried.
+func IsIdempotent(method string) bool {
+	switch method {
+	case "", MethodGet, MethodHead, MethodOptions, MethodPut, MethodDelete, MethodTrace:
+		return true
+	default:
+		return false
+	}
+}
+
+// ParseRetryAfter parses the `Retry-After` header of a response, which can be
+// a number of seconds or an http date.
+func ParseRetryAfter(res *http.Response) (time.Duration, bool) {
+	if res == nil {
+		return 0, false
+	}
+	value := res.Header.Get(HeaderRetryAfter)
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if delay := time.Until(date); delay > 0 {
+			return delay, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+type attemptKey struct{}
+
+// WithAttempt adds the attempt number of a retried request to a context.
+func WithAttempt(ctx context.Context, attempt int) context.Context {
+	return context.WithValue(ctx, attemptKey{}, attempt)
+}
+
+// GetAttempt returns the attempt number of a retried request from a context.
+// It returns 0 if the request was not sent with a retry policy.
+func GetAttempt(ctx context.Context) int {
+	if ctx == nil {
+		return 0
+	}
+	if value, ok := ctx.Value(attemptKey{}).(int); ok {
+		return value
+	}
+	return 0
+}
@@ -0,0 +1,250 @@
+package r2
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/logger"
+)
+
+func TestRetryPolicyDefaults(t *testing.T) {
+	assert := assert.New(t)
+
+	var rp RetryPolicy
+	assert.Equal(DefaultRetryMaxAttempts, rp.MaxAttemptsOrDefault())
+	assert.Equal(DefaultRetryInitialBackoff, rp.InitialBackoffOrDefault())
+	assert.Equal(DefaultRetryMaxBackoff, rp.MaxBackoffOrDefault())
+	assert.Equal(DefaultRetryMultiplier, rp.MultiplierOrDefault())
+}
+
+func TestRetryPolicyRetryable(t *testing.T) {
+	assert := assert.New(t)
+
+	var rp RetryPolicy
+	get, _ := http.NewRequest(MethodGet, "http://foo.com", nil)
+	post, _ := http.NewRequest(MethodPost, "http://foo.com", nil)
+
+	assert.True(rp.Retryable(get, nil, fmt.Errorf("connection refused")))
+	assert.True(rp.Retryable(get, &http.Response{StatusCode: http.StatusServiceUnavailable}, nil))
+	assert.True(rp.Retryable(get, &http.Response{StatusCode: http.StatusTooManyRequests}, nil))
+	assert.False(rp.Retryable(get, &http.Response{StatusCode: http.StatusNotImplemented}, nil))
+	assert.False(rp.Retryable(get, &http.Response{StatusCode: http.StatusBadRequest}, nil))
+	assert.False(rp.Retryable(get, &http.Response{StatusCode: http.StatusOK}, nil))
+
+	assert.False(rp.Retryable(post, &http.Response{StatusCode: http.StatusServiceUnavailable}, nil))
+	rp.RetryNonIdempotent = true
+	assert.True(rp.Retryable(post, &http.Response{StatusCode: http.StatusServiceUnavailable}, nil))
+
+	rp.ShouldRetry = func(res *http.Response, _ error) bool { return res.StatusCode == http.StatusConflict }
+	assert.True(rp.Retryable(post, &http.Response{StatusCode: http.StatusConflict}, nil))
+	assert.False(rp.Retryable(post, &http.Response{StatusCode: http.StatusServiceUnavailable}, nil))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.False(RetryPolicy{}.Retryable(get.WithContext(ctx), nil, context.Canceled))
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	assert := assert.New(t)
+
+	rp := RetryPolicy{
+		InitialBackoff: time.Second,
+		MaxBackoff:     5 * time.Second,
+		DisableJitter:  true,
+	}
+	backoff, ok := rp.Backoff(1, nil)
+	assert.True(ok)
+	assert.Equal(time.Second, backoff)
+	backoff, _ = rp.Backoff(2, nil)
+	assert.Equal(2*time.Second, backoff)
+	backoff, _ = rp.Backoff(10, nil)
+	assert.Equal(5*time.Second, backoff)
+
+	res := &http.Response{Header: http.Header{HeaderRetryAfter: []string{"3"}}}
+	backoff, ok = rp.Backoff(1, res)
+	assert.True(ok)
+	assert.Equal(3*time.Second, backoff)
+
+	res.Header.Set(HeaderRetryAfter, "30")
+	_, ok = rp.Backoff(1, res)
+	assert.False(ok)
+
+	rp.DisableJitter = false
+	for x := 0; x < 10; x++ {
+		backoff, _ = rp.Backoff(2, nil)
+		assert.True(backoff >= time.Second && backoff <= 2*time.Second)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	assert := assert.New(t)
+
+	_, ok := ParseRetryAfter(nil)
+	assert.False(ok)
+
+	res := &http.Response{Header: http.Header{}}
+	_, ok = ParseRetryAfter(res)
+	assert.False(ok)
+
+	res.Header.Set(HeaderRetryAfter, "120")
+	delay, ok := ParseRetryAfter(res)
+	assert.True(ok)
+	assert.Equal(2*time.Minute, delay)
+
+	res.Header.Set(HeaderRetryAfter, time.Now().UTC().Add(time.Hour).Format(http.TimeFormat))
+	delay, ok = ParseRetryAfter(res)
+	assert.True(ok)
+	assert.True(delay > 59*time.Minute)
+
+	res.Header.Set(HeaderRetryAfter, "not a date")
+	_, ok = ParseRetryAfter(res)
+	assert.False(ok)
+}
+
+func TestIsIdempotent(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True(IsIdempotent(MethodGet))
+	assert.True(IsIdempotent(MethodPut))
+	assert.True(IsIdempotent(MethodDelete))
+	assert.False(IsIdempotent(MethodPost))
+	assert.False(IsIdempotent(MethodPatch))
+}
+
+func mockServerFailures(failures int32, statusCode int) (*httptest.Server, *int32) {
+	var calls int32
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if atomic.AddInt32(&calls, 1) <= failures {
+			w.WriteHeader(statusCode)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write(body)
+	})), &calls
+}
+
+func TestRequestRetry(t *testing.T) {
+	assert := assert.New(t)
+
+	server, calls := mockServerFailures(2, http.StatusServiceUnavailable)
+	defer server.Close()
+
+	var attempts []int
+	var traced int32
+	contents, res, err := New(server.URL,
+		OptPut(),
+		OptBody(ioutil.NopCloser(bytes.NewBufferString("hello"))),
+		OptRetry(RetryPolicy{InitialBackoff: time.Millisecond}),
+		OptTracer(MockTracer{StartHandler: func(_ *http.Request) { atomic.AddInt32(&traced, 1) }}),
+		OptOnRequest(func(req *http.Request) error {
+			attempts = append(attempts, GetAttempt(req.Context()))
+			return nil
+		}),
+	).BytesWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, res.StatusCode)
+	assert.Equal("hello", string(contents))
+	assert.Equal(3, atomic.LoadInt32(calls))
+	assert.Equal(3, atomic.LoadInt32(&traced))
+	assert.Equal([]int{1, 2, 3}, attempts)
+}
+
+func TestRequestRetryExhausted(t *testing.T) {
+	assert := assert.New(t)
+
+	server, calls := mockServerFailures(5, http.StatusBadGateway)
+	defer server.Close()
+
+	res, err := New(server.URL,
+		OptRetry(RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond}),
+	).DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusBadGateway, res.StatusCode)
+	assert.Equal(2, atomic.LoadInt32(calls))
+}
+
+func TestRequestRetryNonIdempotent(t *testing.T) {
+	assert := assert.New(t)
+
+	server, calls := mockServerFailures(1, http.StatusServiceUnavailable)
+	defer server.Close()
+
+	res, err := New(server.URL,
+		OptPost(),
+		OptRetry(RetryPolicy{InitialBackoff: time.Millisecond}),
+	).DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusServiceUnavailable, res.StatusCode)
+	assert.Equal(1, atomic.LoadInt32(calls))
+}
+
+func TestRequestRetryConnectionError(t *testing.T) {
+	assert := assert.New(t)
+
+	server := mockServerOK()
+	url := server.URL
+	server.Close()
+
+	var attempts int32
+	_, err := New(url,
+		OptRetry(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}),
+		OptOnResponse(func(_ *http.Request, _ *http.Response, _ time.Time, err error) error {
+			atomic.AddInt32(&attempts, 1)
+			return err
+		}),
+	).Do()
+	assert.NotNil(err)
+	assert.Equal(3, atomic.LoadInt32(&attempts))
+}
+
+func TestRequestRetryContextCanceled(t *testing.T) {
+	assert := assert.New(t)
+
+	server, calls := mockServerFailures(5, http.StatusServiceUnavailable)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := New(server.URL,
+		OptContext(ctx),
+		OptRetry(RetryPolicy{InitialBackoff: time.Hour, MaxBackoff: time.Hour}),
+	).Do()
+	assert.NotNil(err)
+	assert.Equal(1, atomic.LoadInt32(calls))
+}
+
+func TestRequestRetryLogEvents(t *testing.T) {
+	assert := assert.New(t)
+
+	server, _ := mockServerFailures(1, http.StatusServiceUnavailable)
+	defer server.Close()
+
+	log, err := logger.New(logger.OptOutput(new(bytes.Buffer)), logger.OptAll())
+	assert.Nil(err)
+	defer log.Close()
+
+	events := make(chan *Event, 2)
+	log.Listen(FlagResponse, "test", func(_ context.Context, e logger.Event) {
+		events <- e.(*Event)
+	})
+
+	assert.Nil(New(server.URL,
+		OptRetry(RetryPolicy{InitialBackoff: time.Millisecond}),
+		OptLogResponse(log),
+	).Discard())
+
+	first, second := <-events, <-events
+	assert.Equal(1, first.Attempt)
+	assert.Equal(http.StatusServiceUnavailable, first.Response.StatusCode)
+	assert.Equal(2, second.Attempt)
+	assert.Equal(http.StatusOK, second.Response.StatusCode)
+}
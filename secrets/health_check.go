@@ -0,0 +1,17 @@
+package secrets
+
+import "context"
+
+// HealthCheck returns a health check func that reads a key from a secrets store.
+// It can be passed to `web.NewHealthCheck` without the web package importing secrets.
+/*
+Usage:
+
+	web.NewHealthCheck("vault", secrets.HealthCheck(client, "ping"), web.OptHealthCheckCritical(false))
+*/
+func HealthCheck(client KV, key string) func(context.Context) error {
+	return func(ctx context.Context) error {
+		_, err := client.Get(ctx, key)
+		return err
+	}
+}
@@ -0,0 +1,18 @@
+package secrets
+
+import (
+	"context"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestHealthCheck(t *testing.T) {
+	assert := assert.New(t)
+
+	client := NewMockClient()
+	assert.Nil(client.Put(context.TODO(), "ping", Values{"ok": "true"}))
+
+	assert.Nil(HealthCheck(client, "ping")(context.TODO()))
+	assert.NotNil(HealthCheck(client, "not-ping")(context.TODO()))
+}
@@ -24,6 +24,8 @@ const (
 	TagKeyHTTPCode = "http.status_code"
 	// TagKeyHTTPURL is the url of the request (typically the raw path).
 	TagKeyHTTPURL = "http.url"
+	// TagKeyHTTPRetryAttempt is the attempt number of a retried request.
+	TagKeyHTTPRetryAttempt = "http.retry_attempt"
 	// TagKeyDBApplication is the application that uses a database.
 	TagKeyDBApplication = "db.application"
 	// TagKeyDBName is the database name.
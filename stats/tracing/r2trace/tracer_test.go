@@ -115,3 +115,15 @@ func TestFinishNilSpan(t *testing.T) {
 	rtf.Finish(nil, nil, time.Now(), nil)
 	assert.Nil(rtf.span)
 }
+
+func TestStartRetryAttempt(t *testing.T) {
+	assert := assert.New(t)
+	mockTracer := mocktracer.New()
+	reqTracer := Tracer(mockTracer)
+
+	req := r2.New("https://foo.com/bar", r2.OptContext(r2.WithAttempt(context.Background(), 2)))
+	rtf := reqTracer.Start(&req.Request)
+	mockSpan := rtf.(r2TraceFinisher).span.(*mocktracer.MockSpan)
+	assert.Len(mockSpan.Tags(), 5)
+	assert.Equal(2, mockSpan.Tags()[tracing.TagKeyHTTPRetryAttempt])
+}
@@ -55,6 +55,7 @@ type App struct {
 	DefaultHeaders          http.Header
 	Statics                 map[string]*StaticFileServer
 	Routes                  map[string]*RouteNode
+	Groups                  []*Group
 	NotFoundHandler         Handler
 	MethodNotAllowedHandler Handler
 	PanicAction             PanicAction
@@ -366,8 +367,8 @@ func (a *App) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 		if a.Config.HandleMethodNotAllowed {
 			if allow := a.allowed(path, req.Method); len(allow) > 0 {
 				w.Header().Set(HeaderAllow, allow)
-				if a.MethodNotAllowedHandler != nil {
-					a.MethodNotAllowedHandler(w, req, nil, nil)
+				if handler := a.methodNotAllowedHandler(path); handler != nil {
+					handler(w, req, nil, nil)
 				} else {
 					http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
 				}
@@ -377,8 +378,8 @@ func (a *App) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	}
 
 	// Handle 404
-	if a.NotFoundHandler != nil {
-		a.NotFoundHandler(w, req, nil, nil)
+	if handler := a.notFoundHandler(path); handler != nil {
+		handler(w, req, nil, nil)
 	} else {
 		http.NotFound(w, req)
 	}
@@ -410,7 +411,14 @@ func (a *App) RenderAction(action Action) Handler {
 			// check for a prerender step
 			if typed, ok := result.(ResultPreRender); ok {
 				if preRenderErr := typed.PreRender(ctx); preRenderErr != nil {
-					err = ex.Nest(err, preRenderErr)
+					// bodies sent chunked or compressed can exceed the limit once they are read.
+					if IsErrRequestBodyTooLarge(preRenderErr) {
+						result = requestEntityTooLarge(ctx)
+					} else if IsErrUnsupportedContentEncoding(preRenderErr) {
+						result = unsupportedMediaType(ctx)
+					} else {
+						err = ex.Nest(err, preRenderErr)
+					}
 				}
 			}
 
@@ -445,7 +453,9 @@ func (a *App) RenderAction(action Action) Handler {
 }
 
 // NestMiddleware wraps an action with a given set of middleware, including app level default middleware.
+// The declared request body size is checked after the middleware runs, so routes can set their own limit with `MaxBodyBytes`.
 func (a *App) NestMiddleware(action Action, middleware ...Middleware) Action {
+	action = requestBodyLimited(action)
 	if len(middleware) == 0 && len(a.DefaultMiddleware) == 0 {
 		return action
 	}
@@ -479,10 +489,37 @@ func (a *App) createCtx(w ResponseWriter, r *http.Request, route *Route, p Route
 		OptCtxRouteParams(p),
 		OptCtxState(a.State.Copy()),
 		OptCtxTracer(a.Tracer),
+		OptCtxMaxBodyBytes(a.Config.MaxBodyBytesOrDefault()),
 	}
 	return NewCtx(w, r, append(options, extra...)...)
 }
 
+// notFoundHandler returns the not found handler of the most specific group
+// matching the path, falling back to the app not found handler.
+func (a *App) notFoundHandler(path string) Handler {
+	var prefix string
+	handler := a.NotFoundHandler
+	for _, group := range a.Groups {
+		if group.NotFoundHandler != nil && group.Matches(path) && len(group.Prefix) >= len(prefix) {
+			prefix, handler = group.Prefix, group.NotFoundHandler
+		}
+	}
+	return handler
+}
+
+// methodNotAllowedHandler returns the method not allowed handler of the most specific group
+// matching the path, falling back to the app method not allowed handler.
+func (a *App) methodNotAllowedHandler(path string) Handler {
+	var prefix string
+	handler := a.MethodNotAllowedHandler
+	for _, group := range a.Groups {
+		if group.MethodNotAllowedHandler != nil && group.Matches(path) && len(group.Prefix) >= len(prefix) {
+			prefix, handler = group.Prefix, group.MethodNotAllowedHandler
+		}
+	}
+	return handler
+}
+
 func (a *App) allowed(path, reqMethod string) (allow string) {
 	if path == "*" { // server-wide
 		for method := range a.Routes {
@@ -0,0 +1,87 @@
+package web
+
+import (
+	"bytes"
+	"net/http"
+)
+
+var (
+	_ ResponseWriter = (*BufferedResponseWriter)(nil)
+)
+
+// NewBufferedResponseWriter returns a new buffered response writer.
+// The header collection is shared with the inner response.
+func NewBufferedResponseWriter(w ResponseWriter) *BufferedResponseWriter {
+	return &BufferedResponseWriter{
+		innerResponse: w,
+		buffer:        new(bytes.Buffer),
+	}
+}
+
+// BufferedResponseWriter is a response writer that holds the status code
+// and body in memory until they are explicitly written to the inner response.
+type BufferedResponseWriter struct {
+	innerResponse ResponseWriter
+	statusCode    int
+	buffer        *bytes.Buffer
+}
+
+// Write writes the data to the buffer.
+func (brw *BufferedResponseWriter) Write(b []byte) (int, error) {
+	return brw.buffer.Write(b)
+}
+
+// Header accesses the response header collection.
+func (brw *BufferedResponseWriter) Header() http.Header {
+	return brw.innerResponse.Header()
+}
+
+// WriteHeader sets the buffered status code.
+func (brw *BufferedResponseWriter) WriteHeader(code int) {
+	if brw.statusCode == 0 {
+		brw.statusCode = code
+	}
+}
+
+// InnerResponse returns the backing writer.
+func (brw *BufferedResponseWriter) InnerResponse() ResponseWriter {
+	return brw.innerResponse
+}
+
+// StatusCode returns the buffered status code, defaulting to 200 if a body was written.
+func (brw *BufferedResponseWriter) StatusCode() int {
+	if brw.statusCode == 0 && brw.buffer.Len() > 0 {
+		return http.StatusOK
+	}
+	return brw.statusCode
+}
+
+// ContentLength returns the length of the buffered body.
+func (brw *BufferedResponseWriter) ContentLength() int {
+	return brw.buffer.Len()
+}
+
+// Bytes returns the buffered body.
+func (brw *BufferedResponseWriter) Bytes() []byte {
+	return brw.buffer.Bytes()
+}
+
+// Flush is a no-op; use `Commit` to write the response.
+func (brw *BufferedResponseWriter) Flush() {}
+
+// Commit writes the buffered status code and body to the inner response.
+func (brw *BufferedResponseWriter) Commit() error {
+	if statusCode := brw.StatusCode(); statusCode != 0 {
+		brw.innerResponse.WriteHeader(statusCode)
+	}
+	if brw.buffer.Len() == 0 {
+		return nil
+	}
+	_, err := brw.innerResponse.Write(brw.buffer.Bytes())
+	return err
+}
+
+// Close disposes of the response writer.
+func (brw *BufferedResponseWriter) Close() error {
+	return nil
+}
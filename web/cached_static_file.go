@@ -11,11 +11,15 @@ type CachedStaticFile struct {
 	Path     string
 	Size     int
 	ModTime  time.Time
+	ETag     string
 	Contents *bytes.Reader
 }
 
 // Render implements Result.
 func (csf CachedStaticFile) Render(ctx *Ctx) error {
+	if csf.ETag != "" {
+		ctx.Response.Header().Set(HeaderETag, csf.ETag)
+	}
 	http.ServeContent(ctx.Response, ctx.Request, csf.Path, csf.ModTime, csf.Contents)
 	return nil
 }
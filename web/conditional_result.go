@@ -0,0 +1,158 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/blend/go-sdk/fileutil"
+)
+
+// ResultWithETag returns a result that is rendered conditionally on a known etag.
+// If the etag is empty, it is computed by hashing the rendered response.
+func ResultWithETag(result Result, etag string) *ConditionalResult {
+	return &ConditionalResult{
+		Result: result,
+		ETag:   etag,
+	}
+}
+
+// ResultWithLastModified returns a result that is rendered conditionally on a known last modified time.
+func ResultWithLastModified(result Result, lastModified time.Time) *ConditionalResult {
+	return &ConditionalResult{
+		Result:       result,
+		LastModified: lastModified,
+	}
+}
+
+// ConditionalResult is a result that honors the `If-None-Match` and `If-Modified-Since`
+// request headers, rendering a 304 Not Modified if the client has a current copy.
+//
+// If neither an ETag or LastModified are provided, the inner result is buffered and
+// a strong ETag is computed from the response body.
+type ConditionalResult struct {
+	Result       Result
+	ETag         string
+	LastModified time.Time
+}
+
+// PreRender calls the inner result's PreRender step if it has one.
+func (cr ConditionalResult) PreRender(ctx *Ctx) error {
+	if typed, ok := cr.Result.(ResultPreRender); ok {
+		return typed.PreRender(ctx)
+	}
+	return nil
+}
+
+// PostRender calls the inner result's PostRender step if it has one.
+func (cr ConditionalResult) PostRender(ctx *Ctx) error {
+	if typed, ok := cr.Result.(ResultPostRender); ok {
+		return typed.PostRender(ctx)
+	}
+	return nil
+}
+
+// Render renders the result.
+func (cr ConditionalResult) Render(ctx *Ctx) error {
+	if !isConditionalMethod(ctx.Request.Method) {
+		return cr.Result.Render(ctx)
+	}
+	if cr.ETag != "" || !cr.LastModified.IsZero() {
+		if IsNotModified(ctx, cr.ETag, cr.LastModified) {
+			return NotModified.Render(ctx)
+		}
+		return cr.Result.Render(ctx)
+	}
+
+	response := ctx.Response
+	buffered := NewBufferedResponseWriter(response)
+	ctx.Response = buffered
+	err := cr.Result.Render(ctx)
+	ctx.Response = response
+	if err != nil {
+		return err
+	}
+
+	if buffered.StatusCode() == http.StatusOK {
+		etag := response.Header().Get(HeaderETag)
+		if etag == "" {
+			if etag, err = fileutil.ETag(buffered.Bytes()); err != nil {
+				return err
+			}
+		}
+		if IsNotModified(ctx, etag, time.Time{}) {
+			return NotModified.Render(ctx)
+		}
+	}
+	return buffered.Commit()
+}
+
+// IsNotModified sets the validator headers on the response and returns if
+// the request preconditions indicate the client already has a current copy.
+// Handlers that know the version of a resource can use it to skip work entirely.
+/*
+Usage:
+
+	if web.IsNotModified(ctx, item.Version, item.UpdatedUTC) {
+		return web.NotModified
+	}
+*/
+func IsNotModified(ctx *Ctx, etag string, lastModified time.Time) bool {
+	if etag != "" {
+		etag = FormatETag(etag)
+		ctx.Response.Header().Set(HeaderETag, etag)
+	}
+	if !lastModified.IsZero() {
+		ctx.Response.Header().Set(HeaderLastModified, lastModified.UTC().Format(http.TimeFormat))
+	}
+	if !isConditionalMethod(ctx.Request.Method) {
+		return false
+	}
+
+	if ifNoneMatch := ctx.Request.Header.Get(HeaderIfNoneMatch); ifNoneMatch != "" {
+		return etag != "" && etagMatches(ifNoneMatch, etag)
+	}
+	if ifModifiedSince := ctx.Request.Header.Get(HeaderIfModifiedSince); ifModifiedSince != "" && !lastModified.IsZero() {
+		since, err := http.ParseTime(ifModifiedSince)
+		if err != nil {
+			return false
+		}
+		return !lastModified.Truncate(time.Second).After(since)
+	}
+	return false
+}
+
+// FormatETag quotes an etag value if it is not already quoted.
+func FormatETag(etag string) string {
+	if strings.HasPrefix(etag, `"`) || strings.HasPrefix(etag, `W/"`) {
+		return etag
+	}
+	return `"` + etag + `"`
+}
+
+// WeakETag returns a weak etag from a given value.
+func WeakETag(value string) string {
+	return `W/"` + value + `"`
+}
+
+// fileInfoETag returns a weak etag for a file from its size and modification time.
+func fileInfoETag(size int64, modTime time.Time) string {
+	return WeakETag(fmt.Sprintf("%x-%x", size, modTime.UnixNano()))
+}
+
+func isConditionalMethod(method string) bool {
+	return method == MethodGet || method == MethodHead
+}
+
+// etagMatches performs the weak comparison required for `If-None-Match`.
+func etagMatches(header, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
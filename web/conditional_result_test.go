@@ -0,0 +1,141 @@
+package web
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/r2"
+)
+
+func TestETagMiddleware(t *testing.T) {
+	assert := assert.New(t)
+
+	app := MustNew()
+	app.GET("/", func(_ *Ctx) Result {
+		return JSON.Result(map[string]string{"foo": "bar"})
+	}, ETag)
+
+	contents, res, err := MockGet(app, "/").BytesWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, res.StatusCode)
+	assert.NotEmpty(contents)
+	etag := res.Header.Get(HeaderETag)
+	assert.NotEmpty(etag)
+	assert.Equal(`"`, etag[:1])
+
+	contents, res, err = MockGet(app, "/", r2.OptHeaderValue(HeaderIfNoneMatch, etag)).BytesWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusNotModified, res.StatusCode)
+	assert.Empty(contents)
+	assert.Equal(etag, res.Header.Get(HeaderETag))
+
+	contents, res, err = MockGet(app, "/", r2.OptHeaderValue(HeaderIfNoneMatch, `"not-it"`)).BytesWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, res.StatusCode)
+	assert.NotEmpty(contents)
+}
+
+func TestETagMiddlewareSkipsErrors(t *testing.T) {
+	assert := assert.New(t)
+
+	app := MustNew()
+	app.GET("/", func(_ *Ctx) Result {
+		return JSON.NotFound()
+	}, ETag)
+
+	res, err := MockGet(app, "/").DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusNotFound, res.StatusCode)
+	assert.Empty(res.Header.Get(HeaderETag))
+}
+
+func TestResultWithETag(t *testing.T) {
+	assert := assert.New(t)
+
+	var renders int
+	app := MustNew()
+	app.GET("/", func(_ *Ctx) Result {
+		renders++
+		return ResultWithETag(JSON.OK(), "v1")
+	})
+
+	res, err := MockGet(app, "/", r2.OptHeaderValue(HeaderIfNoneMatch, `W/"v0", "v1"`)).DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusNotModified, res.StatusCode)
+	assert.Equal(`"v1"`, res.Header.Get(HeaderETag))
+
+	res, err = MockGet(app, "/", r2.OptHeaderValue(HeaderIfNoneMatch, "*")).DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusNotModified, res.StatusCode)
+
+	res, err = MockMethod(app, "POST", "/", r2.OptHeaderValue(HeaderIfNoneMatch, `"v1"`)).DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusNotFound, res.StatusCode)
+	assert.Equal(2, renders)
+}
+
+func TestResultWithLastModified(t *testing.T) {
+	assert := assert.New(t)
+
+	modified := time.Date(2019, 02, 03, 04, 05, 06, 07, time.UTC)
+	app := MustNew()
+	app.GET("/", func(_ *Ctx) Result {
+		return ResultWithLastModified(JSON.OK(), modified)
+	})
+
+	res, err := MockGet(app, "/", r2.OptHeaderValue(HeaderIfModifiedSince, modified.Format(http.TimeFormat))).DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusNotModified, res.StatusCode)
+	assert.Equal(modified.Format(http.TimeFormat), res.Header.Get(HeaderLastModified))
+
+	res, err = MockGet(app, "/", r2.OptHeaderValue(HeaderIfModifiedSince, modified.Add(-time.Hour).Format(http.TimeFormat))).DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, res.StatusCode)
+}
+
+func TestIsNotModified(t *testing.T) {
+	assert := assert.New(t)
+
+	var renders int
+	app := MustNew()
+	app.GET("/", func(ctx *Ctx) Result {
+		if IsNotModified(ctx, "v2", time.Time{}) {
+			return NotModified
+		}
+		renders++
+		return JSON.OK()
+	})
+
+	res, err := MockGet(app, "/", r2.OptHeaderValue(HeaderIfNoneMatch, `"v2"`)).DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusNotModified, res.StatusCode)
+	assert.Zero(renders)
+
+	res, err = MockGet(app, "/").DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, res.StatusCode)
+	assert.Equal(`"v2"`, res.Header.Get(HeaderETag))
+	assert.Equal(1, renders)
+}
+
+func TestStaticResultETag(t *testing.T) {
+	assert := assert.New(t)
+
+	app := MustNew()
+	app.GET("/", func(_ *Ctx) Result {
+		return Static("testdata/test_file.html")
+	}, ETag)
+
+	res, err := MockGet(app, "/").DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, res.StatusCode)
+	etag := res.Header.Get(HeaderETag)
+	assert.NotEmpty(etag)
+	assert.Equal(`W/"`, etag[:3])
+
+	res, err = MockGet(app, "/", r2.OptHeaderValue(HeaderIfNoneMatch, etag)).DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusNotModified, res.StatusCode)
+}
@@ -31,6 +31,7 @@ type Config struct {
 
 	DefaultHeaders      map[string]string `json:"defaultHeaders,omitempty" yaml:"defaultHeaders,omitempty"`
 	MaxHeaderBytes      int               `json:"maxHeaderBytes,omitempty" yaml:"maxHeaderBytes,omitempty" env:"MAX_HEADER_BYTES"`
+	MaxBodyBytes        int64             `json:"maxBodyBytes,omitempty" yaml:"maxBodyBytes,omitempty" env:"MAX_BODY_BYTES"`
 	ReadTimeout         time.Duration     `json:"readTimeout,omitempty" yaml:"readTimeout,omitempty" env:"READ_HEADER_TIMEOUT"`
 	ReadHeaderTimeout   time.Duration     `json:"readHeaderTimeout,omitempty" yaml:"readHeaderTimeout,omitempty" env:"READ_HEADER_TIMEOUT"`
 	WriteTimeout        time.Duration     `json:"writeTimeout,omitempty" yaml:"writeTimeout,omitempty" env:"WRITE_TIMEOUT"`
@@ -153,6 +154,14 @@ func (c Config) MaxHeaderBytesOrDefault() int {
 	return DefaultMaxHeaderBytes
 }
 
+// MaxBodyBytesOrDefault returns the maximum request body size in bytes or a default.
+func (c Config) MaxBodyBytesOrDefault() int64 {
+	if c.MaxBodyBytes > 0 {
+		return c.MaxBodyBytes
+	}
+	return DefaultMaxBodyBytes
+}
+
 // ReadTimeoutOrDefault gets a property.
 func (c Config) ReadTimeoutOrDefault() time.Duration {
 	if c.ReadTimeout > 0 {
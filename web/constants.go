@@ -18,6 +18,10 @@ const (
 	// RegexpAssetCacheFiles is a common regex for parsing css, js, and html file routes.
 	RegexpAssetCacheFiles = `^(.*)\.([0-9]+)\.(css|js|html|htm)$`
 
+	// HeaderAccept is the "Accept" header.
+	// It indicates what media types the request will accept responses as.
+	HeaderAccept = "Accept"
+
 	// HeaderAcceptEncoding is the "Accept-Encoding" header.
 	// It indicates what types of encodings the request will accept responses as.
 	// It typically enables or disables compressed (gzipped) responses.
@@ -59,6 +63,22 @@ const (
 	// It specifies the MIME-type of the request or response.
 	HeaderContentType = "Content-Type"
 
+	// HeaderETag is the "ETag" header.
+	// It is an opaque validator for the current representation of a resource.
+	HeaderETag = "ETag"
+
+	// HeaderIfModifiedSince is the "If-Modified-Since" header.
+	// It makes a GET or HEAD request conditional on the resource changing after a given date.
+	HeaderIfModifiedSince = "If-Modified-Since"
+
+	// HeaderIfNoneMatch is the "If-None-Match" header.
+	// It makes a GET or HEAD request conditional on the resource not matching any of the given ETags.
+	HeaderIfNoneMatch = "If-None-Match"
+
+	// HeaderLastModified is the "Last-Modified" header.
+	// It is the date the resource was last changed.
+	HeaderLastModified = "Last-Modified"
+
 	// HeaderServer is the "Server" header.
 	// It is an informational header to tell the client what server software was used.
 	HeaderServer = "Server"
@@ -89,6 +109,41 @@ const (
 	// HeaderStrictTransportSecurity is the hsts header.
 	HeaderStrictTransportSecurity = "Strict-Transport-Security"
 
+	// HeaderUpgrade is the "Upgrade" header.
+	// It is used by clients to request a protocol switch, e.g. to websockets.
+	HeaderUpgrade = "Upgrade"
+
+	// HeaderOrigin is the "Origin" header.
+	HeaderOrigin = "Origin"
+
+	// HeaderSecWebSocketKey is the websocket handshake key header.
+	HeaderSecWebSocketKey = "Sec-Websocket-Key"
+
+	// HeaderSecWebSocketAccept is the websocket handshake accept header.
+	HeaderSecWebSocketAccept = "Sec-Websocket-Accept"
+
+	// HeaderSecWebSocketVersion is the websocket protocol version header.
+	HeaderSecWebSocketVersion = "Sec-Websocket-Version"
+
+	// HeaderSecWebSocketProtocol is the websocket subprotocol header.
+	HeaderSecWebSocketProtocol = "Sec-Websocket-Protocol"
+
+	// HeaderRetryAfter is the "Retry-After" header.
+	// It indicates how many seconds a client should wait before making another request.
+	HeaderRetryAfter = "Retry-After"
+
+	// HeaderRateLimitLimit is the "RateLimit-Limit" header.
+	// It indicates the request quota for the client.
+	HeaderRateLimitLimit = "Ratelimit-Limit"
+
+	// HeaderRateLimitRemaining is the "RateLimit-Remaining" header.
+	// It indicates the remaining request quota for the client.
+	HeaderRateLimitRemaining = "Ratelimit-Remaining"
+
+	// HeaderRateLimitReset is the "RateLimit-Reset" header.
+	// It indicates the number of seconds until the quota resets.
+	HeaderRateLimitReset = "Ratelimit-Reset"
+
 	// ContentTypeApplicationJSON is a content type for JSON responses.
 	// We specify chartset=utf-8 so that clients know to use the UTF-8 string encoding.
 	ContentTypeApplicationJSON = "application/json; charset=UTF-8"
@@ -114,6 +169,8 @@ const (
 	ContentEncodingIdentity = "identity"
 	// ContentEncodingGZIP is the gzip (compressed) content encoding.
 	ContentEncodingGZIP = "gzip"
+	// ContentEncodingDeflate is the deflate (zlib compressed) content encoding.
+	ContentEncodingDeflate = "deflate"
 )
 
 const (
@@ -131,6 +188,9 @@ const (
 	// MethodGet is an http verb.
 	MethodGet = "GET"
 
+	// MethodHead is an http verb.
+	MethodHead = "HEAD"
+
 	// MethodPost is an http verb.
 	MethodPost = "POST"
 
@@ -197,6 +257,8 @@ const (
 	DefaultBindAddr = ":8080"
 	// DefaultHealthzBindAddr is the default healthz bind address.
 	DefaultHealthzBindAddr = ":8081"
+	// DefaultHealthCheckTimeout is the default timeout for individual health checks.
+	DefaultHealthCheckTimeout = 5 * time.Second
 	// DefaultMockBindAddr is a bind address used for integration testing.
 	DefaultMockBindAddr = "127.0.0.1:0"
 	// DefaultSkipRedirectTrailingSlash is the default if we should redirect for missing trailing slashes.
@@ -210,6 +272,8 @@ const (
 
 	// DefaultMaxHeaderBytes is a default that is unset.
 	DefaultMaxHeaderBytes = 0
+	// DefaultMaxBodyBytes is a default that is unset, i.e. request bodies are not limited.
+	DefaultMaxBodyBytes int64 = 0
 	// DefaultReadTimeout is a default.
 	DefaultReadTimeout = 5 * time.Second
 	// DefaultReadHeaderTimeout is a default.
@@ -246,6 +310,16 @@ const (
 
 	// DefaultBufferPoolSize is the default buffer pool size.
 	DefaultViewBufferPoolSize = 256
+
+	// DefaultWebSocketReadLimit is the default maximum size of a websocket message (32MiB).
+	DefaultWebSocketReadLimit = 32 << 20
+
+	// DefaultRateLimit is the default number of requests allowed per rate limit period.
+	DefaultRateLimit = 60
+	// DefaultRateLimitPeriod is the default rate limit period.
+	DefaultRateLimitPeriod = time.Minute
+	// DefaultRateLimitSweepInterval is the default interval between sweeps of expired local rate limit states.
+	DefaultRateLimitSweepInterval = time.Minute
 )
 
 // DefaultHeaders are the default headers added by go-web.
@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"encoding/xml"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
@@ -50,6 +51,10 @@ type Ctx struct {
 	// the `.PostBody()` function, instead read directly from `.Request.Body` with
 	// a stream reader or similar.
 	Body []byte
+	// MaxBodyBytes is the maximum size of the decoded post body read by `.PostBody()`.
+	// It is the app config value by default but can be overwritten by middleware.
+	// If zero, the post body size is not limited.
+	MaxBodyBytes int64
 	// Form is a cache of parsed url form values from the post body.
 	Form url.Values
 	// State is a mutable bag of state, it contains by default
@@ -199,12 +204,26 @@ func (rc *Ctx) HeaderValue(key string) (value string, err error) {
 // It will store those bytes for re-use on this context object.
 // If you're expecting a large post body, or a large post body is even possible
 // use a stream reader on `.Request.Body` instead of this method.
+//
+// Bodies with a "gzip" or "deflate" `Content-Encoding` are decompressed transparently.
+// If `.MaxBodyBytes` is set and the decoded body exceeds it, an error of class
+// `ErrRequestBodyTooLarge` is returned.
 func (rc *Ctx) PostBody() ([]byte, error) {
-	var err error
-	if len(rc.Body) == 0 {
-		if rc.Request != nil && rc.Request.Body != nil {
-			defer rc.Request.Body.Close()
-			rc.Body, err = ioutil.ReadAll(rc.Request.Body)
+	if len(rc.Body) == 0 && rc.Request != nil && rc.Request.Body != nil {
+		defer rc.Request.Body.Close()
+		body, err := NewRequestBodyReader(rc.Request)
+		if err != nil {
+			return nil, err
+		}
+		defer body.Close()
+		if rc.MaxBodyBytes > 0 {
+			rc.Body, err = ioutil.ReadAll(io.LimitReader(body, rc.MaxBodyBytes+1))
+			if err == nil && int64(len(rc.Body)) > rc.MaxBodyBytes {
+				rc.Body = nil
+				return nil, ex.New(ErrRequestBodyTooLarge, ex.OptMessagef("max body bytes: %d", rc.MaxBodyBytes))
+			}
+		} else {
+			rc.Body, err = ioutil.ReadAll(body)
 		}
 		if err != nil {
 			return nil, ex.New(err)
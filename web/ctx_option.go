@@ -54,6 +54,11 @@ func OptCtxTracer(tracer Tracer) CtxOption {
 	return func(c *Ctx) { c.Tracer = tracer }
 }
 
+// OptCtxMaxBodyBytes sets the context max body size.
+func OptCtxMaxBodyBytes(maxBodyBytes int64) CtxOption {
+	return func(c *Ctx) { c.MaxBodyBytes = maxBodyBytes }
+}
+
 // OptCtxRouteParamValue sets the context default result provider.
 func OptCtxRouteParamValue(key, value string) CtxOption {
 	return func(c *Ctx) {
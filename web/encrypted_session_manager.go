@@ -0,0 +1,158 @@
+package web
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/blend/go-sdk/crypto"
+	"github.com/blend/go-sdk/ex"
+)
+
+// NewEncryptedSessionManager returns a new encrypted session manager.
+// The first key is used to encrypt sessions, and all the keys are tried when decrypting,
+// which allows keys to be rotated without logging out every user.
+func NewEncryptedSessionManager(keys ...[]byte) *EncryptedSessionManager {
+	return &EncryptedSessionManager{
+		Keys: keys,
+	}
+}
+
+// NewEncryptedAuthManager returns a new auth manager that stores sessions in encrypted cookies.
+func NewEncryptedAuthManager(keys [][]byte, options ...AuthManagerOption) (manager AuthManager, err error) {
+	return NewEncryptedAuthManagerFromSessions(NewEncryptedSessionManager(keys...), options...)
+}
+
+// NewEncryptedAuthManagerFromSessions returns a new auth manager that stores sessions in encrypted cookies
+// with a given session manager, e.g. one with revocations set.
+func NewEncryptedAuthManagerFromSessions(sessions *EncryptedSessionManager, options ...AuthManagerOption) (manager AuthManager, err error) {
+	manager, err = NewAuthManager(options...)
+	if err != nil {
+		return
+	}
+	manager.SerializeSessionValueHandler = sessions.SerializeSessionValueHandler
+	manager.ParseSessionValueHandler = sessions.ParseSessionValueHandler
+	return
+}
+
+// EncryptedSessionManager stores sessions in the session cookie itself, encrypted with
+// `crypto.Encrypt` and authenticated with an HMAC, with keys derived separately from each configured key.
+//
+// Because sessions are not stored server side, a user's sessions are logged out with `RemoveUserSessions`,
+// which needs `Revocations` to be set, and which revokes the sessions created before it is called.
+// To log out every user, rotate the keys.
+type EncryptedSessionManager struct {
+	Keys [][]byte
+	// Revocations holds when each user's sessions were revoked; sessions created before then are invalid.
+	// It must be shared if sessions are verified by several replicas.
+	Revocations SessionRevocations
+}
+
+// SessionRevocations stores when each user's sessions were revoked.
+type SessionRevocations interface {
+	RevokeUserSessions(ctx context.Context, userID string, revoked time.Time) error
+	UserSessionsRevoked(ctx context.Context, userID string) (time.Time, error)
+}
+
+// RemoveUserSessions logs out all of a user's sessions, by revoking the sessions created before now.
+func (esm EncryptedSessionManager) RemoveUserSessions(ctx context.Context, userID string) error {
+	if esm.Revocations == nil {
+		return ex.New(ErrSessionValueInvalid, ex.OptMessage("encrypted session manager revocations are unset"))
+	}
+	return esm.Revocations.RevokeUserSessions(ctx, userID, time.Now().UTC())
+}
+
+// SerializeSessionValueHandler is a shim to the auth manager.
+func (esm EncryptedSessionManager) SerializeSessionValueHandler(_ context.Context, session *Session) (string, error) {
+	if len(esm.Keys) == 0 {
+		return "", ex.New(ErrSessionValueInvalid, ex.OptMessage("encrypted session manager keys are unset"))
+	}
+	contents, err := json.Marshal(session)
+	if err != nil {
+		return "", ex.New(err)
+	}
+	encryptionKey, macKey := encryptedSessionKeys(esm.Keys[0])
+	cipherText, err := crypto.Encrypt(encryptionKey, contents)
+	if err != nil {
+		return "", ex.New(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(append(cipherText, crypto.HMAC512(macKey, cipherText)...)), nil
+}
+
+// ParseSessionValueHandler is a shim to the auth manager.
+func (esm EncryptedSessionManager) ParseSessionValueHandler(ctx context.Context, sessionValue string) (*Session, error) {
+	contents, err := base64.RawURLEncoding.DecodeString(sessionValue)
+	if err != nil || len(contents) < sha512.Size {
+		return nil, ex.New(ErrSessionValueInvalid)
+	}
+	cipherText, mac := contents[:len(contents)-sha512.Size], contents[len(contents)-sha512.Size:]
+	for _, key := range esm.Keys {
+		encryptionKey, macKey := encryptedSessionKeys(key)
+		if !hmac.Equal(mac, crypto.HMAC512(macKey, cipherText)) {
+			continue
+		}
+		plainText, err := crypto.Decrypt(encryptionKey, cipherText)
+		if err != nil {
+			return nil, ex.New(ErrSessionValueInvalid, ex.OptInner(err))
+		}
+		var session Session
+		if err = json.Unmarshal(plainText, &session); err != nil {
+			return nil, ex.New(ErrSessionValueInvalid, ex.OptInner(err))
+		}
+		if esm.Revocations != nil {
+			revoked, err := esm.Revocations.UserSessionsRevoked(ctx, session.UserID)
+			if err != nil {
+				return nil, err
+			}
+			if !revoked.IsZero() && session.CreatedUTC.Before(revoked) {
+				return nil, ex.New(ErrSessionValueInvalid, ex.OptMessage("session revoked"))
+			}
+		}
+		return &session, nil
+	}
+	return nil, ex.New(ErrSessionValueInvalid)
+}
+
+// encryptedSessionKeys derives separate encryption and mac keys from a configured key,
+// so the same key is not used for both.
+func encryptedSessionKeys(key []byte) (encryptionKey, macKey []byte) {
+	encryptionKey = crypto.HMAC512(key, []byte("enc"))[:32]
+	macKey = crypto.HMAC512(key, []byte("mac"))
+	return
+}
+
+// NewLocalSessionRevocations returns new session revocations held in memory,
+// for apps that run a single replica.
+func NewLocalSessionRevocations() *LocalSessionRevocations {
+	return &LocalSessionRevocations{
+		Revoked: map[string]time.Time{},
+	}
+}
+
+// LocalSessionRevocations holds when each user's sessions were revoked in memory.
+type LocalSessionRevocations struct {
+	sync.Mutex
+	Revoked map[string]time.Time
+}
+
+// RevokeUserSessions implements SessionRevocations.
+func (lsr *LocalSessionRevocations) RevokeUserSessions(_ context.Context, userID string, revoked time.Time) error {
+	lsr.Lock()
+	defer lsr.Unlock()
+	if lsr.Revoked == nil {
+		lsr.Revoked = map[string]time.Time{}
+	}
+	lsr.Revoked[userID] = revoked
+	return nil
+}
+
+// UserSessionsRevoked implements SessionRevocations.
+func (lsr *LocalSessionRevocations) UserSessionsRevoked(_ context.Context, userID string) (time.Time, error) {
+	lsr.Lock()
+	defer lsr.Unlock()
+	return lsr.Revoked[userID], nil
+}
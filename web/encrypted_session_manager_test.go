@@ -0,0 +1,144 @@
+package web
+
+import (
+	"context"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/crypto"
+	"github.com/blend/go-sdk/uuid"
+)
+
+func TestEncryptedSessionManager(t *testing.T) {
+	assert := assert.New(t)
+
+	m := NewEncryptedSessionManager(crypto.MustCreateKey(32))
+	session := &Session{
+		SessionID:  uuid.V4().String(),
+		UserID:     uuid.V4().String(),
+		CreatedUTC: time.Date(2018, 9, 8, 12, 00, 0, 0, time.UTC),
+		ExpiresUTC: time.Date(2018, 9, 9, 12, 00, 0, 0, time.UTC),
+		State:      map[string]interface{}{"foo": "bar"},
+	}
+
+	value, err := m.SerializeSessionValueHandler(context.Background(), session)
+	assert.Nil(err)
+	assert.NotEmpty(value)
+
+	parsed, err := m.ParseSessionValueHandler(context.Background(), value)
+	assert.Nil(err)
+	assert.Equal(session.SessionID, parsed.SessionID)
+	assert.Equal(session.UserID, parsed.UserID)
+	assert.Equal(session.ExpiresUTC, parsed.ExpiresUTC)
+	assert.Equal("bar", parsed.State["foo"])
+
+	tampered := []byte(value)
+	tampered[len(tampered)/2] ^= 'A' ^ 'B'
+	_, err = m.ParseSessionValueHandler(context.Background(), string(tampered))
+	assert.True(IsErrSessionInvalid(err))
+
+	_, err = m.ParseSessionValueHandler(context.Background(), "not-a-session")
+	assert.True(IsErrSessionInvalid(err))
+}
+
+func TestEncryptedSessionManagerKeyRotation(t *testing.T) {
+	assert := assert.New(t)
+
+	oldKey, newKey := crypto.MustCreateKey(32), crypto.MustCreateKey(32)
+	session := NewSession(uuid.V4().String(), NewSessionID())
+
+	value, err := NewEncryptedSessionManager(oldKey).SerializeSessionValueHandler(context.Background(), session)
+	assert.Nil(err)
+
+	parsed, err := NewEncryptedSessionManager(newKey, oldKey).ParseSessionValueHandler(context.Background(), value)
+	assert.Nil(err)
+	assert.Equal(session.SessionID, parsed.SessionID)
+
+	_, err = NewEncryptedSessionManager(newKey).ParseSessionValueHandler(context.Background(), value)
+	assert.True(IsErrSessionInvalid(err))
+}
+
+func TestEncryptedAuthManager(t *testing.T) {
+	assert := assert.New(t)
+
+	am, err := NewEncryptedAuthManager([][]byte{crypto.MustCreateKey(32)})
+	assert.Nil(err)
+
+	loginCtx := MockCtx("GET", "/")
+	session, err := am.Login("example-string", loginCtx)
+	assert.Nil(err)
+	cookies := readSetCookies(loginCtx.Response.Header())
+	assert.Len(cookies, 1)
+
+	verifyCtx := MockCtx("GET", "/", OptCtxCookieValue(am.CookieDefaults.Name, cookies[0].Value))
+	verified, err := am.VerifySession(verifyCtx)
+	assert.Nil(err)
+	assert.NotNil(verified)
+	assert.Equal(session.SessionID, verified.SessionID)
+	assert.Equal("example-string", verified.UserID)
+
+	invalidCtx := MockCtx("GET", "/", OptCtxCookieValue(am.CookieDefaults.Name, "garbage"))
+	verified, err = am.VerifySession(invalidCtx)
+	assert.NotNil(err)
+	assert.Nil(verified)
+	assert.NotEmpty(invalidCtx.Response.Header().Get(HeaderSetCookie))
+}
+
+func TestEncryptedSessionManagerKeysSeparate(t *testing.T) {
+	assert := assert.New(t)
+
+	key := crypto.MustCreateKey(32)
+	encryptionKey, macKey := encryptedSessionKeys(key)
+	assert.Len(encryptionKey, 32)
+	assert.NotEqual(key, encryptionKey)
+	assert.NotEqual(key, macKey)
+	assert.NotEqual(encryptionKey, macKey[:32])
+
+	// the session is not encrypted with the configured key itself.
+	value, err := NewEncryptedSessionManager(key).SerializeSessionValueHandler(context.Background(), NewSession("user", NewSessionID()))
+	assert.Nil(err)
+	contents, err := base64.RawURLEncoding.DecodeString(value)
+	assert.Nil(err)
+	plainText, err := crypto.Decrypt(key, contents[:len(contents)-sha512.Size])
+	assert.Nil(err)
+	assert.False(json.Valid(plainText))
+}
+
+func TestEncryptedSessionManagerRemoveUserSessions(t *testing.T) {
+	assert := assert.New(t)
+
+	m := NewEncryptedSessionManager(crypto.MustCreateKey(32))
+	assert.NotNil(m.RemoveUserSessions(context.Background(), "user"), "revocations are required")
+
+	m.Revocations = NewLocalSessionRevocations()
+	session := NewSession("user", NewSessionID())
+	session.CreatedUTC = time.Now().UTC().Add(-time.Minute)
+	other := NewSession("other-user", NewSessionID())
+	other.CreatedUTC = session.CreatedUTC
+
+	value, err := m.SerializeSessionValueHandler(context.Background(), session)
+	assert.Nil(err)
+	otherValue, err := m.SerializeSessionValueHandler(context.Background(), other)
+	assert.Nil(err)
+
+	assert.Nil(m.RemoveUserSessions(context.Background(), "user"))
+	_, err = m.ParseSessionValueHandler(context.Background(), value)
+	assert.True(IsErrSessionInvalid(err))
+
+	// other users, and sessions created after the revocation, are still valid.
+	_, err = m.ParseSessionValueHandler(context.Background(), otherValue)
+	assert.Nil(err)
+	value, err = m.SerializeSessionValueHandler(context.Background(), NewSession("user", NewSessionID()))
+	assert.Nil(err)
+	_, err = m.ParseSessionValueHandler(context.Background(), value)
+	assert.Nil(err)
+}
+
+func readSetCookies(header http.Header) []*http.Cookie {
+	return (&http.Response{Header: header}).Cookies()
+}
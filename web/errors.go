@@ -10,10 +10,26 @@ const (
 	ErrSessionIDEmpty ex.Class = "auth session id is empty"
 	// ErrSecureSessionIDEmpty is an error that is thrown if a given secure session id is invalid.
 	ErrSecureSessionIDEmpty ex.Class = "auth secure session id is empty"
+	// ErrSessionValueInvalid is an error that is thrown if a session value cannot be parsed or authenticated.
+	ErrSessionValueInvalid ex.Class = "auth session value is invalid"
 	// ErrUnsetViewTemplate is an error that is thrown if a given secure session id is invalid.
 	ErrUnsetViewTemplate ex.Class = "view result template is unset"
 	// ErrParameterMissing is an error on request validation.
 	ErrParameterMissing ex.Class = "parameter is missing"
+	// ErrWebSocketBadHandshake is an error returned if a websocket upgrade request is invalid.
+	ErrWebSocketBadHandshake ex.Class = "websocket handshake is invalid"
+	// ErrWebSocketProtocol is an error returned if a peer violates the websocket protocol.
+	ErrWebSocketProtocol ex.Class = "websocket protocol error"
+	// ErrWebSocketReadLimit is an error returned if a websocket message exceeds the read limit.
+	ErrWebSocketReadLimit ex.Class = "websocket message exceeds read limit"
+	// ErrWebSocketClosed is an error returned if a write is attempted after a close frame is sent.
+	ErrWebSocketClosed ex.Class = "websocket connection is closed"
+	// ErrRequestBodyTooLarge is an error returned if a request body exceeds the max body size.
+	ErrRequestBodyTooLarge ex.Class = "request body too large"
+	// ErrUnsupportedContentEncoding is an error returned if a request body has an unknown content encoding.
+	ErrUnsupportedContentEncoding ex.Class = "unsupported request content encoding"
+	// ErrHealthCheckTimeout is an error returned if a health check exceeds its timeout.
+	ErrHealthCheckTimeout ex.Class = "health check timed out"
 )
 
 // NewParameterMissingError returns a new parameter missing error.
@@ -28,12 +44,29 @@ func IsErrSessionInvalid(err error) bool {
 	}
 	if ex.Is(err, ErrSessionIDEmpty) ||
 		ex.Is(err, ErrSecureSessionIDEmpty) ||
+		ex.Is(err, ErrSessionValueInvalid) ||
 		ex.Is(err, jwt.ErrValidation) {
 		return true
 	}
 	return false
 }
 
+// IsErrRequestBodyTooLarge returns if an error is a request body too large error.
+func IsErrRequestBodyTooLarge(err error) bool {
+	if err == nil {
+		return false
+	}
+	return ex.Is(err, ErrRequestBodyTooLarge)
+}
+
+// IsErrUnsupportedContentEncoding returns if an error is an unsupported content encoding error.
+func IsErrUnsupportedContentEncoding(err error) bool {
+	if err == nil {
+		return false
+	}
+	return ex.Is(err, ErrUnsupportedContentEncoding)
+}
+
 // IsErrParameterMissing returns if an error is a session invalid error.
 func IsErrParameterMissing(err error) bool {
 	if err == nil {
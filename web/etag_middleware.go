@@ -0,0 +1,23 @@
+package web
+
+// ETag is a middleware that computes etags for GET and HEAD responses
+// and returns 304 Not Modified when the client sends a matching `If-None-Match` header.
+//
+// Results that already handle conditional requests (static files and explicit conditional results)
+// are rendered as-is.
+func ETag(action Action) Action {
+	return func(r *Ctx) Result {
+		result := action(r)
+		if result == nil || !isConditionalMethod(r.Request.Method) {
+			return result
+		}
+		switch result.(type) {
+		case *ConditionalResult, ConditionalResult,
+			*StaticResult, StaticResult,
+			*CachedStaticFile, CachedStaticFile,
+			NotModifiedResult, *NotModifiedResult:
+			return result
+		}
+		return &ConditionalResult{Result: result}
+	}
+}
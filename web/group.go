@@ -0,0 +1,143 @@
+package web
+
+import "strings"
+
+// Group returns a new route group with a given path prefix and middleware.
+/*
+Routes registered on the group are registered on the app with the group prefix,
+and the group middleware is applied to each action before the app default middleware.
+
+	api := app.Group("/api/v1", web.SessionRequired)
+	api.GET("/users", listUsers)           // GET /api/v1/users
+	admin := api.Group("/admin", requireAdmin)
+	admin.DELETE("/users/:id", deleteUser) // DELETE /api/v1/admin/users/:id
+*/
+func (a *App) Group(prefix string, middleware ...Middleware) *Group {
+	g := &Group{
+		App:        a,
+		Prefix:     formatGroupPrefix(prefix),
+		Middleware: middleware,
+	}
+	a.Groups = append(a.Groups, g)
+	return g
+}
+
+// Group is a set of routes that share a path prefix and middleware.
+type Group struct {
+	App                     *App
+	Parent                  *Group
+	Prefix                  string
+	Middleware              []Middleware
+	NotFoundHandler         Handler
+	MethodNotAllowedHandler Handler
+}
+
+// Group returns a nested route group.
+// The nested group prefix is appended to the group prefix, and the group middleware
+// is applied inside the parent group middleware.
+func (g *Group) Group(prefix string, middleware ...Middleware) *Group {
+	child := &Group{
+		App:        g.App,
+		Parent:     g,
+		Prefix:     g.Prefix + formatGroupPrefix(prefix),
+		Middleware: middleware,
+	}
+	g.App.Groups = append(g.App.Groups, child)
+	return child
+}
+
+// NotFound sets the not found action for requests under the group prefix.
+// The action is wrapped with the group middleware.
+func (g *Group) NotFound(action Action) {
+	g.NotFoundHandler = g.App.RenderAction(g.NestMiddleware(action))
+}
+
+// MethodNotAllowed sets the method not allowed action for requests under the group prefix.
+// The action is wrapped with the group middleware.
+func (g *Group) MethodNotAllowed(action Action) {
+	g.MethodNotAllowedHandler = g.App.RenderAction(g.NestMiddleware(action))
+}
+
+// Matches returns if a given path falls under the group prefix.
+func (g *Group) Matches(path string) bool {
+	if g.Prefix == "" {
+		return true
+	}
+	if !strings.HasPrefix(path, g.Prefix) {
+		return false
+	}
+	return len(path) == len(g.Prefix) || path[len(g.Prefix)] == '/'
+}
+
+// GET registers a GET request handler.
+func (g *Group) GET(path string, action Action, middleware ...Middleware) {
+	g.Handle("GET", path, g.App.RenderAction(g.NestMiddleware(action, middleware...)))
+}
+
+// OPTIONS registers a OPTIONS request handler.
+func (g *Group) OPTIONS(path string, action Action, middleware ...Middleware) {
+	g.Handle("OPTIONS", path, g.App.RenderAction(g.NestMiddleware(action, middleware...)))
+}
+
+// HEAD registers a HEAD request handler.
+func (g *Group) HEAD(path string, action Action, middleware ...Middleware) {
+	g.Handle("HEAD", path, g.App.RenderAction(g.NestMiddleware(action, middleware...)))
+}
+
+// PUT registers a PUT request handler.
+func (g *Group) PUT(path string, action Action, middleware ...Middleware) {
+	g.Handle("PUT", path, g.App.RenderAction(g.NestMiddleware(action, middleware...)))
+}
+
+// PATCH registers a PATCH request handler.
+func (g *Group) PATCH(path string, action Action, middleware ...Middleware) {
+	g.Handle("PATCH", path, g.App.RenderAction(g.NestMiddleware(action, middleware...)))
+}
+
+// POST registers a POST request actions.
+func (g *Group) POST(path string, action Action, middleware ...Middleware) {
+	g.Handle("POST", path, g.App.RenderAction(g.NestMiddleware(action, middleware...)))
+}
+
+// DELETE registers a DELETE request handler.
+func (g *Group) DELETE(path string, action Action, middleware ...Middleware) {
+	g.Handle("DELETE", path, g.App.RenderAction(g.NestMiddleware(action, middleware...)))
+}
+
+// Handle adds a raw handler at a given method and path relative to the group prefix.
+func (g *Group) Handle(method, path string, handler Handler) {
+	if len(path) == 0 {
+		panic("path must not be empty")
+	}
+	if path[0] != '/' {
+		panic("path must begin with '/' in path '" + path + "'")
+	}
+	if g.Prefix != "" && path == "/" {
+		path = ""
+	}
+	g.App.Handle(method, g.Prefix+path, handler)
+}
+
+// NestMiddleware wraps an action with the given middleware, the group middleware
+// (including any parent groups), and the app level default middleware.
+func (g *Group) NestMiddleware(action Action, middleware ...Middleware) Action {
+	var finalMiddleware []Middleware
+	finalMiddleware = append(finalMiddleware, middleware...)
+	for group := g; group != nil; group = group.Parent {
+		finalMiddleware = append(finalMiddleware, group.Middleware...)
+	}
+	return g.App.NestMiddleware(action, finalMiddleware...)
+}
+
+//
+// internal helpers
+//
+
+// formatGroupPrefix ensures a prefix starts with a slash and does not end with one.
+func formatGroupPrefix(prefix string) string {
+	prefix = strings.TrimRight(prefix, "/")
+	if prefix != "" && prefix[0] != '/' {
+		prefix = "/" + prefix
+	}
+	return prefix
+}
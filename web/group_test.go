@@ -0,0 +1,89 @@
+package web
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func recordingMiddleware(name string, calls *[]string) Middleware {
+	return func(action Action) Action {
+		return func(ctx *Ctx) Result {
+			*calls = append(*calls, name)
+			return action(ctx)
+		}
+	}
+}
+
+func TestGroup(t *testing.T) {
+	assert := assert.New(t)
+
+	var calls []string
+	app := MustNew(OptUse(recordingMiddleware("app", &calls)))
+	api := app.Group("/api/v1/", recordingMiddleware("api", &calls))
+	assert.Equal("/api/v1", api.Prefix)
+	api.GET("/", func(_ *Ctx) Result { return Text.Result("index") })
+	api.GET("/users/:id", func(ctx *Ctx) Result {
+		return Text.Result(ctx.RouteParams.Get("id"))
+	}, recordingMiddleware("route", &calls))
+	admin := api.Group("admin", recordingMiddleware("admin", &calls))
+	admin.POST("/users", func(_ *Ctx) Result { return Text.Result("created") })
+
+	contents, err := MockGet(app, "/api/v1").Bytes()
+	assert.Nil(err)
+	assert.Equal("index", string(contents))
+
+	calls = nil
+	contents, err = MockGet(app, "/api/v1/users/1234").Bytes()
+	assert.Nil(err)
+	assert.Equal("1234", string(contents))
+	assert.Equal([]string{"app", "api", "route"}, calls)
+
+	calls = nil
+	res, err := MockMethod(app, "POST", "/api/v1/admin/users").DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, res.StatusCode)
+	assert.Equal([]string{"app", "api", "admin"}, calls)
+}
+
+func TestGroupNotFound(t *testing.T) {
+	assert := assert.New(t)
+
+	app := MustNew(OptNotFoundHandler(func(_ *Ctx) Result {
+		return Text.Status(http.StatusTeapot)
+	}))
+	api := app.Group("/api")
+	api.NotFound(func(_ *Ctx) Result { return JSON.NotFound() })
+	v2 := api.Group("/v2")
+	v2.NotFound(func(_ *Ctx) Result { return Text.Status(http.StatusGone) })
+
+	res, err := MockGet(app, "/api/nope").DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusNotFound, res.StatusCode)
+	assert.Equal(ContentTypeApplicationJSON, res.Header.Get(HeaderContentType))
+
+	res, err = MockGet(app, "/api/v2/nope").DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusGone, res.StatusCode)
+
+	res, err = MockGet(app, "/apiary").DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusTeapot, res.StatusCode)
+}
+
+func TestGroupMethodNotAllowed(t *testing.T) {
+	assert := assert.New(t)
+
+	app := MustNew()
+	app.Config.HandleMethodNotAllowed = true
+	api := app.Group("/api")
+	api.GET("/users", ok)
+	api.MethodNotAllowed(func(_ *Ctx) Result { return JSON.Status(http.StatusMethodNotAllowed) })
+
+	res, err := MockMethod(app, "DELETE", "/api/users").DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusMethodNotAllowed, res.StatusCode)
+	assert.Equal(ContentTypeApplicationJSON, res.Header.Get(HeaderContentType))
+	assert.Equal("GET, OPTIONS", res.Header.Get(HeaderAllow))
+}
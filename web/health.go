@@ -0,0 +1,141 @@
+package web
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Health statuses.
+const (
+	HealthStatusOK       = "ok"
+	HealthStatusDegraded = "degraded"
+	HealthStatusFailed   = "failed"
+)
+
+// Health probe paths.
+const (
+	HealthPathHealthz = "/healthz"
+	HealthPathReadyz  = "/readyz"
+	HealthPathLivez   = "/livez"
+)
+
+var (
+	_ Controller = (*Health)(nil)
+)
+
+// NewHealth returns a new health controller for a given set of checks.
+/*
+Usage:
+
+	app.Register(web.NewHealth(
+		web.NewHealthCheck("db", web.HealthCheckPing(conn.Connection)),
+		web.NewHealthCheck("vault", secrets.HealthCheck(client, "ping"), web.OptHealthCheckCritical(false)),
+	))
+*/
+func NewHealth(checks ...*HealthCheck) *Health {
+	return &Health{
+		Checks: checks,
+	}
+}
+
+// Health is a registry of health checks that serves readiness and liveness probes.
+//
+// Readiness probes (`/healthz` and `/readyz`) run every check; liveness probes (`/livez`)
+// only run checks marked with `OptHealthCheckLiveness`. A failing critical check
+// fails the probe with a 503, a failing non-critical check degrades the probe but still returns a 200.
+type Health struct {
+	sync.Mutex
+	Checks []*HealthCheck
+}
+
+// HealthStatus is the overall result of a set of health checks.
+type HealthStatus struct {
+	Status     string                       `json:"status"`
+	Checks     map[string]HealthCheckResult `json:"checks"`
+	CheckedUTC time.Time                    `json:"checkedUTC"`
+}
+
+// StatusCode returns the http status code for the health status.
+func (hs HealthStatus) StatusCode() int {
+	if hs.Status == HealthStatusFailed {
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusOK
+}
+
+// Add adds checks to the registry.
+func (h *Health) Add(checks ...*HealthCheck) {
+	h.Lock()
+	defer h.Unlock()
+	h.Checks = append(h.Checks, checks...)
+}
+
+// Register registers the health probe routes with an app.
+func (h *Health) Register(app *App) {
+	app.GET(HealthPathHealthz, h.Readiness)
+	app.GET(HealthPathReadyz, h.Readiness)
+	app.GET(HealthPathLivez, h.Liveness)
+}
+
+// Readiness runs all the checks and returns the health status.
+func (h *Health) Readiness(r *Ctx) Result {
+	return h.result(h.Check(r.Context(), false))
+}
+
+// Liveness runs the liveness checks and returns the health status.
+func (h *Health) Liveness(r *Ctx) Result {
+	return h.result(h.Check(r.Context(), true))
+}
+
+// Check runs the checks concurrently and returns the overall status.
+// If `livenessOnly` is set, only checks marked as liveness checks are run.
+func (h *Health) Check(ctx context.Context, livenessOnly bool) HealthStatus {
+	h.Lock()
+	checks := make([]*HealthCheck, 0, len(h.Checks))
+	for _, check := range h.Checks {
+		if livenessOnly && !check.Liveness {
+			continue
+		}
+		checks = append(checks, check)
+	}
+	h.Unlock()
+
+	results := make([]HealthCheckResult, len(checks))
+	wg := sync.WaitGroup{}
+	wg.Add(len(checks))
+	for index, check := range checks {
+		go func(index int, check *HealthCheck) {
+			defer wg.Done()
+			results[index] = check.Run(ctx)
+		}(index, check)
+	}
+	wg.Wait()
+
+	status := HealthStatus{
+		Status:     HealthStatusOK,
+		Checks:     make(map[string]HealthCheckResult, len(checks)),
+		CheckedUTC: time.Now().UTC(),
+	}
+	for index, check := range checks {
+		result := results[index]
+		status.Checks[check.Name] = result
+		if result.Status == HealthStatusOK {
+			continue
+		}
+		if result.Critical {
+			status.Status = HealthStatusFailed
+		} else if status.Status == HealthStatusOK {
+			status.Status = HealthStatusDegraded
+		}
+	}
+	return status
+}
+
+func (h *Health) result(status HealthStatus) Result {
+	return &JSONResult{
+		StatusCode: status.StatusCode(),
+		Response:   status,
+	}
+}
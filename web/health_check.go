@@ -0,0 +1,147 @@
+package web
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/blend/go-sdk/ex"
+)
+
+// HealthCheckFunc is a function that returns an error if a dependency is unhealthy.
+type HealthCheckFunc func(context.Context) error
+
+// NewHealthCheck returns a new health check.
+// Health checks are critical by default, and have a default timeout of `DefaultHealthCheckTimeout`.
+func NewHealthCheck(name string, check HealthCheckFunc, options ...HealthCheckOption) *HealthCheck {
+	hc := &HealthCheck{
+		Name:     name,
+		Check:    check,
+		Timeout:  DefaultHealthCheckTimeout,
+		Critical: true,
+	}
+	for _, option := range options {
+		option(hc)
+	}
+	return hc
+}
+
+// HealthCheckOption is an option for health checks.
+type HealthCheckOption func(*HealthCheck)
+
+// OptHealthCheckTimeout sets the health check timeout.
+func OptHealthCheckTimeout(timeout time.Duration) HealthCheckOption {
+	return func(hc *HealthCheck) { hc.Timeout = timeout }
+}
+
+// OptHealthCheckCacheTTL sets how long a health check result is reused for.
+func OptHealthCheckCacheTTL(ttl time.Duration) HealthCheckOption {
+	return func(hc *HealthCheck) { hc.CacheTTL = ttl }
+}
+
+// OptHealthCheckCritical sets if a failing health check fails the overall status.
+func OptHealthCheckCritical(critical bool) HealthCheckOption {
+	return func(hc *HealthCheck) { hc.Critical = critical }
+}
+
+// OptHealthCheckLiveness sets if the health check is included in liveness probes.
+func OptHealthCheckLiveness(liveness bool) HealthCheckOption {
+	return func(hc *HealthCheck) { hc.Liveness = liveness }
+}
+
+// HealthCheck is a named health check.
+type HealthCheck struct {
+	// Name is the name of the check in the health status output.
+	Name string
+	// Check is the check function.
+	Check HealthCheckFunc
+	// Timeout bounds the time the check can take.
+	Timeout time.Duration
+	// CacheTTL is how long a result is reused before the check is run again.
+	// If unset, the check runs on every probe.
+	CacheTTL time.Duration
+	// Critical indicates a failure should fail the overall status.
+	// Non-critical failures only degrade the overall status.
+	Critical bool
+	// Liveness indicates the check should be included in liveness probes
+	// in addition to readiness probes.
+	Liveness bool
+
+	sync.Mutex
+	lastResult *HealthCheckResult
+}
+
+// HealthCheckResult is the result of a health check.
+type HealthCheckResult struct {
+	Status     string        `json:"status"`
+	Critical   bool          `json:"critical"`
+	Error      string        `json:"error,omitempty"`
+	Elapsed    time.Duration `json:"elapsed"`
+	CheckedUTC time.Time     `json:"checkedUTC"`
+}
+
+// Run runs the check, returning a cached result if one is available.
+func (hc *HealthCheck) Run(ctx context.Context) HealthCheckResult {
+	hc.Lock()
+	defer hc.Unlock()
+
+	now := time.Now().UTC()
+	if hc.lastResult != nil && hc.CacheTTL > 0 && now.Sub(hc.lastResult.CheckedUTC) < hc.CacheTTL {
+		return *hc.lastResult
+	}
+
+	if hc.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, hc.Timeout)
+		defer cancel()
+	}
+
+	result := HealthCheckResult{
+		Status:     HealthStatusOK,
+		Critical:   hc.Critical,
+		CheckedUTC: now,
+	}
+	if err := hc.safeCheck(ctx); err != nil {
+		result.Status = HealthStatusFailed
+		result.Error = err.Error()
+	}
+	result.Elapsed = time.Now().UTC().Sub(now)
+	hc.lastResult = &result
+	return result
+}
+
+// safeCheck runs the check, returning an error if it panics or exceeds the timeout.
+func (hc *HealthCheck) safeCheck(ctx context.Context) error {
+	errors := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				errors <- ex.New(r)
+			}
+		}()
+		errors <- hc.Check(ctx)
+	}()
+	select {
+	case err := <-errors:
+		return err
+	case <-ctx.Done():
+		return ex.New(ErrHealthCheckTimeout, ex.OptMessagef("health check: %s", hc.Name))
+	}
+}
+
+// Pinger is a type that can be pinged, e.g. a `*sql.DB`.
+type Pinger interface {
+	PingContext(context.Context) error
+}
+
+// HealthCheckPing returns a health check func that pings a dependency.
+/*
+Usage:
+
+	web.NewHealthCheck("db", web.HealthCheckPing(conn.Connection))
+*/
+func HealthCheckPing(pinger Pinger) HealthCheckFunc {
+	return func(ctx context.Context) error {
+		return pinger.PingContext(ctx)
+	}
+}
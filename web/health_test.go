@@ -0,0 +1,150 @@
+package web
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestHealthCheck(t *testing.T) {
+	assert := assert.New(t)
+
+	hc := NewHealthCheck("test", func(_ context.Context) error { return nil })
+	assert.True(hc.Critical)
+	assert.Equal(DefaultHealthCheckTimeout, hc.Timeout)
+
+	result := hc.Run(context.Background())
+	assert.Equal(HealthStatusOK, result.Status)
+	assert.Empty(result.Error)
+	assert.False(result.CheckedUTC.IsZero())
+
+	hc = NewHealthCheck("test", func(_ context.Context) error { return fmt.Errorf("test error") })
+	result = hc.Run(context.Background())
+	assert.Equal(HealthStatusFailed, result.Status)
+	assert.Equal("test error", result.Error)
+}
+
+func TestHealthCheckTimeout(t *testing.T) {
+	assert := assert.New(t)
+
+	hc := NewHealthCheck("test", func(ctx context.Context) error {
+		<-ctx.Done()
+		time.Sleep(time.Second)
+		return nil
+	}, OptHealthCheckTimeout(time.Millisecond))
+	result := hc.Run(context.Background())
+	assert.Equal(HealthStatusFailed, result.Status)
+	assert.Contains(result.Error, string(ErrHealthCheckTimeout))
+}
+
+func TestHealthCheckPanic(t *testing.T) {
+	assert := assert.New(t)
+
+	hc := NewHealthCheck("test", func(_ context.Context) error { panic("this is only a test") })
+	result := hc.Run(context.Background())
+	assert.Equal(HealthStatusFailed, result.Status)
+	assert.NotEmpty(result.Error)
+}
+
+func TestHealthCheckCacheTTL(t *testing.T) {
+	assert := assert.New(t)
+
+	var calls int32
+	hc := NewHealthCheck("test", func(_ context.Context) error {
+		atomic.AddInt32(&calls, 1)
+		return nil
+	}, OptHealthCheckCacheTTL(time.Hour))
+
+	hc.Run(context.Background())
+	hc.Run(context.Background())
+	assert.Equal(1, atomic.LoadInt32(&calls))
+
+	hc.CacheTTL = 0
+	hc.Run(context.Background())
+	assert.Equal(2, atomic.LoadInt32(&calls))
+}
+
+type mockPinger struct {
+	err error
+}
+
+func (mp mockPinger) PingContext(_ context.Context) error { return mp.err }
+
+func TestHealthCheckPing(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Nil(HealthCheckPing(mockPinger{})(context.Background()))
+	assert.NotNil(HealthCheckPing(mockPinger{err: fmt.Errorf("test error")})(context.Background()))
+}
+
+func TestHealthCheckStatus(t *testing.T) {
+	assert := assert.New(t)
+
+	healthy := NewHealthCheck("healthy", func(_ context.Context) error { return nil }, OptHealthCheckLiveness(true))
+	optional := NewHealthCheck("optional", func(_ context.Context) error { return fmt.Errorf("test error") }, OptHealthCheckCritical(false))
+	critical := NewHealthCheck("critical", func(_ context.Context) error { return fmt.Errorf("test error") })
+
+	health := NewHealth(healthy)
+	status := health.Check(context.Background(), false)
+	assert.Equal(HealthStatusOK, status.Status)
+	assert.Equal(http.StatusOK, status.StatusCode())
+
+	health.Add(optional)
+	status = health.Check(context.Background(), false)
+	assert.Equal(HealthStatusDegraded, status.Status)
+	assert.Equal(http.StatusOK, status.StatusCode())
+	assert.Len(status.Checks, 2)
+	assert.Equal(HealthStatusFailed, status.Checks["optional"].Status)
+
+	health.Add(critical)
+	status = health.Check(context.Background(), false)
+	assert.Equal(HealthStatusFailed, status.Status)
+	assert.Equal(http.StatusServiceUnavailable, status.StatusCode())
+
+	status = health.Check(context.Background(), true)
+	assert.Equal(HealthStatusOK, status.Status)
+	assert.Len(status.Checks, 1)
+}
+
+func TestHealthRegister(t *testing.T) {
+	assert := assert.New(t)
+
+	var healthy int32 = 1
+	app := MustNew()
+	app.Register(NewHealth(
+		NewHealthCheck("live", func(_ context.Context) error { return nil }, OptHealthCheckLiveness(true)),
+		NewHealthCheck("ready", func(_ context.Context) error {
+			if atomic.LoadInt32(&healthy) == 1 {
+				return nil
+			}
+			return fmt.Errorf("not ready")
+		}),
+	))
+
+	var status HealthStatus
+	meta, err := MockGet(app, HealthPathReadyz).JSONWithResponse(&status)
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, meta.StatusCode)
+	assert.Equal(HealthStatusOK, status.Status)
+	assert.Len(status.Checks, 2)
+
+	atomic.StoreInt32(&healthy, 0)
+
+	status = HealthStatus{}
+	meta, err = MockGet(app, HealthPathHealthz).JSONWithResponse(&status)
+	assert.Nil(err)
+	assert.Equal(http.StatusServiceUnavailable, meta.StatusCode)
+	assert.Equal(HealthStatusFailed, status.Status)
+	assert.Equal("not ready", status.Checks["ready"].Error)
+
+	status = HealthStatus{}
+	meta, err = MockGet(app, HealthPathLivez).JSONWithResponse(&status)
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, meta.StatusCode)
+	assert.Len(status.Checks, 1)
+}
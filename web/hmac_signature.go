@@ -0,0 +1,133 @@
+package web
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/blend/go-sdk/cache"
+	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/webutil"
+)
+
+// HMACSignatureRequired returns a middleware that verifies requests are signed with one of the given keys,
+// e.g. incoming webhooks, using the `X-Signature` and `X-Signature-Timestamp` headers
+// set by `r2.OptHMACSignature`.
+/*
+Requests with a missing or invalid signature, a timestamp outside of the tolerance, or a signature
+that was already used, get a 401:
+
+	app.POST("/webhooks/partner", handler, web.HMACSignatureRequired(web.OptHMACSignatureKeys(currentKey, previousKey)))
+
+The post body is read to verify the signature, and is available to the action with `ctx.PostBody()`.
+*/
+func HMACSignatureRequired(options ...HMACSignatureOption) Middleware {
+	return NewHMACSignatureVerifier(options...).Middleware
+}
+
+// NewHMACSignatureVerifier returns a new hmac signature verifier.
+func NewHMACSignatureVerifier(options ...HMACSignatureOption) *HMACSignatureVerifier {
+	hsv := &HMACSignatureVerifier{
+		Tolerance: webutil.DefaultSignatureTolerance,
+	}
+	for _, option := range options {
+		option(hsv)
+	}
+	if hsv.Seen == nil {
+		seen := cache.NewLocalCache()
+		go seen.Start()
+		hsv.Seen = seen
+	}
+	return hsv
+}
+
+// HMACSignatureOption is an option for hmac signature verifiers.
+type HMACSignatureOption func(*HMACSignatureVerifier)
+
+// OptHMACSignatureKeys sets the keys signatures are verified with.
+// Multiple keys can be set to rotate keys without downtime.
+func OptHMACSignatureKeys(keys ...[]byte) HMACSignatureOption {
+	return func(hsv *HMACSignatureVerifier) { hsv.Keys = keys }
+}
+
+// OptHMACSignatureTolerance sets the maximum difference between the signature timestamp and the current time.
+func OptHMACSignatureTolerance(tolerance time.Duration) HMACSignatureOption {
+	return func(hsv *HMACSignatureVerifier) { hsv.Tolerance = tolerance }
+}
+
+// OptHMACSignatureSeen sets the store of signatures that have been used, which replayed requests are rejected with.
+// Use a shared store, e.g. backed by a database, if requests are verified by several replicas.
+func OptHMACSignatureSeen(seen cache.Cache) HMACSignatureOption {
+	return func(hsv *HMACSignatureVerifier) { hsv.Seen = seen }
+}
+
+// OptHMACSignatureInvalid sets the action to run when a request signature is not valid.
+func OptHMACSignatureInvalid(action Action) HMACSignatureOption {
+	return func(hsv *HMACSignatureVerifier) { hsv.Invalid = action }
+}
+
+// HMACSignatureVerifier verifies hmac signed requests.
+type HMACSignatureVerifier struct {
+	Keys      [][]byte
+	Tolerance time.Duration
+	// Seen holds the signatures of verified requests until their timestamps are outside of the tolerance,
+	// so a captured request cannot be replayed; it defaults to a local cache.
+	// Identical requests signed within the same second have the same signature, so senders that make them
+	// should vary the request, e.g. with a request id in the body.
+	Seen cache.Cache
+	// Invalid is the action to run for requests with invalid signatures;
+	// if unset a not authorized result is returned from the default provider.
+	Invalid Action
+}
+
+// ToleranceOrDefault returns the tolerance or a default.
+func (hsv *HMACSignatureVerifier) ToleranceOrDefault() time.Duration {
+	if hsv.Tolerance > 0 {
+		return hsv.Tolerance
+	}
+	return webutil.DefaultSignatureTolerance
+}
+
+// Verify verifies the signature of a request, and that the signature has not been used before.
+func (hsv *HMACSignatureVerifier) Verify(ctx *Ctx) error {
+	body, err := ctx.PostBody()
+	if err != nil {
+		return err
+	}
+	tolerance := hsv.ToleranceOrDefault()
+	if err := webutil.VerifyRequestSignature(ctx.Request, body, time.Now().UTC(), tolerance, hsv.Keys...); err != nil {
+		return err
+	}
+	if hsv.Seen == nil {
+		return nil
+	}
+	// a timestamp can be ahead of the current time by the tolerance, so the signature is valid for twice the tolerance.
+	signature := strings.ToLower(ctx.Request.Header.Get(webutil.HeaderXSignature))
+	_, seen, err := hsv.Seen.GetOrSet(signature, func() (interface{}, error) { return true, nil }, cache.OptValueTTL(2*tolerance))
+	if err != nil {
+		return err
+	}
+	if seen {
+		return ex.New(webutil.ErrSignatureReplayed)
+	}
+	return nil
+}
+
+// Middleware implements Middleware.
+func (hsv *HMACSignatureVerifier) Middleware(action Action) Action {
+	return func(ctx *Ctx) Result {
+		if err := hsv.Verify(ctx); err != nil {
+			if IsErrRequestBodyTooLarge(err) {
+				return ctx.DefaultProvider.Status(http.StatusRequestEntityTooLarge)
+			}
+			if !webutil.IsErrSignature(err) {
+				return ctx.DefaultProvider.BadRequest(err)
+			}
+			if hsv.Invalid != nil {
+				return hsv.Invalid(ctx)
+			}
+			return ctx.DefaultProvider.NotAuthorized()
+		}
+		return action(ctx)
+	}
+}
@@ -0,0 +1,103 @@
+package web
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/r2"
+	"github.com/blend/go-sdk/webutil"
+)
+
+func TestHMACSignatureRequired(t *testing.T) {
+	assert := assert.New(t)
+
+	key := []byte("current-key")
+	app := MustNew()
+	app.POST("/webhooks", func(ctx *Ctx) Result {
+		body, err := ctx.PostBody()
+		if err != nil {
+			return Text.InternalError(err)
+		}
+		return Text.Result(string(body))
+	}, HMACSignatureRequired(OptHMACSignatureKeys([]byte("next-key"), key)))
+
+	contents, res, err := MockPost(app, "/webhooks",
+		ioutil.NopCloser(bytes.NewBufferString(`{"event":"created"}`)),
+		r2.OptHMACSignature(key),
+	).BytesWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, res.StatusCode)
+	assert.Equal(`{"event":"created"}`, string(contents))
+
+	res, err = MockPost(app, "/webhooks",
+		ioutil.NopCloser(bytes.NewBufferString(`{"event":"created"}`)),
+		r2.OptHMACSignature([]byte("wrong-key")),
+	).DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusUnauthorized, res.StatusCode)
+
+	res, err = MockPost(app, "/webhooks",
+		ioutil.NopCloser(bytes.NewBufferString(`{"event":"created"}`)),
+	).DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusUnauthorized, res.StatusCode)
+}
+
+func TestHMACSignatureRequiredReplayed(t *testing.T) {
+	assert := assert.New(t)
+
+	key := []byte("current-key")
+	app := MustNew()
+	app.POST("/webhooks", ok, HMACSignatureRequired(OptHMACSignatureKeys(key), OptHMACSignatureTolerance(0)))
+
+	body := []byte(`{"event":"created"}`)
+	req, err := http.NewRequest(http.MethodPost, "/webhooks", nil)
+	assert.Nil(err)
+	webutil.SignRequest(req, key, body, time.Now().UTC())
+	signature := req.Header.Get(webutil.HeaderXSignature)
+
+	res, err := MockPost(app, "/webhooks", ioutil.NopCloser(bytes.NewReader(body)), r2.OptHeader(req.Header)).DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, res.StatusCode)
+
+	// the same signed request is rejected, however its signature is encoded.
+	res, err = MockPost(app, "/webhooks", ioutil.NopCloser(bytes.NewReader(body)), r2.OptHeader(req.Header)).DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusUnauthorized, res.StatusCode)
+	res, err = MockPost(app, "/webhooks", ioutil.NopCloser(bytes.NewReader(body)),
+		r2.OptHeaderValue(webutil.HeaderXSignatureTimestamp, req.Header.Get(webutil.HeaderXSignatureTimestamp)),
+		r2.OptHeaderValue(webutil.HeaderXSignature, strings.ToUpper(signature)),
+	).DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusUnauthorized, res.StatusCode)
+
+	// a tolerance that is not positive uses the default, rather than accepting any timestamp.
+	req, err = http.NewRequest(http.MethodPost, "/webhooks", nil)
+	assert.Nil(err)
+	webutil.SignRequest(req, key, body, time.Now().UTC().Add(-time.Hour))
+	res, err = MockPost(app, "/webhooks", ioutil.NopCloser(bytes.NewReader(body)), r2.OptHeader(req.Header)).DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusUnauthorized, res.StatusCode)
+}
+
+func TestHMACSignatureRequiredInvalid(t *testing.T) {
+	assert := assert.New(t)
+
+	app := MustNew()
+	app.POST("/webhooks", ok, HMACSignatureRequired(
+		OptHMACSignatureKeys([]byte("current-key")),
+		OptHMACSignatureInvalid(func(_ *Ctx) Result { return JSON.Status(http.StatusForbidden) }),
+	))
+
+	res, err := MockPost(app, "/webhooks",
+		ioutil.NopCloser(bytes.NewBufferString(`{"event":"created"}`)),
+		r2.OptHMACSignature([]byte("wrong-key")),
+	).DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusForbidden, res.StatusCode)
+}
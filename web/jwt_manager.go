@@ -2,6 +2,8 @@ package web
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/rsa"
 	"time"
 
 	"github.com/blend/go-sdk/ex"
@@ -11,6 +13,9 @@ import (
 const (
 	// ErrJWTNonstandardClaims can be returned by the jwt manager keyfunc.
 	ErrJWTNonstandardClaims = ex.Class("jwt; invalid claims object; should be standard claims")
+
+	// DefaultJWTIssuer is the default issuer for session tokens.
+	DefaultJWTIssuer = "go-web"
 )
 
 // NewJWTManager returns a new jwt manager from a key.
@@ -22,21 +27,92 @@ func NewJWTManager(key []byte) *JWTManager {
 	}
 }
 
+// NewJWTManagerFromKeys returns a new jwt manager that signs and verifies tokens with
+// a given set of keys. The first key is used to sign new tokens; all the keys are used
+// to verify tokens, selected by the token `kid` header.
+func NewJWTManagerFromKeys(keys []JWTKey, options ...JWTManagerOption) *JWTManager {
+	jwtm := &JWTManager{
+		Keys: keys,
+	}
+	for _, option := range options {
+		option(jwtm)
+	}
+	return jwtm
+}
+
+// JWTManagerOption is an option for jwt managers.
+type JWTManagerOption func(*JWTManager)
+
+// OptJWTManagerIssuer sets the issuer that is written to and required of tokens.
+func OptJWTManagerIssuer(issuer string) JWTManagerOption {
+	return func(jwtm *JWTManager) { jwtm.Issuer = issuer }
+}
+
+// OptJWTManagerAudience sets the audience that is written to and required of tokens.
+func OptJWTManagerAudience(audience string) JWTManagerOption {
+	return func(jwtm *JWTManager) { jwtm.Audience = audience }
+}
+
+// JWTKey is a signing key for a jwt manager.
+type JWTKey struct {
+	// ID is the key id, written to the `kid` header of tokens signed with the key.
+	ID string
+	// Method is the signing method, e.g. `jwt.SigningMethodRS256` or `jwt.SigningMethodES256`.
+	Method jwt.SigningMethod
+	// PrivateKey is the key used to sign tokens; it is a []byte for HMAC methods,
+	// an *rsa.PrivateKey for RSA methods, and an *ecdsa.PrivateKey for ECDSA methods.
+	// It can be unset for keys that are only used to verify tokens.
+	PrivateKey interface{}
+	// PublicKey is the key used to verify tokens; if unset it is derived from the private key.
+	PublicKey interface{}
+}
+
+// VerificationKey returns the key used to verify tokens.
+func (jk JWTKey) VerificationKey() interface{} {
+	if jk.PublicKey != nil {
+		return jk.PublicKey
+	}
+	switch typed := jk.PrivateKey.(type) {
+	case *rsa.PrivateKey:
+		return &typed.PublicKey
+	case *ecdsa.PrivateKey:
+		return &typed.PublicKey
+	default:
+		return jk.PrivateKey
+	}
+}
+
 // JWTManager is a manager for JWTs.
+//
+// By default it signs tokens with a single HMAC key from `KeyProvider`.
+// If `Keys` are set, tokens are signed with the first key and verified with any of the keys
+// based on the `kid` header, which allows keys to be rotated and, for asymmetric keys,
+// other services to verify tokens with the public keys served by `JWKSAction`.
 type JWTManager struct {
 	KeyProvider func(*Session) ([]byte, error)
+
+	Keys     []JWTKey
+	Issuer   string
+	Audience string
 }
 
 // Claims returns the sesion as a JWT standard claims object.
 func (jwtm JWTManager) Claims(session *Session) *jwt.StandardClaims {
-	return &jwt.StandardClaims{
+	claims := &jwt.StandardClaims{
 		ID:        session.SessionID,
 		Audience:  session.BaseURL,
-		Issuer:    "go-web",
+		Issuer:    DefaultJWTIssuer,
 		Subject:   session.UserID,
 		IssuedAt:  session.CreatedUTC.Unix(),
 		ExpiresAt: session.ExpiresUTC.Unix(),
 	}
+	if jwtm.Issuer != "" {
+		claims.Issuer = jwtm.Issuer
+	}
+	if jwtm.Audience != "" {
+		claims.Audience = jwtm.Audience
+	}
+	return claims
 }
 
 // FromClaims returns a session from a given claims set.
@@ -52,6 +128,14 @@ func (jwtm JWTManager) FromClaims(claims *jwt.StandardClaims) *Session {
 
 // KeyFunc is a shim function to get the key for a given token.
 func (jwtm JWTManager) KeyFunc(token *jwt.Token) (interface{}, error) {
+	if len(jwtm.Keys) > 0 {
+		key, err := jwtm.keyForToken(token)
+		if err != nil {
+			return nil, err
+		}
+		return key.VerificationKey(), nil
+	}
+
 	typed, ok := token.Claims.(*jwt.StandardClaims)
 	if !ok {
 		return nil, ErrJWTNonstandardClaims
@@ -61,6 +145,16 @@ func (jwtm JWTManager) KeyFunc(token *jwt.Token) (interface{}, error) {
 
 // SerializeSessionValueHandler is a shim to the auth manager.
 func (jwtm JWTManager) SerializeSessionValueHandler(_ context.Context, session *Session) (output string, err error) {
+	if len(jwtm.Keys) > 0 {
+		signingKey := jwtm.Keys[0]
+		token := jwt.NewWithClaims(signingKey.Method, jwtm.Claims(session))
+		if signingKey.ID != "" {
+			token.Header["kid"] = signingKey.ID
+		}
+		output, err = token.SignedString(signingKey.PrivateKey)
+		return
+	}
+
 	var key []byte
 	key, err = jwtm.KeyProvider(session)
 	if err != nil {
@@ -75,11 +169,76 @@ func (jwtm JWTManager) SerializeSessionValueHandler(_ context.Context, session *
 // ParseSessionValueHandler is a shim to the auth manager.
 func (jwtm JWTManager) ParseSessionValueHandler(_ context.Context, sessionValue string) (*Session, error) {
 	var claims jwt.StandardClaims
-	_, err := jwt.ParseWithClaims(sessionValue, &claims, jwtm.KeyFunc)
+	parser := jwt.Parser{
+		ValidMethods: jwtm.validMethods(),
+	}
+	_, err := parser.ParseWithClaims(sessionValue, &claims, jwtm.KeyFunc)
 	if err != nil {
 		return nil, err
 	}
-
-	// do we check if the token is valid ???
+	if jwtm.Issuer != "" && !claims.VerifyIssuer(jwtm.Issuer, true) {
+		return nil, ex.New(jwt.ErrValidation, ex.OptMessagef("invalid issuer: %s", claims.Issuer))
+	}
+	if jwtm.Audience != "" && !claims.VerifyAudience(jwtm.Audience, true) {
+		return nil, ex.New(jwt.ErrValidation, ex.OptMessagef("invalid audience: %s", claims.Audience))
+	}
 	return jwtm.FromClaims(&claims), nil
 }
+
+// JWKS returns the public keys as a JSON web key set.
+// Symmetric (HMAC) keys are never included.
+func (jwtm JWTManager) JWKS() (output jwt.JWKS, err error) {
+	output.Keys = []jwt.JWK{}
+	for _, key := range jwtm.Keys {
+		verificationKey := key.VerificationKey()
+		if _, isSymmetric := verificationKey.([]byte); isSymmetric {
+			continue
+		}
+		var jwk jwt.JWK
+		jwk, err = jwt.NewJWK(key.ID, key.Method.Alg(), verificationKey)
+		if err != nil {
+			return
+		}
+		output.Keys = append(output.Keys, jwk)
+	}
+	return
+}
+
+// JWKSAction is an action that returns the public keys as a JSON web key set.
+/*
+Usage:
+
+	app.GET("/.well-known/jwks.json", jwtManager.JWKSAction)
+*/
+func (jwtm JWTManager) JWKSAction(_ *Ctx) Result {
+	jwks, err := jwtm.JWKS()
+	if err != nil {
+		return JSON.InternalError(err)
+	}
+	return JSON.Result(jwks)
+}
+
+func (jwtm JWTManager) keyForToken(token *jwt.Token) (JWTKey, error) {
+	kid, _ := token.Header["kid"].(string)
+	for _, key := range jwtm.Keys {
+		if key.ID != kid {
+			continue
+		}
+		if token.Method == nil || token.Method.Alg() != key.Method.Alg() {
+			return JWTKey{}, ex.New(jwt.ErrValidation, ex.OptInner(jwt.ErrInvalidSigningMethod))
+		}
+		return key, nil
+	}
+	return JWTKey{}, ex.New(jwt.ErrValidation, ex.OptInner(ex.New(jwt.ErrJWKNotFound, ex.OptMessagef("kid: %s", kid))))
+}
+
+func (jwtm JWTManager) validMethods() []string {
+	if len(jwtm.Keys) == 0 {
+		return nil
+	}
+	var methods []string
+	for _, key := range jwtm.Keys {
+		methods = append(methods, key.Method.Alg())
+	}
+	return methods
+}
@@ -1,6 +1,11 @@
 package web
 
 import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
 	"testing"
 	"time"
 
@@ -122,3 +127,99 @@ func TestNewJWTManagerSerialization(t *testing.T) {
 	assert.False(parsed.CreatedUTC.IsZero())
 	assert.False(parsed.ExpiresUTC.IsZero())
 }
+
+func TestJWTManagerFromKeys(t *testing.T) {
+	assert := assert.New(t)
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(err)
+
+	oldKey := JWTKey{ID: "old", Method: jwt.SigningMethodRS256, PrivateKey: rsaKey}
+	newKey := JWTKey{ID: "new", Method: jwt.SigningMethodES256, PrivateKey: ecKey}
+
+	session := &Session{
+		SessionID:  uuid.V4().String(),
+		UserID:     uuid.V4().String(),
+		CreatedUTC: time.Now().UTC(),
+		ExpiresUTC: time.Now().UTC().Add(time.Hour),
+	}
+
+	before := NewJWTManagerFromKeys([]JWTKey{oldKey}, OptJWTManagerIssuer("test-issuer"), OptJWTManagerAudience("test-audience"))
+	oldToken, err := before.SerializeSessionValueHandler(context.Background(), session)
+	assert.Nil(err)
+
+	after := NewJWTManagerFromKeys([]JWTKey{newKey, oldKey}, OptJWTManagerIssuer("test-issuer"), OptJWTManagerAudience("test-audience"))
+	newToken, err := after.SerializeSessionValueHandler(context.Background(), session)
+	assert.Nil(err)
+
+	parsed, err := after.ParseSessionValueHandler(context.Background(), oldToken)
+	assert.Nil(err)
+	assert.Equal(session.SessionID, parsed.SessionID)
+	assert.Equal(session.UserID, parsed.UserID)
+
+	parsed, err = after.ParseSessionValueHandler(context.Background(), newToken)
+	assert.Nil(err)
+	assert.Equal(session.SessionID, parsed.SessionID)
+
+	_, err = before.ParseSessionValueHandler(context.Background(), newToken)
+	assert.True(IsErrSessionInvalid(err))
+
+	otherAudience := NewJWTManagerFromKeys([]JWTKey{newKey}, OptJWTManagerIssuer("test-issuer"), OptJWTManagerAudience("other-audience"))
+	_, err = otherAudience.ParseSessionValueHandler(context.Background(), newToken)
+	assert.True(IsErrSessionInvalid(err))
+
+	otherIssuer := NewJWTManagerFromKeys([]JWTKey{newKey}, OptJWTManagerIssuer("other-issuer"))
+	_, err = otherIssuer.ParseSessionValueHandler(context.Background(), newToken)
+	assert.True(IsErrSessionInvalid(err))
+}
+
+func TestJWTManagerFromKeysAlgorithmMismatch(t *testing.T) {
+	assert := assert.New(t)
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(err)
+	hmacKey := crypto.MustCreateKey(32)
+
+	m := NewJWTManagerFromKeys([]JWTKey{{ID: "rsa", Method: jwt.SigningMethodRS256, PrivateKey: rsaKey}})
+
+	// a token signed with a different algorithm but the same kid is rejected.
+	token := jwt.NewWithClaims(jwt.SigningMethodHMAC256, &jwt.StandardClaims{Subject: "example-string", ID: "session"})
+	token.Header["kid"] = "rsa"
+	forged, err := token.SignedString(hmacKey)
+	assert.Nil(err)
+
+	_, err = m.ParseSessionValueHandler(context.Background(), forged)
+	assert.True(IsErrSessionInvalid(err))
+}
+
+func TestJWTManagerJWKSAction(t *testing.T) {
+	assert := assert.New(t)
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(err)
+
+	m := NewJWTManagerFromKeys([]JWTKey{
+		{ID: "rsa", Method: jwt.SigningMethodRS256, PrivateKey: rsaKey},
+		{ID: "ec", Method: jwt.SigningMethodES256, PrivateKey: ecKey},
+		{ID: "hmac", Method: jwt.SigningMethodHMAC512, PrivateKey: crypto.MustCreateKey(32)},
+	})
+
+	app := MustNew()
+	app.GET("/.well-known/jwks.json", m.JWKSAction)
+
+	var jwks jwt.JWKS
+	assert.Nil(MockGet(app, "/.well-known/jwks.json").JSON(&jwks))
+	assert.Len(jwks.Keys, 2)
+
+	session := NewSession("example-string", NewSessionID())
+	session.ExpiresUTC = time.Now().UTC().Add(time.Hour)
+	token, err := m.SerializeSessionValueHandler(context.Background(), session)
+	assert.Nil(err)
+	parsed, err := jwt.Parse(token, jwks.Keyfunc)
+	assert.Nil(err)
+	assert.True(parsed.Valid)
+}
@@ -0,0 +1,103 @@
+package web
+
+import (
+	"mime"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// AcceptProviderAsDefault sets the context.DefaultProvider based on the request `Accept` header.
+// It chooses among the JSON, XML, Text and View providers, falling back to the
+// existing default provider if the header is missing or does not match any of them.
+func AcceptProviderAsDefault(action Action) Action {
+	return func(ctx *Ctx) Result {
+		ctx.Response.Header().Add(HeaderVary, HeaderAccept)
+		if provider := NegotiateProvider(ctx); provider != nil {
+			ctx.DefaultProvider = provider
+		}
+		return action(ctx)
+	}
+}
+
+// NegotiateProvider returns the result provider that best matches the request `Accept` header.
+// It returns nil if the header is unset or no provider matches.
+//
+// `text/html` and `application/xhtml+xml` select the context views, `application/json` selects JSON,
+// `application/xml` and `text/xml` select XML, and `text/plain` selects Text.
+func NegotiateProvider(ctx *Ctx) ResultProvider {
+	if ctx.Request == nil {
+		return nil
+	}
+	for _, mediaType := range ParseAccept(ctx.Request.Header.Get(HeaderAccept)) {
+		switch mediaType {
+		case "*/*":
+			return nil
+		case "application/json", "application/*":
+			return JSON
+		case "application/xml", "text/xml":
+			return XML
+		case "text/plain":
+			return Text
+		case "text/html", "application/xhtml+xml", "text/*":
+			if ctx.Views != nil {
+				return ctx.Views
+			}
+			if mediaType == "text/*" {
+				return Text
+			}
+		}
+	}
+	return nil
+}
+
+// ParseAccept parses an `Accept` header value into a list of media types ordered by preference.
+// Media types with a quality of zero are omitted; ties are broken by specificity, then header order.
+func ParseAccept(header string) []string {
+	type acceptValue struct {
+		MediaType   string
+		Quality     float64
+		Specificity int
+	}
+
+	var values []acceptValue
+	for _, part := range strings.Split(header, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		mediaType, params, err := mime.ParseMediaType(part)
+		if err != nil {
+			continue
+		}
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			if quality, err = strconv.ParseFloat(q, 64); err != nil {
+				continue
+			}
+		}
+		if quality <= 0 {
+			continue
+		}
+		specificity := 2
+		if mediaType == "*/*" {
+			specificity = 0
+		} else if strings.HasSuffix(mediaType, "/*") {
+			specificity = 1
+		}
+		values = append(values, acceptValue{MediaType: mediaType, Quality: quality, Specificity: specificity})
+	}
+
+	sort.SliceStable(values, func(i, j int) bool {
+		if values[i].Quality != values[j].Quality {
+			return values[i].Quality > values[j].Quality
+		}
+		return values[i].Specificity > values[j].Specificity
+	})
+
+	output := make([]string, len(values))
+	for index, value := range values {
+		output[index] = value.MediaType
+	}
+	return output
+}
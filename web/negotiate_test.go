@@ -0,0 +1,59 @@
+package web
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/r2"
+)
+
+func TestParseAccept(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Empty(ParseAccept(""))
+	assert.Equal([]string{"application/json"}, ParseAccept("application/json"))
+	assert.Equal(
+		[]string{"text/html", "application/xhtml+xml", "application/xml", "*/*"},
+		ParseAccept("text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8"),
+	)
+	assert.Equal([]string{"text/plain", "text/*"}, ParseAccept("text/*, text/plain, application/json;q=0"))
+}
+
+func TestNegotiateProvider(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Nil(NegotiateProvider(MockCtx("GET", "/")))
+	assert.Equal(JSON, NegotiateProvider(MockCtx("GET", "/", OptCtxHeaderValue(HeaderAccept, "application/json"))))
+	assert.Equal(XML, NegotiateProvider(MockCtx("GET", "/", OptCtxHeaderValue(HeaderAccept, "text/xml"))))
+	assert.Equal(Text, NegotiateProvider(MockCtx("GET", "/", OptCtxHeaderValue(HeaderAccept, "text/plain"))))
+	assert.Nil(NegotiateProvider(MockCtx("GET", "/", OptCtxHeaderValue(HeaderAccept, "*/*"))))
+	assert.Equal(XML, NegotiateProvider(MockCtx("GET", "/", OptCtxHeaderValue(HeaderAccept, "text/html, application/xml;q=0.5"))))
+
+	views := NewViewCache()
+	assert.Equal(views, NegotiateProvider(MockCtx("GET", "/", OptCtxViews(views), OptCtxHeaderValue(HeaderAccept, "text/html, application/xml;q=0.5"))))
+}
+
+func TestAcceptProviderAsDefault(t *testing.T) {
+	assert := assert.New(t)
+
+	app := MustNew(OptUse(AcceptProviderAsDefault))
+	app.GET("/", func(ctx *Ctx) Result {
+		return ctx.DefaultProvider.NotFound()
+	})
+
+	res, err := MockGet(app, "/", r2.OptHeaderValue(HeaderAccept, "application/json")).DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusNotFound, res.StatusCode)
+	assert.Equal(ContentTypeApplicationJSON, res.Header.Get(HeaderContentType))
+	assert.Equal(HeaderAccept, res.Header.Get(HeaderVary))
+
+	res, err = MockGet(app, "/", r2.OptHeaderValue(HeaderAccept, "application/xml")).DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusNotFound, res.StatusCode)
+	assert.Equal(ContentTypeXML, res.Header.Get(HeaderContentType))
+
+	res, err = MockGet(app, "/", r2.OptHeaderValue(HeaderAccept, "text/plain")).DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(ContentTypeText, res.Header.Get(HeaderContentType))
+}
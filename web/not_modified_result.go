@@ -0,0 +1,20 @@
+package web
+
+import "net/http"
+
+var (
+	// NotModified is a static result.
+	NotModified NotModifiedResult
+)
+
+// NotModifiedResult returns a not modified response.
+type NotModifiedResult struct{}
+
+// Render renders a static result.
+func (nmr NotModifiedResult) Render(ctx *Ctx) error {
+	header := ctx.Response.Header()
+	header.Del(HeaderContentType)
+	header.Del(HeaderContentLength)
+	ctx.Response.WriteHeader(http.StatusNotModified)
+	return nil
+}
@@ -0,0 +1,147 @@
+package web
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/blend/go-sdk/webutil"
+)
+
+// RateLimitKeyProvider returns the key a request is rate limited by.
+// If the returned key is empty the request is not rate limited.
+type RateLimitKeyProvider func(*Ctx) string
+
+// RateLimitKeyRemoteAddr is a key provider that limits by the client ip.
+func RateLimitKeyRemoteAddr(ctx *Ctx) string {
+	return webutil.GetRemoteAddr(ctx.Request)
+}
+
+// RateLimitKeySessionUserID is a key provider that limits by the session user id,
+// falling back to the client ip for requests without a session.
+// It should be used after `SessionAware` or `SessionRequired`.
+func RateLimitKeySessionUserID(ctx *Ctx) string {
+	if ctx.Session != nil && ctx.Session.UserID != "" {
+		return "user:" + ctx.Session.UserID
+	}
+	return RateLimitKeyRemoteAddr(ctx)
+}
+
+// RateLimit returns a middleware that rate limits requests.
+/*
+By default requests are limited per client ip to 60 requests per minute with a token bucket
+held in process. The algorithms are implemented in this package, rather than with `collections.RateLimiter`,
+so their state can be kept in a store. To share limits across instances provide a shared store:
+
+	app.Use(web.RateLimit(
+		web.OptRateLimitAlgorithm(web.SlidingWindow{Limit: 100, Window: time.Minute}),
+		web.OptRateLimitStore(webdb.NewRateLimitStore(conn)),
+	))
+*/
+func RateLimit(options ...RateLimiterOption) Middleware {
+	return NewRateLimiter(options...).Middleware
+}
+
+// NewRateLimiter returns a new rate limiter.
+func NewRateLimiter(options ...RateLimiterOption) *RateLimiter {
+	rl := &RateLimiter{
+		Algorithm:   TokenBucket{Limit: DefaultRateLimit, Period: DefaultRateLimitPeriod},
+		Store:       NewLocalRateLimitStore(),
+		KeyProvider: RateLimitKeyRemoteAddr,
+	}
+	for _, option := range options {
+		option(rl)
+	}
+	return rl
+}
+
+// RateLimiterOption is an option for rate limiters.
+type RateLimiterOption func(*RateLimiter)
+
+// OptRateLimitAlgorithm sets the rate limit algorithm.
+func OptRateLimitAlgorithm(algorithm RateLimitAlgorithm) RateLimiterOption {
+	return func(rl *RateLimiter) { rl.Algorithm = algorithm }
+}
+
+// OptRateLimitStore sets the rate limit state store.
+func OptRateLimitStore(store RateLimitStore) RateLimiterOption {
+	return func(rl *RateLimiter) { rl.Store = store }
+}
+
+// OptRateLimitKeyProvider sets the key provider.
+func OptRateLimitKeyProvider(keyProvider RateLimitKeyProvider) RateLimiterOption {
+	return func(rl *RateLimiter) { rl.KeyProvider = keyProvider }
+}
+
+// OptRateLimitKeyPrefix sets a prefix for keys, allowing multiple limiters to share a store.
+func OptRateLimitKeyPrefix(prefix string) RateLimiterOption {
+	return func(rl *RateLimiter) { rl.KeyPrefix = prefix }
+}
+
+// OptRateLimitExceeded sets the action to run when a request is rate limited.
+func OptRateLimitExceeded(action Action) RateLimiterOption {
+	return func(rl *RateLimiter) { rl.Exceeded = action }
+}
+
+// RateLimiter limits requests per key.
+//
+// It does not use `collections.RateLimiter`, which keeps a timestamp per allowed request for every key
+// in an unsynchronized map, cannot share limits across instances, and does not report the remaining
+// requests or the reset time needed for the rate limit headers. Instead algorithms keep a small
+// `RateLimitState` per key that can be persisted in a `RateLimitStore`.
+type RateLimiter struct {
+	Algorithm   RateLimitAlgorithm
+	Store       RateLimitStore
+	KeyProvider RateLimitKeyProvider
+	KeyPrefix   string
+	// Exceeded is the action to run for limited requests;
+	// if unset a 429 status result is returned from the default provider.
+	Exceeded Action
+}
+
+// Check takes from the limit for a given key.
+func (rl *RateLimiter) Check(ctx *Ctx, key string) (result RateLimitResult, err error) {
+	now := time.Now().UTC()
+	err = rl.Store.Update(ctx.Context(), rl.KeyPrefix+key, func(state RateLimitState) RateLimitState {
+		state, result = rl.Algorithm.Take(state, now)
+		return state
+	})
+	return
+}
+
+// Middleware implements Middleware.
+func (rl *RateLimiter) Middleware(action Action) Action {
+	return func(ctx *Ctx) Result {
+		key := rl.KeyProvider(ctx)
+		if key == "" {
+			return action(ctx)
+		}
+		provider := ctx.DefaultProvider
+		if provider == nil {
+			provider = Text
+		}
+		result, err := rl.Check(ctx, key)
+		if err != nil {
+			return provider.InternalError(err)
+		}
+
+		header := ctx.Response.Header()
+		header.Set(HeaderRateLimitLimit, strconv.Itoa(result.Limit))
+		header.Set(HeaderRateLimitRemaining, strconv.Itoa(result.Remaining))
+		header.Set(HeaderRateLimitReset, formatRateLimitSeconds(result.Reset))
+		if !result.Allowed {
+			header.Set(HeaderRetryAfter, formatRateLimitSeconds(result.RetryAfter))
+			if rl.Exceeded != nil {
+				return rl.Exceeded(ctx)
+			}
+			return provider.Status(http.StatusTooManyRequests)
+		}
+		return action(ctx)
+	}
+}
+
+// formatRateLimitSeconds formats a duration as whole seconds, rounding up.
+func formatRateLimitSeconds(d time.Duration) string {
+	return strconv.FormatInt(int64(math.Ceil(d.Round(time.Millisecond).Seconds())), 10)
+}
@@ -0,0 +1,168 @@
+package web
+
+import (
+	"math"
+	"time"
+)
+
+var (
+	_ RateLimitAlgorithm = (*TokenBucket)(nil)
+	_ RateLimitAlgorithm = (*SlidingWindow)(nil)
+)
+
+// RateLimitState is the persisted state for a rate limit key.
+// The zero value represents a key that has not been seen.
+type RateLimitState struct {
+	// Count is the number of tokens available (token bucket) or
+	// the number of requests in the current window (sliding window).
+	Count float64
+	// Previous is the number of requests in the previous window (sliding window only).
+	Previous float64
+	// Timestamp is the last refill time (token bucket) or the current window start (sliding window).
+	Timestamp time.Time
+	// Expires is the time after which the state is equivalent to the zero value and can be discarded.
+	Expires time.Time
+}
+
+// IsZero returns if the state is unset.
+func (rls RateLimitState) IsZero() bool {
+	return rls.Timestamp.IsZero()
+}
+
+// RateLimitResult is the outcome of a rate limit check.
+type RateLimitResult struct {
+	// Allowed indicates if the request should proceed.
+	Allowed bool
+	// Limit is the maximum number of requests allowed per period.
+	Limit int
+	// Remaining is the number of requests remaining in the period.
+	Remaining int
+	// Reset is the time until the limit fully resets.
+	Reset time.Duration
+	// RetryAfter is the time until the next request would be allowed, if it was not allowed.
+	RetryAfter time.Duration
+}
+
+// RateLimitAlgorithm computes rate limit decisions from a stored state.
+type RateLimitAlgorithm interface {
+	Take(state RateLimitState, now time.Time) (RateLimitState, RateLimitResult)
+}
+
+// TokenBucket is a rate limit algorithm that allows bursts of up to `Limit` requests,
+// refilling at a rate of `Limit` tokens per `Period`.
+// Values that are not positive fall back to `DefaultRateLimit` and `DefaultRateLimitPeriod`.
+type TokenBucket struct {
+	Limit  int
+	Period time.Duration
+}
+
+// LimitOrDefault returns the limit or a default.
+func (tb TokenBucket) LimitOrDefault() int {
+	if tb.Limit > 0 {
+		return tb.Limit
+	}
+	return DefaultRateLimit
+}
+
+// PeriodOrDefault returns the period or a default.
+func (tb TokenBucket) PeriodOrDefault() time.Duration {
+	if tb.Period > 0 {
+		return tb.Period
+	}
+	return DefaultRateLimitPeriod
+}
+
+// Take implements RateLimitAlgorithm.
+func (tb TokenBucket) Take(state RateLimitState, now time.Time) (RateLimitState, RateLimitResult) {
+	limit := tb.LimitOrDefault()
+	capacity := float64(limit)
+	rate := capacity / float64(tb.PeriodOrDefault())
+
+	tokens := capacity
+	if !state.IsZero() {
+		elapsed := now.Sub(state.Timestamp)
+		if elapsed < 0 {
+			elapsed = 0
+		}
+		tokens = math.Min(capacity, state.Count+float64(elapsed)*rate)
+	}
+
+	result := RateLimitResult{Limit: limit}
+	if tokens >= 1 {
+		tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = time.Duration(math.Round((1 - tokens) / rate))
+	}
+	result.Remaining = int(math.Floor(tokens))
+	result.Reset = time.Duration(math.Round((capacity - tokens) / rate))
+
+	return RateLimitState{
+		Count:     tokens,
+		Timestamp: now,
+		Expires:   now.Add(result.Reset),
+	}, result
+}
+
+// SlidingWindow is a rate limit algorithm that allows `Limit` requests per `Window`,
+// weighting the previous window's count by how much of it overlaps the trailing window.
+// Values that are not positive fall back to `DefaultRateLimit` and `DefaultRateLimitPeriod`.
+type SlidingWindow struct {
+	Limit  int
+	Window time.Duration
+}
+
+// LimitOrDefault returns the limit or a default.
+func (sw SlidingWindow) LimitOrDefault() int {
+	if sw.Limit > 0 {
+		return sw.Limit
+	}
+	return DefaultRateLimit
+}
+
+// WindowOrDefault returns the window or a default.
+func (sw SlidingWindow) WindowOrDefault() time.Duration {
+	if sw.Window > 0 {
+		return sw.Window
+	}
+	return DefaultRateLimitPeriod
+}
+
+// Take implements RateLimitAlgorithm.
+func (sw SlidingWindow) Take(state RateLimitState, now time.Time) (RateLimitState, RateLimitResult) {
+	window := sw.WindowOrDefault()
+	windowStart := now.Truncate(window)
+	if !state.Timestamp.Equal(windowStart) {
+		if state.Timestamp.Equal(windowStart.Add(-window)) {
+			state.Previous = state.Count
+		} else {
+			state.Previous = 0
+		}
+		state.Count = 0
+		state.Timestamp = windowStart
+	}
+	state.Expires = windowStart.Add(2 * window)
+
+	limit := float64(sw.LimitOrDefault())
+	elapsed := now.Sub(windowStart)
+	weight := 1 - float64(elapsed)/float64(window)
+	estimated := state.Previous*weight + state.Count
+
+	result := RateLimitResult{
+		Limit: sw.LimitOrDefault(),
+		Reset: windowStart.Add(window).Sub(now),
+	}
+	if estimated+1 <= limit {
+		state.Count++
+		estimated++
+		result.Allowed = true
+	} else if state.Count+1 > limit || state.Previous == 0 {
+		result.RetryAfter = result.Reset
+	} else {
+		// the time into the window at which the weighted previous count leaves room for one more request.
+		allowedAt := time.Duration(float64(window) * (1 - (limit-state.Count-1)/state.Previous))
+		result.RetryAfter = allowedAt - elapsed
+	}
+	result.Remaining = int(math.Max(0, math.Floor(limit-estimated)))
+	return state, result
+}
@@ -0,0 +1,80 @@
+package web
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+var (
+	_ RateLimitStore = (*LocalRateLimitStore)(nil)
+)
+
+// RateLimitStore persists rate limit state so that limits can be shared.
+type RateLimitStore interface {
+	// Update atomically applies the update func to the state for a given key.
+	Update(ctx context.Context, key string, update func(RateLimitState) RateLimitState) error
+}
+
+// NewLocalRateLimitStore returns a new in-process rate limit store.
+func NewLocalRateLimitStore() *LocalRateLimitStore {
+	return &LocalRateLimitStore{
+		States: make(map[string]RateLimitState),
+	}
+}
+
+// LocalRateLimitStore is an in-process rate limit store.
+// Limits are not shared across instances.
+//
+// Expired states are swept as states are updated, at most once per sweep interval,
+// so memory use is bounded by the number of keys seen within the rate limit period.
+type LocalRateLimitStore struct {
+	sync.Mutex
+	States map[string]RateLimitState
+	// SweepInterval is the minimum interval between sweeps of expired states.
+	SweepInterval time.Duration
+
+	lastSweep time.Time
+}
+
+// SweepIntervalOrDefault returns the sweep interval or a default.
+func (lrs *LocalRateLimitStore) SweepIntervalOrDefault() time.Duration {
+	if lrs.SweepInterval > 0 {
+		return lrs.SweepInterval
+	}
+	return DefaultRateLimitSweepInterval
+}
+
+// Update implements RateLimitStore.
+func (lrs *LocalRateLimitStore) Update(_ context.Context, key string, update func(RateLimitState) RateLimitState) error {
+	lrs.Lock()
+	defer lrs.Unlock()
+	if lrs.States == nil {
+		lrs.States = make(map[string]RateLimitState)
+	}
+	if now := time.Now().UTC(); now.Sub(lrs.lastSweep) >= lrs.SweepIntervalOrDefault() {
+		lrs.sweep(now)
+	}
+	lrs.States[key] = update(lrs.States[key])
+	return nil
+}
+
+// Sweep removes expired states.
+// States are also swept as they are updated, so it does not need to be called to bound memory use.
+func (lrs *LocalRateLimitStore) Sweep(_ context.Context) error {
+	lrs.Lock()
+	defer lrs.Unlock()
+	lrs.sweep(time.Now().UTC())
+	return nil
+}
+
+// sweep removes states that expired before a given time.
+// It must be called while holding the lock.
+func (lrs *LocalRateLimitStore) sweep(now time.Time) {
+	for key, state := range lrs.States {
+		if !state.Expires.IsZero() && state.Expires.Before(now) {
+			delete(lrs.States, key)
+		}
+	}
+	lrs.lastSweep = now
+}
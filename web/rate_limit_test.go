@@ -0,0 +1,209 @@
+package web
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/r2"
+)
+
+func TestTokenBucket(t *testing.T) {
+	assert := assert.New(t)
+
+	tb := TokenBucket{Limit: 2, Period: 10 * time.Second}
+	now := time.Date(2019, 10, 10, 12, 00, 00, 00, time.UTC)
+
+	state, result := tb.Take(RateLimitState{}, now)
+	assert.True(result.Allowed)
+	assert.Equal(1, result.Remaining)
+	assert.Equal(5*time.Second, result.Reset)
+
+	state, result = tb.Take(state, now)
+	assert.True(result.Allowed)
+	assert.Zero(result.Remaining)
+
+	state, result = tb.Take(state, now.Add(time.Second))
+	assert.False(result.Allowed)
+	assert.Equal(4*time.Second, result.RetryAfter)
+
+	_, result = tb.Take(state, now.Add(5*time.Second))
+	assert.True(result.Allowed)
+}
+
+func TestRateLimitAlgorithmDefaults(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Date(2019, 10, 10, 12, 00, 00, 00, time.UTC)
+
+	// limits and periods that are not positive use the defaults, rather than dividing by zero.
+	state, result := TokenBucket{}.Take(RateLimitState{}, now)
+	assert.True(result.Allowed)
+	assert.Equal(DefaultRateLimit, result.Limit)
+	assert.Equal(DefaultRateLimit-1, result.Remaining)
+	assert.Equal(DefaultRateLimitPeriod/DefaultRateLimit, result.Reset)
+	_, result = TokenBucket{Limit: -1, Period: -time.Second}.Take(state, now.Add(time.Second))
+	assert.True(result.Allowed)
+
+	_, result = SlidingWindow{}.Take(RateLimitState{}, now)
+	assert.True(result.Allowed)
+	assert.Equal(DefaultRateLimit, result.Limit)
+	assert.Equal(DefaultRateLimit-1, result.Remaining)
+	assert.Equal(DefaultRateLimitPeriod, result.Reset)
+}
+
+func TestSlidingWindow(t *testing.T) {
+	assert := assert.New(t)
+
+	sw := SlidingWindow{Limit: 2, Window: time.Minute}
+	now := time.Date(2019, 10, 10, 12, 00, 00, 00, time.UTC)
+
+	state, result := sw.Take(RateLimitState{}, now)
+	assert.True(result.Allowed)
+	assert.Equal(1, result.Remaining)
+	assert.Equal(time.Minute, result.Reset)
+
+	state, result = sw.Take(state, now.Add(time.Second))
+	assert.True(result.Allowed)
+	assert.Zero(result.Remaining)
+
+	state, result = sw.Take(state, now.Add(2*time.Second))
+	assert.False(result.Allowed)
+	assert.Equal(58*time.Second, result.RetryAfter)
+
+	// half way into the next window, half of the previous window's count still applies.
+	state, result = sw.Take(state, now.Add(90*time.Second))
+	assert.True(result.Allowed)
+	assert.Equal(2.0, state.Previous)
+	assert.Equal(1.0, state.Count)
+
+	_, result = sw.Take(state, now.Add(91*time.Second))
+	assert.False(result.Allowed)
+	assert.Equal(29*time.Second, result.RetryAfter)
+
+	// skipping a window resets the count.
+	state, result = sw.Take(state, now.Add(5*time.Minute))
+	assert.True(result.Allowed)
+	assert.Zero(state.Previous)
+}
+
+func TestLocalRateLimitStoreSweep(t *testing.T) {
+	assert := assert.New(t)
+
+	store := NewLocalRateLimitStore()
+	assert.Nil(store.Update(context.Background(), "expired", func(_ RateLimitState) RateLimitState {
+		return RateLimitState{Timestamp: time.Now().UTC(), Expires: time.Now().UTC().Add(-time.Second)}
+	}))
+	assert.Nil(store.Update(context.Background(), "current", func(_ RateLimitState) RateLimitState {
+		return RateLimitState{Timestamp: time.Now().UTC(), Expires: time.Now().UTC().Add(time.Hour)}
+	}))
+	assert.Nil(store.Sweep(context.Background()))
+	assert.Len(store.States, 1)
+	_, ok := store.States["current"]
+	assert.True(ok)
+}
+
+func TestLocalRateLimitStoreSweepsOnUpdate(t *testing.T) {
+	assert := assert.New(t)
+
+	store := NewLocalRateLimitStore()
+	store.SweepInterval = time.Nanosecond
+	assert.Nil(store.Update(context.Background(), "expired", func(_ RateLimitState) RateLimitState {
+		return RateLimitState{Timestamp: time.Now().UTC(), Expires: time.Now().UTC().Add(-time.Second)}
+	}))
+	assert.Len(store.States, 1)
+
+	time.Sleep(time.Millisecond)
+	assert.Nil(store.Update(context.Background(), "current", func(_ RateLimitState) RateLimitState {
+		return RateLimitState{Timestamp: time.Now().UTC(), Expires: time.Now().UTC().Add(time.Hour)}
+	}))
+	assert.Len(store.States, 1)
+	_, ok := store.States["current"]
+	assert.True(ok)
+
+	// updates within the sweep interval do not sweep.
+	store.SweepInterval = time.Hour
+	assert.Nil(store.Update(context.Background(), "expired", func(_ RateLimitState) RateLimitState {
+		return RateLimitState{Timestamp: time.Now().UTC(), Expires: time.Now().UTC().Add(-time.Second)}
+	}))
+	assert.Nil(store.Update(context.Background(), "current", func(state RateLimitState) RateLimitState {
+		return state
+	}))
+	assert.Len(store.States, 2)
+}
+
+func TestRateLimitNoDefaultProvider(t *testing.T) {
+	assert := assert.New(t)
+
+	action := RateLimit(
+		OptRateLimitAlgorithm(TokenBucket{Limit: 1, Period: time.Minute}),
+		OptRateLimitKeyProvider(func(_ *Ctx) string { return "key" }),
+	)(ok)
+
+	action(MockCtx("GET", "/"))
+	result, isRaw := action(MockCtx("GET", "/")).(*RawResult)
+	assert.True(isRaw)
+	assert.Equal(http.StatusTooManyRequests, result.StatusCode)
+}
+
+func TestRateLimit(t *testing.T) {
+	assert := assert.New(t)
+
+	app := MustNew()
+	app.GET("/", ok, RateLimit(OptRateLimitAlgorithm(TokenBucket{Limit: 2, Period: time.Minute})))
+
+	res, err := MockGet(app, "/", r2.OptHeaderValue("X-Forwarded-For", "10.0.0.1")).DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, res.StatusCode)
+	assert.Equal("2", res.Header.Get(HeaderRateLimitLimit))
+	assert.Equal("1", res.Header.Get(HeaderRateLimitRemaining))
+	assert.Equal("30", res.Header.Get(HeaderRateLimitReset))
+
+	res, err = MockGet(app, "/", r2.OptHeaderValue("X-Forwarded-For", "10.0.0.1")).DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, res.StatusCode)
+
+	res, err = MockGet(app, "/", r2.OptHeaderValue("X-Forwarded-For", "10.0.0.1")).DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusTooManyRequests, res.StatusCode)
+	assert.Equal("30", res.Header.Get(HeaderRetryAfter))
+
+	// a different client has its own limit.
+	res, err = MockGet(app, "/", r2.OptHeaderValue("X-Forwarded-For", "10.0.0.2")).DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, res.StatusCode)
+}
+
+func TestRateLimitCustomKey(t *testing.T) {
+	assert := assert.New(t)
+
+	var exceeded bool
+	app := MustNew()
+	app.GET("/", ok, RateLimit(
+		OptRateLimitAlgorithm(SlidingWindow{Limit: 1, Window: time.Minute}),
+		OptRateLimitKeyProvider(func(ctx *Ctx) string { return ctx.Request.Header.Get("X-Api-Key") }),
+		OptRateLimitExceeded(func(ctx *Ctx) Result {
+			exceeded = true
+			return JSON.Status(http.StatusTooManyRequests)
+		}),
+	))
+
+	// requests without a key are not limited.
+	for x := 0; x < 3; x++ {
+		res, err := MockGet(app, "/").DiscardWithResponse()
+		assert.Nil(err)
+		assert.Equal(http.StatusOK, res.StatusCode)
+		assert.Empty(res.Header.Get(HeaderRateLimitLimit))
+	}
+
+	res, err := MockGet(app, "/", r2.OptHeaderValue("X-Api-Key", "foo")).DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, res.StatusCode)
+
+	res, err = MockGet(app, "/", r2.OptHeaderValue("X-Api-Key", "foo")).DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusTooManyRequests, res.StatusCode)
+	assert.True(exceeded)
+}
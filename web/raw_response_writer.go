@@ -1,11 +1,17 @@
 package web
 
 import (
+	"bufio"
+	"fmt"
+	"net"
 	"net/http"
+
+	"github.com/blend/go-sdk/ex"
 )
 
 var (
 	_ ResponseWriter = (*RawResponseWriter)(nil)
+	_ http.Hijacker  = (*RawResponseWriter)(nil)
 )
 
 // NewRawResponseWriter creates a new uncompressed response writer.
@@ -62,6 +68,15 @@ func (rw *RawResponseWriter) Flush() {
 	}
 }
 
+// Hijack wraps response writer's Hijack function.
+func (rw *RawResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := rw.innerResponse.(http.Hijacker)
+	if !ok {
+		return nil, nil, ex.New(fmt.Errorf("ResponseWriter doesn't support Hijacker interface"))
+	}
+	return hijacker.Hijack()
+}
+
 // Close disposes of the response writer.
 func (rw *RawResponseWriter) Close() error {
 	return nil
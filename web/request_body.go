@@ -0,0 +1,111 @@
+package web
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/blend/go-sdk/ex"
+)
+
+// MaxBodyBytes returns a middleware that limits the request body size for an action,
+// overriding the app `Config.MaxBodyBytes` limit.
+// Requests that declare a `Content-Length` over the limit are rejected with a 413
+// before the action is called, and the request body is capped so that any read past
+// the limit, through `ctx.PostBody()`, `ctx.Form()` or `ctx.Request.Body` directly, returns
+// an `ErrRequestBodyTooLarge` error. Actions that return that error from
+// `DefaultProvider.InternalError(err)` also render a 413.
+func MaxBodyBytes(maxBodyBytes int64) Middleware {
+	return func(action Action) Action {
+		limited := requestBodyLimited(action)
+		return func(ctx *Ctx) Result {
+			ctx.MaxBodyBytes = maxBodyBytes
+			return limited(ctx)
+		}
+	}
+}
+
+// NewRequestBodyReader returns a reader for the request body that decodes
+// any "gzip" or "deflate" `Content-Encoding` values in the order they were applied.
+// The caller is responsible for closing both the returned reader and the request body.
+func NewRequestBodyReader(req *http.Request) (io.ReadCloser, error) {
+	body := ioutil.NopCloser(req.Body)
+	encodings := headerTokens(req.Header, HeaderContentEncoding)
+	var err error
+	for index := len(encodings) - 1; index >= 0; index-- {
+		switch strings.ToLower(encodings[index]) {
+		case ContentEncodingIdentity:
+			continue
+		case ContentEncodingGZIP, "x-gzip":
+			body, err = gzip.NewReader(body)
+		case ContentEncodingDeflate:
+			body, err = zlib.NewReader(body)
+		default:
+			return nil, ex.New(ErrUnsupportedContentEncoding, ex.OptMessagef("content encoding: %s", encodings[index]))
+		}
+		if err != nil {
+			return nil, ex.New(err)
+		}
+	}
+	return body, nil
+}
+
+// requestBodyLimited returns an action that rejects requests that declare a body larger than the context limit,
+// and caps reads of the request body at that limit.
+func requestBodyLimited(action Action) Action {
+	return func(ctx *Ctx) Result {
+		if ctx.MaxBodyBytes > 0 {
+			if ctx.Request.ContentLength > ctx.MaxBodyBytes {
+				return requestEntityTooLarge(ctx)
+			}
+			if ctx.Request.Body != nil {
+				if limited, ok := ctx.Request.Body.(*maxBodyReader); !ok || limited.max != ctx.MaxBodyBytes {
+					ctx.Request.Body = &maxBodyReader{
+						ReadCloser: http.MaxBytesReader(ctx.Response, ctx.Request.Body, ctx.MaxBodyBytes),
+						max:        ctx.MaxBodyBytes,
+					}
+				}
+			}
+		}
+		return action(ctx)
+	}
+}
+
+// maxBodyReader wraps an `http.MaxBytesReader` so that reads past the limit
+// return an error of class `ErrRequestBodyTooLarge`.
+type maxBodyReader struct {
+	io.ReadCloser
+	max  int64
+	read int64
+}
+
+// Read implements io.Reader.
+func (mbr *maxBodyReader) Read(p []byte) (n int, err error) {
+	n, err = mbr.ReadCloser.Read(p)
+	mbr.read += int64(n)
+	if err != nil && err != io.EOF && mbr.read >= mbr.max {
+		err = ex.New(ErrRequestBodyTooLarge, ex.OptMessagef("max body bytes: %d", mbr.max))
+	}
+	return
+}
+
+// requestEntityTooLarge returns a 413 result from the context default provider.
+func requestEntityTooLarge(ctx *Ctx) Result {
+	provider := ctx.DefaultProvider
+	if provider == nil {
+		provider = Text
+	}
+	return provider.Status(http.StatusRequestEntityTooLarge)
+}
+
+// unsupportedMediaType returns a 415 result from the context default provider.
+func unsupportedMediaType(ctx *Ctx) Result {
+	provider := ctx.DefaultProvider
+	if provider == nil {
+		provider = Text
+	}
+	return provider.Status(http.StatusUnsupportedMediaType)
+}
@@ -0,0 +1,219 @@
+package web
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/r2"
+)
+
+func TestCtxPostBodyGZip(t *testing.T) {
+	assert := assert.New(t)
+
+	buffer := new(bytes.Buffer)
+	gzw := gzip.NewWriter(buffer)
+	_, err := gzw.Write([]byte(`{"foo":"bar"}`))
+	assert.Nil(err)
+	assert.Nil(gzw.Close())
+
+	ctx := MockCtx("POST", "/", OptCtxBodyBytes(buffer.Bytes()), OptCtxHeaderValue(HeaderContentEncoding, ContentEncodingGZIP))
+	var body map[string]string
+	assert.Nil(ctx.PostBodyAsJSON(&body))
+	assert.Equal("bar", body["foo"])
+}
+
+func TestCtxPostBodyDeflate(t *testing.T) {
+	assert := assert.New(t)
+
+	buffer := new(bytes.Buffer)
+	zw := zlib.NewWriter(buffer)
+	_, err := zw.Write([]byte("hello world"))
+	assert.Nil(err)
+	assert.Nil(zw.Close())
+
+	ctx := MockCtx("POST", "/", OptCtxBodyBytes(buffer.Bytes()), OptCtxHeaderValue(HeaderContentEncoding, ContentEncodingDeflate))
+	body, err := ctx.PostBodyAsString()
+	assert.Nil(err)
+	assert.Equal("hello world", body)
+}
+
+func TestCtxPostBodyUnsupportedEncoding(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx := MockCtx("POST", "/", OptCtxBodyBytes([]byte("foo")), OptCtxHeaderValue(HeaderContentEncoding, "br"))
+	_, err := ctx.PostBody()
+	assert.True(IsErrUnsupportedContentEncoding(err))
+}
+
+func TestCtxPostBodyMaxBodyBytes(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx := MockCtx("POST", "/", OptCtxBodyBytes([]byte("0123456789")), OptCtxMaxBodyBytes(5))
+	_, err := ctx.PostBody()
+	assert.True(IsErrRequestBodyTooLarge(err))
+	assert.Empty(ctx.Body)
+
+	ctx = MockCtx("POST", "/", OptCtxBodyBytes([]byte("0123456789")), OptCtxMaxBodyBytes(10))
+	body, err := ctx.PostBody()
+	assert.Nil(err)
+	assert.Equal("0123456789", string(body))
+}
+
+func TestCtxPostBodyMaxBodyBytesDecompressed(t *testing.T) {
+	assert := assert.New(t)
+
+	buffer := new(bytes.Buffer)
+	gzw := gzip.NewWriter(buffer)
+	_, err := gzw.Write(bytes.Repeat([]byte("a"), 1<<16))
+	assert.Nil(err)
+	assert.Nil(gzw.Close())
+	assert.True(buffer.Len() < 1024)
+
+	ctx := MockCtx("POST", "/",
+		OptCtxBodyBytes(buffer.Bytes()),
+		OptCtxHeaderValue(HeaderContentEncoding, ContentEncodingGZIP),
+		OptCtxMaxBodyBytes(1024),
+	)
+	_, err = ctx.PostBody()
+	assert.True(IsErrRequestBodyTooLarge(err))
+}
+
+func TestAppMaxBodyBytes(t *testing.T) {
+	assert := assert.New(t)
+
+	var calls int
+	app := MustNew(OptConfig(Config{MaxBodyBytes: 5}))
+	app.POST("/", func(ctx *Ctx) Result {
+		calls++
+		if _, err := ctx.PostBody(); err != nil {
+			return ctx.DefaultProvider.BadRequest(err)
+		}
+		return Raw([]byte("ok"))
+	})
+
+	res, err := MockPost(app, "/", ioutil.NopCloser(bytes.NewReader([]byte("0123456789"))), func(r *r2.Request) error {
+		r.ContentLength = 10
+		return nil
+	}).DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusRequestEntityTooLarge, res.StatusCode)
+	assert.Zero(calls)
+
+	res, err = MockPost(app, "/", ioutil.NopCloser(bytes.NewReader([]byte("01234"))), func(r *r2.Request) error {
+		r.ContentLength = 5
+		return nil
+	}).DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, res.StatusCode)
+	assert.Equal(1, calls)
+}
+
+func TestMaxBodyBytes(t *testing.T) {
+	assert := assert.New(t)
+
+	app := MustNew()
+	app.POST("/", func(ctx *Ctx) Result {
+		body, err := ctx.PostBody()
+		if err != nil {
+			return ctx.DefaultProvider.BadRequest(err)
+		}
+		return Raw(body)
+	}, MaxBodyBytes(3))
+
+	res, err := MockPost(app, "/", ioutil.NopCloser(bytes.NewReader([]byte("0123456789"))), func(r *r2.Request) error {
+		r.ContentLength = 10
+		return nil
+	}).DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusRequestEntityTooLarge, res.StatusCode)
+
+	contents, err := MockPost(app, "/", ioutil.NopCloser(bytes.NewReader([]byte("012")))).Bytes()
+	assert.Nil(err)
+	assert.Equal("012", string(contents))
+}
+
+func TestMaxBodyBytesOverridesApp(t *testing.T) {
+	assert := assert.New(t)
+
+	app := MustNew(OptConfig(Config{MaxBodyBytes: 5}))
+	app.POST("/", func(ctx *Ctx) Result {
+		body, err := ctx.PostBody()
+		if err != nil {
+			return ctx.DefaultProvider.InternalError(err)
+		}
+		return Raw(body)
+	}, MaxBodyBytes(20))
+
+	res, err := MockPost(app, "/", ioutil.NopCloser(bytes.NewReader([]byte("0123456789"))), func(r *r2.Request) error {
+		r.ContentLength = 10
+		return nil
+	}).DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, res.StatusCode)
+
+	res, err = MockPost(app, "/", ioutil.NopCloser(bytes.NewReader(bytes.Repeat([]byte("0"), 30)))).DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusRequestEntityTooLarge, res.StatusCode)
+}
+
+func TestAppMaxBodyBytesUndeclared(t *testing.T) {
+	assert := assert.New(t)
+
+	app := MustNew(OptConfig(Config{MaxBodyBytes: 5}))
+	app.POST("/", func(ctx *Ctx) Result {
+		body, err := ctx.PostBody()
+		if err != nil {
+			return ctx.DefaultProvider.InternalError(err)
+		}
+		return Raw(body)
+	})
+
+	// the body is sent chunked, so it is only found to be too large when it is read.
+	res, err := MockPost(app, "/", ioutil.NopCloser(bytes.NewReader([]byte("0123456789")))).DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusRequestEntityTooLarge, res.StatusCode)
+}
+
+func TestAppMaxBodyBytesRequestBody(t *testing.T) {
+	assert := assert.New(t)
+
+	app := MustNew(OptConfig(Config{MaxBodyBytes: 5}))
+	app.POST("/", func(ctx *Ctx) Result {
+		body, err := ioutil.ReadAll(ctx.Request.Body)
+		if err != nil {
+			return ctx.DefaultProvider.InternalError(err)
+		}
+		return Raw(body)
+	})
+
+	// reading the request body directly is capped as well.
+	res, err := MockPost(app, "/", ioutil.NopCloser(bytes.NewReader([]byte("0123456789")))).DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusRequestEntityTooLarge, res.StatusCode)
+
+	contents, err := MockPost(app, "/", ioutil.NopCloser(bytes.NewReader([]byte("01234")))).Bytes()
+	assert.Nil(err)
+	assert.Equal("01234", string(contents))
+}
+
+func TestAppUnsupportedContentEncoding(t *testing.T) {
+	assert := assert.New(t)
+
+	app := MustNew()
+	app.POST("/", func(ctx *Ctx) Result {
+		body, err := ctx.PostBody()
+		if err != nil {
+			return ctx.DefaultProvider.InternalError(err)
+		}
+		return Raw(body)
+	})
+
+	res, err := MockPost(app, "/", ioutil.NopCloser(bytes.NewReader([]byte("foo"))), r2.OptHeaderValue(HeaderContentEncoding, "br")).DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusUnsupportedMediaType, res.StatusCode)
+}
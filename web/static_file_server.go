@@ -7,6 +7,8 @@ import (
 	"os"
 	"regexp"
 	"sync"
+
+	"github.com/blend/go-sdk/fileutil"
 )
 
 // NewStaticFileServer returns a new static file cache.
@@ -132,6 +134,9 @@ func (sc *StaticFileServer) ServeFile(r *Ctx, filePath string) Result {
 		http.Error(r.Response, err.Error(), http.StatusInternalServerError)
 		return nil
 	}
+	if r.Response.Header().Get(HeaderETag) == "" {
+		r.Response.Header().Set(HeaderETag, fileInfoETag(finfo.Size(), finfo.ModTime()))
+	}
 	http.ServeContent(r.Response, r.Request, filePath, finfo.ModTime(), f)
 	return nil
 }
@@ -154,6 +159,9 @@ func (sc *StaticFileServer) ServeCachedFile(r *Ctx, filepath string) Result {
 		http.NotFound(r.Response, r.Request)
 		return nil
 	}
+	if file.ETag != "" {
+		r.Response.Header().Set(HeaderETag, file.ETag)
+	}
 	http.ServeContent(r.Response, r.Request, filepath, file.ModTime, file.Contents)
 	return nil
 }
@@ -226,10 +234,16 @@ func (sc *StaticFileServer) ResolveCachedFile(filepath string) (*CachedStaticFil
 		return nil, err
 	}
 
+	etag, err := fileutil.ETag(contents)
+	if err != nil {
+		return nil, err
+	}
+
 	file := &CachedStaticFile{
 		Path:     filepath,
 		Contents: bytes.NewReader(contents),
 		ModTime:  finfo.ModTime(),
+		ETag:     FormatETag(etag),
 		Size:     len(contents),
 	}
 
@@ -49,6 +49,9 @@ func (sr StaticResult) Render(ctx *Ctx) error {
 		return err
 	}
 
+	if ctx.Response.Header().Get(HeaderETag) == "" {
+		ctx.Response.Header().Set(HeaderETag, fileInfoETag(d.Size(), d.ModTime()))
+	}
 	http.ServeContent(ctx.Response, ctx.Request, filePath, d.ModTime(), f)
 	return nil
 }
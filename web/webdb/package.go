@@ -0,0 +1,5 @@
+/*
+Package webdb provides `db.Connection` backed implementations of web stores,
+allowing state like sessions and rate limits to be shared across instances of an app.
+*/
+package webdb
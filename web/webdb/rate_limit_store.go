@@ -0,0 +1,124 @@
+package webdb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/blend/go-sdk/db"
+	"github.com/blend/go-sdk/db/migration"
+	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/web"
+)
+
+var (
+	_ web.RateLimitStore = (*RateLimitStore)(nil)
+)
+
+// DefaultRateLimitTable is the default rate limit table name.
+const DefaultRateLimitTable = "web_rate_limit"
+
+// NewRateLimitStore returns a new rate limit store.
+func NewRateLimitStore(conn *db.Connection, options ...RateLimitStoreOption) *RateLimitStore {
+	rls := &RateLimitStore{
+		Conn:  conn,
+		Table: DefaultRateLimitTable,
+	}
+	for _, option := range options {
+		option(rls)
+	}
+	return rls
+}
+
+// RateLimitStoreOption is an option for rate limit stores.
+type RateLimitStoreOption func(*RateLimitStore)
+
+// OptRateLimitTable sets the rate limit table name.
+func OptRateLimitTable(table string) RateLimitStoreOption {
+	return func(rls *RateLimitStore) { rls.Table = table }
+}
+
+// RateLimitStore is a rate limit store backed by a database table.
+// State for a key is locked for the duration of an update.
+type RateLimitStore struct {
+	Conn  *db.Connection
+	Table string
+}
+
+// Migrations returns the migrations to create the rate limit table.
+func (rls *RateLimitStore) Migrations() *migration.Group {
+	return migration.NewGroupWithActions(
+		migration.NewStep(
+			migration.TableNotExists(rls.Table),
+			migration.Statements(
+				fmt.Sprintf(`CREATE TABLE %s (
+					key varchar(1024) not null primary key,
+					count double precision not null default 0,
+					previous double precision not null default 0,
+					timestamp timestamp with time zone,
+					expires timestamp with time zone
+				)`, rls.Table),
+				fmt.Sprintf(`CREATE INDEX ix_%s_expires ON %s (expires)`, rls.Table, rls.Table),
+			),
+		),
+	)
+}
+
+// Update implements web.RateLimitStore.
+func (rls *RateLimitStore) Update(ctx context.Context, key string, update func(web.RateLimitState) web.RateLimitState) (err error) {
+	var tx *sql.Tx
+	tx, err = rls.Conn.BeginContext(ctx)
+	if err != nil {
+		return
+	}
+	defer func() {
+		if err != nil {
+			if txErr := tx.Rollback(); txErr != nil {
+				err = ex.Nest(err, txErr)
+			}
+		} else {
+			if txErr := tx.Commit(); txErr != nil {
+				err = ex.Nest(err, txErr)
+			}
+		}
+	}()
+
+	// ensure the row exists so it can be locked for concurrent updates.
+	err = db.IgnoreExecResult(rls.Conn.Invoke(db.OptContext(ctx), db.OptTx(tx)).Exec(
+		fmt.Sprintf("INSERT INTO %s (key) VALUES ($1) ON CONFLICT (key) DO NOTHING", rls.Table), key,
+	))
+	if err != nil {
+		return
+	}
+
+	var state web.RateLimitState
+	var timestamp, expires *time.Time
+	_, err = rls.Conn.Invoke(db.OptContext(ctx), db.OptTx(tx)).Query(
+		fmt.Sprintf("SELECT count, previous, timestamp, expires FROM %s WHERE key = $1 FOR UPDATE", rls.Table), key,
+	).Scan(&state.Count, &state.Previous, &timestamp, &expires)
+	if err != nil {
+		return
+	}
+	if timestamp != nil {
+		state.Timestamp = timestamp.UTC()
+	}
+	if expires != nil {
+		state.Expires = expires.UTC()
+	}
+
+	state = update(state)
+	err = db.IgnoreExecResult(rls.Conn.Invoke(db.OptContext(ctx), db.OptTx(tx)).Exec(
+		fmt.Sprintf("UPDATE %s SET count = $2, previous = $3, timestamp = $4, expires = $5 WHERE key = $1", rls.Table),
+		key, state.Count, state.Previous, state.Timestamp, state.Expires,
+	))
+	return
+}
+
+// Sweep removes expired rate limit states.
+// It should be called periodically to bound the size of the table.
+func (rls *RateLimitStore) Sweep(ctx context.Context) error {
+	return db.IgnoreExecResult(rls.Conn.Invoke(db.OptContext(ctx)).Exec(
+		fmt.Sprintf("DELETE FROM %s WHERE expires < $1", rls.Table), time.Now().UTC(),
+	))
+}
@@ -0,0 +1,36 @@
+package webdb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/web"
+)
+
+func TestRateLimitStore(t *testing.T) {
+	assert := assert.New(t)
+
+	store := NewRateLimitStore(defaultDB(), OptRateLimitTable(buildTestTableName()))
+	defer dropTestTable(store.Table)
+	assert.Nil(store.Migrations().Action(context.Background(), defaultDB()))
+
+	algorithm := web.TokenBucket{Limit: 2, Period: time.Hour}
+	now := time.Now().UTC()
+
+	var results []web.RateLimitResult
+	for x := 0; x < 3; x++ {
+		assert.Nil(store.Update(context.Background(), "test-key", func(state web.RateLimitState) web.RateLimitState {
+			var result web.RateLimitResult
+			state, result = algorithm.Take(state, now)
+			results = append(results, result)
+			return state
+		}))
+	}
+	assert.True(results[0].Allowed)
+	assert.True(results[1].Allowed)
+	assert.False(results[2].Allowed)
+
+	assert.Nil(store.Sweep(context.Background()))
+}
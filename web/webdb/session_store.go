@@ -0,0 +1,164 @@
+package webdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/blend/go-sdk/db"
+	"github.com/blend/go-sdk/db/migration"
+	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/web"
+)
+
+// DefaultSessionTable is the default session table name.
+const DefaultSessionTable = "web_session"
+
+// NewSessionStore returns a new session store.
+func NewSessionStore(conn *db.Connection, options ...SessionStoreOption) *SessionStore {
+	ss := &SessionStore{
+		Conn:  conn,
+		Table: DefaultSessionTable,
+	}
+	for _, option := range options {
+		option(ss)
+	}
+	return ss
+}
+
+// SessionStoreOption is an option for session stores.
+type SessionStoreOption func(*SessionStore)
+
+// OptSessionTable sets the session table name.
+func OptSessionTable(table string) SessionStoreOption {
+	return func(ss *SessionStore) { ss.Table = table }
+}
+
+// SessionStore is a web.AuthManager session store backed by a database table.
+/*
+Use it with an auth manager by setting the persist, fetch and remove handlers:
+
+	store := webdb.NewSessionStore(conn)
+	auth, err := web.NewAuthManager(
+		web.OptAuthManagerPersistHandler(store.PersistHandler),
+		web.OptAuthManagerFetchHandler(store.FetchHandler),
+		web.OptAuthManagerRemoveHandler(store.RemoveHandler),
+	)
+
+Expired sessions are not returned by `FetchHandler`, but remain in the table until
+`Sweep` is called, which should be done periodically (e.g. with a cron job).
+*/
+type SessionStore struct {
+	Conn  *db.Connection
+	Table string
+}
+
+// Migrations returns the migrations to create the session table.
+func (ss *SessionStore) Migrations() *migration.Group {
+	return migration.NewGroupWithActions(
+		migration.NewStep(
+			migration.TableNotExists(ss.Table),
+			migration.Statements(
+				fmt.Sprintf(`CREATE TABLE %s (
+					session_id varchar(255) not null primary key,
+					user_id varchar(255) not null,
+					base_url varchar(1024),
+					created_utc timestamp with time zone not null,
+					expires_utc timestamp with time zone,
+					user_agent varchar(1024),
+					remote_addr varchar(255),
+					state jsonb
+				)`, ss.Table),
+				fmt.Sprintf(`CREATE INDEX ix_%s_user_id ON %s (user_id)`, ss.Table, ss.Table),
+				fmt.Sprintf(`CREATE INDEX ix_%s_expires_utc ON %s (expires_utc)`, ss.Table, ss.Table),
+			),
+		),
+	)
+}
+
+// PersistHandler inserts or updates a session, and implements web.AuthManagerPersistHandler.
+func (ss *SessionStore) PersistHandler(ctx context.Context, session *web.Session) error {
+	state, err := json.Marshal(session.State)
+	if err != nil {
+		return ex.New(err)
+	}
+	var expiresUTC *time.Time
+	if !session.ExpiresUTC.IsZero() {
+		expiresUTC = &session.ExpiresUTC
+	}
+	return db.IgnoreExecResult(ss.Conn.Invoke(db.OptContext(ctx)).Exec(
+		fmt.Sprintf(`INSERT INTO %s (session_id, user_id, base_url, created_utc, expires_utc, user_agent, remote_addr, state)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			ON CONFLICT (session_id) DO UPDATE SET
+				user_id = excluded.user_id,
+				base_url = excluded.base_url,
+				expires_utc = excluded.expires_utc,
+				user_agent = excluded.user_agent,
+				remote_addr = excluded.remote_addr,
+				state = excluded.state`, ss.Table),
+		session.SessionID, session.UserID, session.BaseURL, session.CreatedUTC, expiresUTC, session.UserAgent, session.RemoteAddr, string(state),
+	))
+}
+
+// FetchHandler returns a session by id, and implements web.AuthManagerFetchHandler.
+// It returns nil if the session is not found or is expired.
+func (ss *SessionStore) FetchHandler(ctx context.Context, sessionID string) (*web.Session, error) {
+	var session web.Session
+	var baseURL, userAgent, remoteAddr, state *string
+	var expiresUTC *time.Time
+	found, err := ss.Conn.Invoke(db.OptContext(ctx)).Query(
+		fmt.Sprintf(`SELECT session_id, user_id, base_url, created_utc, expires_utc, user_agent, remote_addr, state
+			FROM %s WHERE session_id = $1 AND (expires_utc IS NULL OR expires_utc > $2)`, ss.Table),
+		sessionID, time.Now().UTC(),
+	).Scan(&session.SessionID, &session.UserID, &baseURL, &session.CreatedUTC, &expiresUTC, &userAgent, &remoteAddr, &state)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+
+	session.CreatedUTC = session.CreatedUTC.UTC()
+	if expiresUTC != nil {
+		session.ExpiresUTC = expiresUTC.UTC()
+	}
+	if baseURL != nil {
+		session.BaseURL = *baseURL
+	}
+	if userAgent != nil {
+		session.UserAgent = *userAgent
+	}
+	if remoteAddr != nil {
+		session.RemoteAddr = *remoteAddr
+	}
+	session.State = map[string]interface{}{}
+	if state != nil {
+		if err = json.Unmarshal([]byte(*state), &session.State); err != nil {
+			return nil, ex.New(err)
+		}
+	}
+	return &session, nil
+}
+
+// RemoveHandler removes a session by id, and implements web.AuthManagerRemoveHandler.
+func (ss *SessionStore) RemoveHandler(ctx context.Context, sessionID string) error {
+	return db.IgnoreExecResult(ss.Conn.Invoke(db.OptContext(ctx)).Exec(
+		fmt.Sprintf("DELETE FROM %s WHERE session_id = $1", ss.Table), sessionID,
+	))
+}
+
+// RemoveUserSessions removes all the sessions for a given user, logging them out everywhere.
+func (ss *SessionStore) RemoveUserSessions(ctx context.Context, userID string) error {
+	return db.IgnoreExecResult(ss.Conn.Invoke(db.OptContext(ctx)).Exec(
+		fmt.Sprintf("DELETE FROM %s WHERE user_id = $1", ss.Table), userID,
+	))
+}
+
+// Sweep removes expired sessions.
+// It should be called periodically to bound the size of the table.
+func (ss *SessionStore) Sweep(ctx context.Context) error {
+	return db.IgnoreExecResult(ss.Conn.Invoke(db.OptContext(ctx)).Exec(
+		fmt.Sprintf("DELETE FROM %s WHERE expires_utc < $1", ss.Table), time.Now().UTC(),
+	))
+}
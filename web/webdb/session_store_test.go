@@ -0,0 +1,51 @@
+package webdb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/web"
+)
+
+func TestSessionStore(t *testing.T) {
+	assert := assert.New(t)
+
+	store := NewSessionStore(defaultDB(), OptSessionTable(buildTestTableName()))
+	defer dropTestTable(store.Table)
+	assert.Nil(store.Migrations().Action(context.Background(), defaultDB()))
+
+	session := web.NewSession("example-string", web.NewSessionID())
+	session.ExpiresUTC = time.Now().UTC().Add(time.Hour)
+	session.State["foo"] = "bar"
+	assert.Nil(store.PersistHandler(context.Background(), session))
+
+	fetched, err := store.FetchHandler(context.Background(), session.SessionID)
+	assert.Nil(err)
+	assert.NotNil(fetched)
+	assert.Equal(session.UserID, fetched.UserID)
+	assert.Equal("bar", fetched.State["foo"])
+
+	session.ExpiresUTC = time.Now().UTC().Add(-time.Minute)
+	assert.Nil(store.PersistHandler(context.Background(), session))
+	fetched, err = store.FetchHandler(context.Background(), session.SessionID)
+	assert.Nil(err)
+	assert.Nil(fetched)
+
+	assert.Nil(store.Sweep(context.Background()))
+	var count int
+	_, err = defaultDB().Query("SELECT count(*) FROM " + store.Table).Scan(&count)
+	assert.Nil(err)
+	assert.Zero(count)
+
+	other := web.NewSession("example-string", web.NewSessionID())
+	assert.Nil(store.PersistHandler(context.Background(), other))
+	assert.Nil(store.PersistHandler(context.Background(), web.NewSession("other-user", web.NewSessionID())))
+	assert.Nil(store.RemoveUserSessions(context.Background(), "example-string"))
+	fetched, err = store.FetchHandler(context.Background(), other.SessionID)
+	assert.Nil(err)
+	assert.Nil(fetched)
+
+	assert.Nil(store.RemoveHandler(context.Background(), other.SessionID))
+}
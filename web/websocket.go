@@ -0,0 +1,263 @@
+package web
+
+import (
+	"crypto/sha1"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/blend/go-sdk/ex"
+)
+
+const (
+	// WebSocketVersion is the only websocket protocol version supported (RFC 6455).
+	WebSocketVersion = "13"
+	// webSocketAcceptGUID is the magic value used to compute the accept header.
+	webSocketAcceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+)
+
+// WebSocketHandler is the function signature for websocket handlers.
+// The connection is closed when the handler returns; if the handler returns an error
+// the connection is closed with an internal error close code.
+type WebSocketHandler func(*Ctx, *WebSocketConn) error
+
+// WebSocket returns an action that upgrades the request to a websocket connection
+// and calls the handler with the connection.
+/*
+Usage:
+
+	app.GET("/ws", web.WebSocket(func(ctx *web.Ctx, conn *web.WebSocketConn) error {
+		for {
+			messageType, data, err := conn.ReadMessage()
+			if err != nil {
+				return err
+			}
+			if err = conn.WriteMessage(messageType, data); err != nil {
+				return err
+			}
+		}
+	}))
+
+Handshake failures return results from the context default provider.
+*/
+func WebSocket(handler WebSocketHandler, options ...WebSocketOption) Action {
+	upgrader := NewWebSocketUpgrader(options...)
+	return func(ctx *Ctx) Result {
+		conn, result := upgrader.Upgrade(ctx)
+		if result != nil {
+			return result
+		}
+
+		started := time.Now().UTC()
+		if ctx.App != nil && ctx.App.Log != nil {
+			ctx.App.Log.Trigger(ctx.Context(), NewWebSocketEvent(FlagWebSocketOpen,
+				OptWebSocketEventRequest(ctx.Request),
+				OptWebSocketEventRoute(ctx.Route),
+				OptWebSocketEventSubprotocol(conn.Subprotocol),
+			))
+		}
+
+		err := safeWebSocketHandler(handler, ctx, conn)
+		if IsWebSocketCloseError(err, WebSocketCloseNormal, WebSocketCloseGoingAway, WebSocketCloseNoStatus) {
+			err = nil
+		}
+		if err != nil && !conn.CloseSent() {
+			_ = conn.WriteClose(WebSocketCloseInternalError, "")
+		}
+		_ = conn.Close()
+
+		if ctx.App != nil && ctx.App.Log != nil {
+			ctx.App.Log.Trigger(ctx.Context(), NewWebSocketEvent(FlagWebSocketClose,
+				OptWebSocketEventRequest(ctx.Request),
+				OptWebSocketEventRoute(ctx.Route),
+				OptWebSocketEventSubprotocol(conn.Subprotocol),
+				OptWebSocketEventCloseCode(conn.CloseCode()),
+				OptWebSocketEventElapsed(time.Now().UTC().Sub(started)),
+				OptWebSocketEventErr(err),
+			))
+		}
+		return nil
+	}
+}
+
+// IsWebSocketUpgrade returns if the request is a websocket upgrade request.
+func IsWebSocketUpgrade(req *http.Request) bool {
+	return headerContainsToken(req.Header, HeaderConnection, "upgrade") &&
+		headerContainsToken(req.Header, HeaderUpgrade, "websocket")
+}
+
+// WebSocketAccept computes the `Sec-WebSocket-Accept` header value for a given key.
+func WebSocketAccept(key string) string {
+	hash := sha1.New()
+	hash.Write([]byte(key))
+	hash.Write([]byte(webSocketAcceptGUID))
+	return base64.StdEncoding.EncodeToString(hash.Sum(nil))
+}
+
+// NewWebSocketUpgrader returns a new websocket upgrader.
+func NewWebSocketUpgrader(options ...WebSocketOption) *WebSocketUpgrader {
+	wsu := &WebSocketUpgrader{
+		ReadLimit: DefaultWebSocketReadLimit,
+	}
+	for _, option := range options {
+		option(wsu)
+	}
+	return wsu
+}
+
+// WebSocketUpgrader performs the websocket opening handshake.
+type WebSocketUpgrader struct {
+	// Subprotocols are the supported subprotocols in order of preference.
+	Subprotocols []string
+	// CheckOrigin returns if the request origin is allowed.
+	// If unset, requests with an `Origin` header are only allowed if the origin host matches the request host.
+	CheckOrigin func(*http.Request) bool
+	// ReadLimit is the maximum message size.
+	ReadLimit int64
+	// ReadTimeout is the per frame read deadline.
+	ReadTimeout time.Duration
+	// WriteTimeout is the per frame write deadline.
+	WriteTimeout time.Duration
+	// OnPing is a ping handler for connections.
+	OnPing func(*WebSocketConn, []byte) error
+	// OnPong is a pong handler for connections.
+	OnPong func(*WebSocketConn, []byte) error
+}
+
+// Upgrade validates the handshake and hijacks the connection.
+// If the handshake is invalid, it returns a non-nil result that should be rendered instead.
+func (wsu *WebSocketUpgrader) Upgrade(ctx *Ctx) (*WebSocketConn, Result) {
+	req := ctx.Request
+	provider := ctx.DefaultProvider
+	if provider == nil {
+		provider = Text
+	}
+
+	if req.Method != MethodGet {
+		return nil, provider.Status(http.StatusMethodNotAllowed)
+	}
+	if !IsWebSocketUpgrade(req) {
+		return nil, provider.BadRequest(ex.New(ErrWebSocketBadHandshake, ex.OptMessage("missing upgrade headers")))
+	}
+	if req.Header.Get(HeaderSecWebSocketVersion) != WebSocketVersion {
+		ctx.Response.Header().Set(HeaderSecWebSocketVersion, WebSocketVersion)
+		return nil, provider.Status(http.StatusUpgradeRequired)
+	}
+	key := strings.TrimSpace(req.Header.Get(HeaderSecWebSocketKey))
+	if decoded, err := base64.StdEncoding.DecodeString(key); err != nil || len(decoded) != 16 {
+		return nil, provider.BadRequest(ex.New(ErrWebSocketBadHandshake, ex.OptMessage("invalid websocket key")))
+	}
+	checkOrigin := wsu.CheckOrigin
+	if checkOrigin == nil {
+		checkOrigin = webSocketSameOrigin
+	}
+	if !checkOrigin(req) {
+		return nil, provider.Status(http.StatusForbidden)
+	}
+
+	hijacker, ok := ctx.Response.(http.Hijacker)
+	if !ok {
+		return nil, provider.InternalError(ex.New(ErrWebSocketBadHandshake, ex.OptMessage("response writer does not support hijacking")))
+	}
+	netConn, rw, err := hijacker.Hijack()
+	if err != nil {
+		return nil, provider.InternalError(err)
+	}
+	// clear any deadlines set by the server for the http request.
+	_ = netConn.SetDeadline(time.Time{})
+
+	conn := NewWebSocketConn(netConn, rw.Reader)
+	conn.Subprotocol = wsu.selectSubprotocol(req)
+	conn.ReadLimit = wsu.ReadLimit
+	conn.ReadTimeout = wsu.ReadTimeout
+	conn.WriteTimeout = wsu.WriteTimeout
+	conn.OnPing = wsu.OnPing
+	conn.OnPong = wsu.OnPong
+
+	header := make(http.Header)
+	for key, values := range ctx.Response.Header() {
+		header[key] = values
+	}
+	header.Set(HeaderUpgrade, "websocket")
+	header.Set(HeaderConnection, "Upgrade")
+	header.Set(HeaderSecWebSocketAccept, WebSocketAccept(key))
+	if conn.Subprotocol != "" {
+		header.Set(HeaderSecWebSocketProtocol, conn.Subprotocol)
+	}
+
+	fmt.Fprintf(rw.Writer, "HTTP/1.1 %d %s\r\n", http.StatusSwitchingProtocols, http.StatusText(http.StatusSwitchingProtocols))
+	_ = header.Write(rw.Writer)
+	rw.Writer.WriteString("\r\n")
+	if err = rw.Writer.Flush(); err != nil {
+		netConn.Close()
+		return nil, ResultWithLoggedError(hijackedResult{}, ex.New(err))
+	}
+	return conn, nil
+}
+
+func (wsu *WebSocketUpgrader) selectSubprotocol(req *http.Request) string {
+	if len(wsu.Subprotocols) == 0 {
+		return ""
+	}
+	requested := headerTokens(req.Header, HeaderSecWebSocketProtocol)
+	for _, supported := range wsu.Subprotocols {
+		for _, value := range requested {
+			if value == supported {
+				return supported
+			}
+		}
+	}
+	return ""
+}
+
+// hijackedResult is a result that renders nothing, used
+// once the connection has been taken over from the http server.
+type hijackedResult struct{}
+
+// Render implements Result.
+func (hijackedResult) Render(_ *Ctx) error { return nil }
+
+func safeWebSocketHandler(handler WebSocketHandler, ctx *Ctx, conn *WebSocketConn) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = ex.New(r)
+		}
+	}()
+	err = handler(ctx, conn)
+	return
+}
+
+func webSocketSameOrigin(req *http.Request) bool {
+	origin := req.Header.Get(HeaderOrigin)
+	if origin == "" {
+		return true
+	}
+	parsed, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(parsed.Host, req.Host)
+}
+
+func headerTokens(headers http.Header, key string) (output []string) {
+	for _, value := range headers[http.CanonicalHeaderKey(key)] {
+		for _, token := range strings.Split(value, ",") {
+			if token = strings.TrimSpace(token); token != "" {
+				output = append(output, token)
+			}
+		}
+	}
+	return
+}
+
+func headerContainsToken(headers http.Header, key, token string) bool {
+	for _, value := range headerTokens(headers, key) {
+		if strings.EqualFold(value, token) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,433 @@
+package web
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/blend/go-sdk/ex"
+)
+
+// WebSocketMessageType is the opcode of a websocket frame.
+type WebSocketMessageType int
+
+// WebSocket message types (frame opcodes) from RFC 6455 section 5.2.
+const (
+	WebSocketMessageContinuation WebSocketMessageType = 0x0
+	WebSocketMessageText         WebSocketMessageType = 0x1
+	WebSocketMessageBinary       WebSocketMessageType = 0x2
+	WebSocketMessageClose        WebSocketMessageType = 0x8
+	WebSocketMessagePing         WebSocketMessageType = 0x9
+	WebSocketMessagePong         WebSocketMessageType = 0xA
+)
+
+// IsControl returns if the message type is a control frame type (close, ping or pong).
+func (wsmt WebSocketMessageType) IsControl() bool {
+	return wsmt&0x8 != 0
+}
+
+// String returns a string representation of the message type.
+func (wsmt WebSocketMessageType) String() string {
+	switch wsmt {
+	case WebSocketMessageContinuation:
+		return "continuation"
+	case WebSocketMessageText:
+		return "text"
+	case WebSocketMessageBinary:
+		return "binary"
+	case WebSocketMessageClose:
+		return "close"
+	case WebSocketMessagePing:
+		return "ping"
+	case WebSocketMessagePong:
+		return "pong"
+	default:
+		return fmt.Sprintf("unknown(%d)", int(wsmt))
+	}
+}
+
+// WebSocket close status codes from RFC 6455 section 7.4.1.
+const (
+	WebSocketCloseNormal          = 1000
+	WebSocketCloseGoingAway       = 1001
+	WebSocketCloseProtocolError   = 1002
+	WebSocketCloseUnsupportedData = 1003
+	WebSocketCloseNoStatus        = 1005
+	WebSocketCloseAbnormal        = 1006
+	WebSocketCloseInvalidPayload  = 1007
+	WebSocketClosePolicyViolation = 1008
+	WebSocketCloseMessageTooBig   = 1009
+	WebSocketCloseInternalError   = 1011
+)
+
+const (
+	webSocketFinalBit          = 1 << 7
+	webSocketReservedBits      = 7 << 4
+	webSocketOpcodeMask        = 0xf
+	webSocketMaskBit           = 1 << 7
+	webSocketPayloadMask       = 0x7f
+	webSocketMaxControlPayload = 125
+	// webSocketInitialPayloadBuffer is the most that is allocated for a frame payload before it is read.
+	webSocketInitialPayloadBuffer = 4096
+)
+
+// WebSocketCloseError is returned by `ReadMessage` when the peer sends a close frame.
+type WebSocketCloseError struct {
+	Code   int
+	Reason string
+}
+
+// Error implements error.
+func (wsce *WebSocketCloseError) Error() string {
+	if wsce.Reason != "" {
+		return fmt.Sprintf("websocket closed; code: %d, reason: %s", wsce.Code, wsce.Reason)
+	}
+	return fmt.Sprintf("websocket closed; code: %d", wsce.Code)
+}
+
+// IsWebSocketCloseError returns if an error is a close error, optionally
+// with one of the given close codes.
+func IsWebSocketCloseError(err error, codes ...int) bool {
+	typed, ok := err.(*WebSocketCloseError)
+	if !ok {
+		return false
+	}
+	if len(codes) == 0 {
+		return true
+	}
+	for _, code := range codes {
+		if typed.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// NewWebSocketConn returns a new websocket connection for a hijacked net.Conn.
+// The reader should be the buffered reader returned by the hijack, as it may
+// already contain frames sent by the client.
+func NewWebSocketConn(conn net.Conn, reader *bufio.Reader) *WebSocketConn {
+	if reader == nil {
+		reader = bufio.NewReader(conn)
+	}
+	return &WebSocketConn{
+		Conn:      conn,
+		Reader:    reader,
+		ReadLimit: DefaultWebSocketReadLimit,
+	}
+}
+
+// WebSocketConn is a server side websocket connection.
+//
+// Reads must be done from a single goroutine; writes are safe
+// to call from multiple goroutines.
+type WebSocketConn struct {
+	// Conn is the underlying (hijacked) connection.
+	Conn net.Conn
+	// Reader is the buffered reader for the connection.
+	Reader *bufio.Reader
+	// Subprotocol is the negotiated subprotocol if any.
+	Subprotocol string
+	// ReadLimit is the maximum size in bytes of a (reassembled) message.
+	// If it is less than or equal to zero messages are not limited, but frames longer
+	// than `math.MaxInt32` bytes are still rejected.
+	ReadLimit int64
+	// ReadTimeout, if set, is applied as a deadline before each frame read.
+	ReadTimeout time.Duration
+	// WriteTimeout, if set, is applied as a deadline before each frame write.
+	WriteTimeout time.Duration
+	// OnPing is called when a ping is received; if unset a pong is sent with the same payload.
+	OnPing func(*WebSocketConn, []byte) error
+	// OnPong is called when a pong is received.
+	OnPong func(*WebSocketConn, []byte) error
+
+	writeMu   sync.Mutex
+	closeSent bool
+	closeCode int
+}
+
+// ReadMessage reads the next complete data message from the connection.
+//
+// Control frames received while waiting are handled inline; pings are answered
+// (or passed to `OnPing`), pongs are passed to `OnPong`, and a close frame is
+// acknowledged and returned as a `*WebSocketCloseError`.
+func (wsc *WebSocketConn) ReadMessage() (messageType WebSocketMessageType, data []byte, err error) {
+	var fin bool
+	var opcode WebSocketMessageType
+	var payload []byte
+	for {
+		fin, opcode, payload, err = wsc.readFrame()
+		if err != nil {
+			return
+		}
+
+		if opcode.IsControl() {
+			if err = wsc.handleControl(opcode, payload); err != nil {
+				return
+			}
+			continue
+		}
+
+		if opcode == WebSocketMessageContinuation {
+			if messageType == WebSocketMessageContinuation {
+				err = wsc.fail(WebSocketCloseProtocolError, ex.New(ErrWebSocketProtocol, ex.OptMessage("unexpected continuation frame")))
+				return
+			}
+		} else {
+			if messageType != WebSocketMessageContinuation {
+				err = wsc.fail(WebSocketCloseProtocolError, ex.New(ErrWebSocketProtocol, ex.OptMessage("expected continuation frame")))
+				return
+			}
+			if opcode != WebSocketMessageText && opcode != WebSocketMessageBinary {
+				err = wsc.fail(WebSocketCloseProtocolError, ex.New(ErrWebSocketProtocol, ex.OptMessagef("unknown opcode: %d", opcode)))
+				return
+			}
+			messageType = opcode
+		}
+
+		if wsc.ReadLimit > 0 && int64(len(data)+len(payload)) > wsc.ReadLimit {
+			err = wsc.fail(WebSocketCloseMessageTooBig, ex.New(ErrWebSocketReadLimit, ex.OptMessagef("limit: %d", wsc.ReadLimit)))
+			return
+		}
+		data = append(data, payload...)
+
+		if fin {
+			if messageType == WebSocketMessageText && !utf8.Valid(data) {
+				err = wsc.fail(WebSocketCloseInvalidPayload, ex.New(ErrWebSocketProtocol, ex.OptMessage("invalid utf-8 in text message")))
+				return
+			}
+			return
+		}
+	}
+}
+
+// WriteMessage writes a message as a single frame.
+func (wsc *WebSocketConn) WriteMessage(messageType WebSocketMessageType, data []byte) error {
+	if messageType.IsControl() && len(data) > webSocketMaxControlPayload {
+		return ex.New(ErrWebSocketProtocol, ex.OptMessage("control frame payload too large"))
+	}
+	wsc.writeMu.Lock()
+	defer wsc.writeMu.Unlock()
+	if wsc.closeSent {
+		return ex.New(ErrWebSocketClosed)
+	}
+	if messageType == WebSocketMessageClose {
+		wsc.closeSent = true
+		wsc.closeCode = WebSocketCloseNoStatus
+		if len(data) >= 2 {
+			wsc.closeCode = int(binary.BigEndian.Uint16(data))
+		}
+	}
+	return wsc.writeFrame(messageType, data)
+}
+
+// WriteText writes a text message.
+func (wsc *WebSocketConn) WriteText(text string) error {
+	return wsc.WriteMessage(WebSocketMessageText, []byte(text))
+}
+
+// WriteBinary writes a binary message.
+func (wsc *WebSocketConn) WriteBinary(data []byte) error {
+	return wsc.WriteMessage(WebSocketMessageBinary, data)
+}
+
+// Ping writes a ping control frame.
+func (wsc *WebSocketConn) Ping(data []byte) error {
+	return wsc.WriteMessage(WebSocketMessagePing, data)
+}
+
+// Pong writes a pong control frame.
+func (wsc *WebSocketConn) Pong(data []byte) error {
+	return wsc.WriteMessage(WebSocketMessagePong, data)
+}
+
+// WriteClose writes a close control frame with a given code and reason.
+// No further messages can be written after a close frame is sent.
+func (wsc *WebSocketConn) WriteClose(code int, reason string) error {
+	payload := make([]byte, 2, 2+len(reason))
+	binary.BigEndian.PutUint16(payload, uint16(code))
+	payload = append(payload, reason...)
+	return wsc.WriteMessage(WebSocketMessageClose, payload)
+}
+
+// CloseSent returns if a close frame has been sent.
+func (wsc *WebSocketConn) CloseSent() bool {
+	wsc.writeMu.Lock()
+	defer wsc.writeMu.Unlock()
+	return wsc.closeSent
+}
+
+// CloseCode returns the status code of the close frame sent, if any.
+func (wsc *WebSocketConn) CloseCode() int {
+	wsc.writeMu.Lock()
+	defer wsc.writeMu.Unlock()
+	return wsc.closeCode
+}
+
+// Close sends a normal close frame if one has not been sent and closes the underlying connection.
+func (wsc *WebSocketConn) Close() error {
+	if !wsc.CloseSent() {
+		_ = wsc.WriteClose(WebSocketCloseNormal, "")
+	}
+	if err := wsc.Conn.Close(); err != nil {
+		return ex.New(err)
+	}
+	return nil
+}
+
+//
+// internal helpers
+//
+
+func (wsc *WebSocketConn) handleControl(opcode WebSocketMessageType, payload []byte) error {
+	switch opcode {
+	case WebSocketMessagePing:
+		if wsc.OnPing != nil {
+			return wsc.OnPing(wsc, payload)
+		}
+		if err := wsc.Pong(payload); err != nil && !ex.Is(err, ErrWebSocketClosed) {
+			return err
+		}
+		return nil
+	case WebSocketMessagePong:
+		if wsc.OnPong != nil {
+			return wsc.OnPong(wsc, payload)
+		}
+		return nil
+	case WebSocketMessageClose:
+		closeErr := &WebSocketCloseError{Code: WebSocketCloseNoStatus}
+		if len(payload) == 1 {
+			return wsc.fail(WebSocketCloseProtocolError, ex.New(ErrWebSocketProtocol, ex.OptMessage("invalid close payload")))
+		}
+		if len(payload) >= 2 {
+			closeErr.Code = int(binary.BigEndian.Uint16(payload))
+			closeErr.Reason = string(payload[2:])
+		}
+		if !wsc.CloseSent() {
+			if closeErr.Code == WebSocketCloseNoStatus {
+				_ = wsc.WriteMessage(WebSocketMessageClose, nil)
+			} else {
+				_ = wsc.WriteClose(closeErr.Code, "")
+			}
+		}
+		return closeErr
+	default:
+		return wsc.fail(WebSocketCloseProtocolError, ex.New(ErrWebSocketProtocol, ex.OptMessagef("unknown control opcode: %d", opcode)))
+	}
+}
+
+// fail sends a close frame with a given code and returns the error.
+func (wsc *WebSocketConn) fail(code int, err error) error {
+	if !wsc.CloseSent() {
+		_ = wsc.WriteClose(code, "")
+	}
+	return err
+}
+
+func (wsc *WebSocketConn) readFrame() (fin bool, opcode WebSocketMessageType, payload []byte, err error) {
+	if wsc.ReadTimeout > 0 {
+		if err = wsc.Conn.SetReadDeadline(time.Now().Add(wsc.ReadTimeout)); err != nil {
+			err = ex.New(err)
+			return
+		}
+	}
+
+	var header [2]byte
+	if _, err = io.ReadFull(wsc.Reader, header[:]); err != nil {
+		err = ex.New(err)
+		return
+	}
+	if header[0]&webSocketReservedBits != 0 {
+		err = wsc.fail(WebSocketCloseProtocolError, ex.New(ErrWebSocketProtocol, ex.OptMessage("reserved bits set")))
+		return
+	}
+	fin = header[0]&webSocketFinalBit != 0
+	opcode = WebSocketMessageType(header[0] & webSocketOpcodeMask)
+	if header[1]&webSocketMaskBit == 0 {
+		err = wsc.fail(WebSocketCloseProtocolError, ex.New(ErrWebSocketProtocol, ex.OptMessage("client frames must be masked")))
+		return
+	}
+
+	length := uint64(header[1] & webSocketPayloadMask)
+	switch length {
+	case 126:
+		var extended [2]byte
+		if _, err = io.ReadFull(wsc.Reader, extended[:]); err != nil {
+			err = ex.New(err)
+			return
+		}
+		length = uint64(binary.BigEndian.Uint16(extended[:]))
+	case 127:
+		var extended [8]byte
+		if _, err = io.ReadFull(wsc.Reader, extended[:]); err != nil {
+			err = ex.New(err)
+			return
+		}
+		length = binary.BigEndian.Uint64(extended[:])
+	}
+
+	if opcode.IsControl() && (!fin || length > webSocketMaxControlPayload) {
+		err = wsc.fail(WebSocketCloseProtocolError, ex.New(ErrWebSocketProtocol, ex.OptMessage("invalid control frame")))
+		return
+	}
+	// frame lengths are peer supplied, so they are bounded even if the read limit is disabled.
+	if length > math.MaxInt32 || (wsc.ReadLimit > 0 && length > uint64(wsc.ReadLimit)) {
+		err = wsc.fail(WebSocketCloseMessageTooBig, ex.New(ErrWebSocketReadLimit, ex.OptMessagef("limit: %d, frame length: %d", wsc.ReadLimit, length)))
+		return
+	}
+
+	var mask [4]byte
+	if _, err = io.ReadFull(wsc.Reader, mask[:]); err != nil {
+		err = ex.New(err)
+		return
+	}
+	// read the payload as it arrives rather than allocating the declared length up front.
+	buffer := bytes.NewBuffer(make([]byte, 0, min(int(length), webSocketInitialPayloadBuffer)))
+	if _, err = io.CopyN(buffer, wsc.Reader, int64(length)); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		err = ex.New(err)
+		return
+	}
+	payload = buffer.Bytes()
+	for index := range payload {
+		payload[index] ^= mask[index%4]
+	}
+	return
+}
+
+// writeFrame writes an unmasked, final frame; it must be called with the write lock held.
+func (wsc *WebSocketConn) writeFrame(opcode WebSocketMessageType, data []byte) error {
+	if wsc.WriteTimeout > 0 {
+		if err := wsc.Conn.SetWriteDeadline(time.Now().Add(wsc.WriteTimeout)); err != nil {
+			return ex.New(err)
+		}
+	}
+
+	header := make([]byte, 2, 10+len(data))
+	header[0] = webSocketFinalBit | byte(opcode)
+	switch length := len(data); {
+	case length <= 125:
+		header[1] = byte(length)
+	case length <= 0xffff:
+		header[1] = 126
+		header = header[:4]
+		binary.BigEndian.PutUint16(header[2:], uint16(length))
+	default:
+		header[1] = 127
+		header = header[:10]
+		binary.BigEndian.PutUint64(header[2:], uint64(length))
+	}
+	if _, err := wsc.Conn.Write(append(header, data...)); err != nil {
+		return ex.New(err)
+	}
+	return nil
+}
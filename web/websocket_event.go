@@ -0,0 +1,130 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/blend/go-sdk/logger"
+	"github.com/blend/go-sdk/timeutil"
+	"github.com/blend/go-sdk/webutil"
+)
+
+// Logger flags for websocket events.
+const (
+	// FlagWebSocketOpen is a logger flag for websocket connections being established.
+	FlagWebSocketOpen = "http.websocket.open"
+	// FlagWebSocketClose is a logger flag for websocket connections being closed.
+	FlagWebSocketClose = "http.websocket.close"
+)
+
+// these are compile time assertions
+var (
+	_ logger.Event        = (*WebSocketEvent)(nil)
+	_ logger.TextWritable = (*WebSocketEvent)(nil)
+	_ json.Marshaler      = (*WebSocketEvent)(nil)
+)
+
+// NewWebSocketEvent returns a new websocket event.
+func NewWebSocketEvent(flag string, options ...WebSocketEventOption) *WebSocketEvent {
+	wse := &WebSocketEvent{
+		EventMeta: logger.NewEventMeta(flag),
+	}
+	for _, option := range options {
+		option(wse)
+	}
+	return wse
+}
+
+// WebSocketEventOption is an option for websocket events.
+type WebSocketEventOption func(*WebSocketEvent)
+
+// OptWebSocketEventRequest sets the request.
+func OptWebSocketEventRequest(req *http.Request) WebSocketEventOption {
+	return func(wse *WebSocketEvent) { wse.Request = req }
+}
+
+// OptWebSocketEventRoute sets the route.
+func OptWebSocketEventRoute(route *Route) WebSocketEventOption {
+	return func(wse *WebSocketEvent) {
+		if route != nil {
+			wse.Route = route.String()
+		}
+	}
+}
+
+// OptWebSocketEventSubprotocol sets the subprotocol.
+func OptWebSocketEventSubprotocol(subprotocol string) WebSocketEventOption {
+	return func(wse *WebSocketEvent) { wse.Subprotocol = subprotocol }
+}
+
+// OptWebSocketEventCloseCode sets the close code.
+func OptWebSocketEventCloseCode(code int) WebSocketEventOption {
+	return func(wse *WebSocketEvent) { wse.CloseCode = code }
+}
+
+// OptWebSocketEventElapsed sets the elapsed time.
+func OptWebSocketEventElapsed(elapsed time.Duration) WebSocketEventOption {
+	return func(wse *WebSocketEvent) { wse.Elapsed = elapsed }
+}
+
+// OptWebSocketEventErr sets the error.
+func OptWebSocketEventErr(err error) WebSocketEventOption {
+	return func(wse *WebSocketEvent) { wse.Err = err }
+}
+
+// WebSocketEvent is an event for websocket connection lifecycle changes.
+type WebSocketEvent struct {
+	*logger.EventMeta
+	Request     *http.Request
+	Route       string
+	Subprotocol string
+	CloseCode   int
+	Elapsed     time.Duration
+	Err         error
+}
+
+// WriteText implements logger.TextWritable.
+func (wse WebSocketEvent) WriteText(tf logger.TextFormatter, wr io.Writer) {
+	if wse.Request != nil {
+		io.WriteString(wr, wse.Request.URL.Path)
+	}
+	if wse.Subprotocol != "" {
+		io.WriteString(wr, logger.Space)
+		io.WriteString(wr, wse.Subprotocol)
+	}
+	if wse.CloseCode > 0 {
+		io.WriteString(wr, logger.Space)
+		io.WriteString(wr, fmt.Sprintf("%d", wse.CloseCode))
+	}
+	if wse.Elapsed > 0 {
+		io.WriteString(wr, logger.Space)
+		io.WriteString(wr, wse.Elapsed.String())
+	}
+	if wse.Err != nil {
+		io.WriteString(wr, logger.Space)
+		io.WriteString(wr, wse.Err.Error())
+	}
+}
+
+// MarshalJSON implements json.Marshaler.
+func (wse WebSocketEvent) MarshalJSON() ([]byte, error) {
+	output := map[string]interface{}{
+		"route":       wse.Route,
+		"subprotocol": wse.Subprotocol,
+		"closeCode":   wse.CloseCode,
+		"elapsed":     timeutil.Milliseconds(wse.Elapsed),
+	}
+	if wse.Request != nil {
+		output["ip"] = webutil.GetRemoteAddr(wse.Request)
+		output["userAgent"] = webutil.GetUserAgent(wse.Request)
+		output["path"] = wse.Request.URL.Path
+		output["host"] = wse.Request.Host
+	}
+	if wse.Err != nil {
+		output["err"] = wse.Err.Error()
+	}
+	return json.Marshal(logger.MergeDecomposed(wse.EventMeta.Decompose(), output))
+}
@@ -0,0 +1,44 @@
+package web
+
+import (
+	"net/http"
+	"time"
+)
+
+// WebSocketOption is an option for websocket upgraders.
+type WebSocketOption func(*WebSocketUpgrader)
+
+// OptWebSocketSubprotocols sets the supported subprotocols in order of preference.
+func OptWebSocketSubprotocols(subprotocols ...string) WebSocketOption {
+	return func(wsu *WebSocketUpgrader) { wsu.Subprotocols = subprotocols }
+}
+
+// OptWebSocketCheckOrigin sets the origin check.
+func OptWebSocketCheckOrigin(checkOrigin func(*http.Request) bool) WebSocketOption {
+	return func(wsu *WebSocketUpgrader) { wsu.CheckOrigin = checkOrigin }
+}
+
+// OptWebSocketReadLimit sets the maximum message size in bytes.
+func OptWebSocketReadLimit(limit int64) WebSocketOption {
+	return func(wsu *WebSocketUpgrader) { wsu.ReadLimit = limit }
+}
+
+// OptWebSocketReadTimeout sets the per frame read deadline.
+func OptWebSocketReadTimeout(d time.Duration) WebSocketOption {
+	return func(wsu *WebSocketUpgrader) { wsu.ReadTimeout = d }
+}
+
+// OptWebSocketWriteTimeout sets the per frame write deadline.
+func OptWebSocketWriteTimeout(d time.Duration) WebSocketOption {
+	return func(wsu *WebSocketUpgrader) { wsu.WriteTimeout = d }
+}
+
+// OptWebSocketOnPing sets the ping handler.
+func OptWebSocketOnPing(handler func(*WebSocketConn, []byte) error) WebSocketOption {
+	return func(wsu *WebSocketUpgrader) { wsu.OnPing = handler }
+}
+
+// OptWebSocketOnPong sets the pong handler.
+func OptWebSocketOnPong(handler func(*WebSocketConn, []byte) error) WebSocketOption {
+	return func(wsu *WebSocketUpgrader) { wsu.OnPong = handler }
+}
@@ -0,0 +1,278 @@
+package web
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/logger"
+	"github.com/blend/go-sdk/webutil"
+)
+
+const testWebSocketKey = "dGhlIHNhbXBsZSBub25jZQ=="
+
+func dialTestWebSocket(t *testing.T, server *httptest.Server, path string, headers ...string) (net.Conn, *bufio.Reader, *http.Response) {
+	conn, err := net.Dial("tcp", webutil.MustParseURL(server.URL).Host)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, _ := http.NewRequest(MethodGet, server.URL+path, nil)
+	req.Header.Set(HeaderConnection, "Upgrade")
+	req.Header.Set(HeaderUpgrade, "websocket")
+	req.Header.Set(HeaderSecWebSocketVersion, WebSocketVersion)
+	req.Header.Set(HeaderSecWebSocketKey, testWebSocketKey)
+	for index := 0; index+1 < len(headers); index += 2 {
+		req.Header.Set(headers[index], headers[index+1])
+	}
+	if err = req.Write(conn); err != nil {
+		t.Fatal(err)
+	}
+	reader := bufio.NewReader(conn)
+	res, err := http.ReadResponse(reader, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return conn, reader, res
+}
+
+func writeTestWebSocketFrame(wr io.Writer, fin bool, opcode WebSocketMessageType, payload []byte) error {
+	var header []byte
+	first := byte(opcode)
+	if fin {
+		first |= webSocketFinalBit
+	}
+	header = append(header, first)
+	switch {
+	case len(payload) <= 125:
+		header = append(header, webSocketMaskBit|byte(len(payload)))
+	case len(payload) <= 0xffff:
+		header = append(header, webSocketMaskBit|126, 0, 0)
+		binary.BigEndian.PutUint16(header[2:], uint16(len(payload)))
+	default:
+		header = append(header, webSocketMaskBit|127, 0, 0, 0, 0, 0, 0, 0, 0)
+		binary.BigEndian.PutUint64(header[2:], uint64(len(payload)))
+	}
+	mask := []byte{1, 2, 3, 4}
+	header = append(header, mask...)
+	masked := make([]byte, len(payload))
+	for index := range payload {
+		masked[index] = payload[index] ^ mask[index%4]
+	}
+	_, err := wr.Write(append(header, masked...))
+	return err
+}
+
+func readTestWebSocketFrame(r io.Reader) (opcode WebSocketMessageType, payload []byte, err error) {
+	var header [2]byte
+	if _, err = io.ReadFull(r, header[:]); err != nil {
+		return
+	}
+	opcode = WebSocketMessageType(header[0] & webSocketOpcodeMask)
+	length := int(header[1] & webSocketPayloadMask)
+	if length == 126 {
+		var extended [2]byte
+		if _, err = io.ReadFull(r, extended[:]); err != nil {
+			return
+		}
+		length = int(binary.BigEndian.Uint16(extended[:]))
+	}
+	payload = make([]byte, length)
+	_, err = io.ReadFull(r, payload)
+	return
+}
+
+func echoWebSocket(_ *Ctx, conn *WebSocketConn) error {
+	for {
+		messageType, data, err := conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+		if err = conn.WriteMessage(messageType, data); err != nil {
+			return err
+		}
+	}
+}
+
+func TestWebSocketAccept(t *testing.T) {
+	assert := assert.New(t)
+	// from RFC 6455 section 1.3
+	assert.Equal("s3pPLMBiTxaQ9kYGzzhZRbK+xOo=", WebSocketAccept(testWebSocketKey))
+}
+
+func TestWebSocketEcho(t *testing.T) {
+	assert := assert.New(t)
+
+	app := MustNew()
+	app.GET("/ws", WebSocket(echoWebSocket, OptWebSocketSubprotocols("chat")))
+	server := httptest.NewServer(app)
+	defer server.Close()
+
+	conn, reader, res := dialTestWebSocket(t, server, "/ws", HeaderSecWebSocketProtocol, "superchat, chat")
+	defer conn.Close()
+	assert.Equal(http.StatusSwitchingProtocols, res.StatusCode)
+	assert.Equal("s3pPLMBiTxaQ9kYGzzhZRbK+xOo=", res.Header.Get(HeaderSecWebSocketAccept))
+	assert.Equal("chat", res.Header.Get(HeaderSecWebSocketProtocol))
+
+	assert.Nil(writeTestWebSocketFrame(conn, true, WebSocketMessageText, []byte("hello")))
+	opcode, payload, err := readTestWebSocketFrame(reader)
+	assert.Nil(err)
+	assert.Equal(WebSocketMessageText, opcode)
+	assert.Equal("hello", string(payload))
+
+	// fragmented with an interleaved ping
+	assert.Nil(writeTestWebSocketFrame(conn, false, WebSocketMessageBinary, []byte("foo")))
+	assert.Nil(writeTestWebSocketFrame(conn, true, WebSocketMessagePing, []byte("ping")))
+	assert.Nil(writeTestWebSocketFrame(conn, true, WebSocketMessageContinuation, bytes.Repeat([]byte("a"), 200)))
+
+	opcode, payload, err = readTestWebSocketFrame(reader)
+	assert.Nil(err)
+	assert.Equal(WebSocketMessagePong, opcode)
+	assert.Equal("ping", string(payload))
+
+	opcode, payload, err = readTestWebSocketFrame(reader)
+	assert.Nil(err)
+	assert.Equal(WebSocketMessageBinary, opcode)
+	assert.Len(payload, 203)
+
+	closePayload := make([]byte, 2)
+	binary.BigEndian.PutUint16(closePayload, WebSocketCloseNormal)
+	assert.Nil(writeTestWebSocketFrame(conn, true, WebSocketMessageClose, closePayload))
+	opcode, payload, err = readTestWebSocketFrame(reader)
+	assert.Nil(err)
+	assert.Equal(WebSocketMessageClose, opcode)
+	assert.Equal(WebSocketCloseNormal, binary.BigEndian.Uint16(payload))
+}
+
+func TestWebSocketReadLimit(t *testing.T) {
+	assert := assert.New(t)
+
+	app := MustNew()
+	app.GET("/ws", WebSocket(echoWebSocket, OptWebSocketReadLimit(16)))
+	server := httptest.NewServer(app)
+	defer server.Close()
+
+	conn, reader, res := dialTestWebSocket(t, server, "/ws")
+	defer conn.Close()
+	assert.Equal(http.StatusSwitchingProtocols, res.StatusCode)
+
+	assert.Nil(writeTestWebSocketFrame(conn, true, WebSocketMessageText, bytes.Repeat([]byte("a"), 32)))
+	opcode, payload, err := readTestWebSocketFrame(reader)
+	assert.Nil(err)
+	assert.Equal(WebSocketMessageClose, opcode)
+	assert.Equal(WebSocketCloseMessageTooBig, binary.BigEndian.Uint16(payload))
+}
+
+func TestWebSocketFrameLengthUnlimited(t *testing.T) {
+	assert := assert.New(t)
+
+	app := MustNew()
+	app.GET("/ws", WebSocket(echoWebSocket, OptWebSocketReadLimit(0)))
+	server := httptest.NewServer(app)
+	defer server.Close()
+
+	conn, reader, res := dialTestWebSocket(t, server, "/ws")
+	defer conn.Close()
+	assert.Equal(http.StatusSwitchingProtocols, res.StatusCode)
+
+	// a frame header declaring a 1TiB payload, which must not be allocated.
+	header := []byte{webSocketFinalBit | byte(WebSocketMessageBinary), webSocketMaskBit | 127, 0, 0, 0, 0, 0, 0, 0, 0, 1, 2, 3, 4}
+	binary.BigEndian.PutUint64(header[2:10], 1<<40)
+	_, err := conn.Write(header)
+	assert.Nil(err)
+
+	opcode, payload, err := readTestWebSocketFrame(reader)
+	assert.Nil(err)
+	assert.Equal(WebSocketMessageClose, opcode)
+	assert.Equal(WebSocketCloseMessageTooBig, binary.BigEndian.Uint16(payload))
+}
+
+func TestWebSocketBadHandshake(t *testing.T) {
+	assert := assert.New(t)
+
+	app := MustNew()
+	app.GET("/ws", WebSocket(echoWebSocket))
+
+	meta, err := MockGet(app, "/ws").DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusBadRequest, meta.StatusCode)
+
+	server := httptest.NewServer(app)
+	defer server.Close()
+
+	conn, _, res := dialTestWebSocket(t, server, "/ws", HeaderSecWebSocketVersion, "8")
+	conn.Close()
+	assert.Equal(http.StatusUpgradeRequired, res.StatusCode)
+	assert.Equal(WebSocketVersion, res.Header.Get(HeaderSecWebSocketVersion))
+
+	conn, _, res = dialTestWebSocket(t, server, "/ws", HeaderOrigin, "https://evil.example.com")
+	conn.Close()
+	assert.Equal(http.StatusForbidden, res.StatusCode)
+}
+
+func TestWebSocketEvents(t *testing.T) {
+	assert := assert.New(t)
+
+	opens := make(chan *WebSocketEvent, 1)
+	closes := make(chan *WebSocketEvent, 1)
+	log := logger.None()
+	log.Flags.Enable(FlagWebSocketOpen, FlagWebSocketClose)
+	log.Listen(FlagWebSocketOpen, "test", func(_ context.Context, e logger.Event) { opens <- e.(*WebSocketEvent) })
+	log.Listen(FlagWebSocketClose, "test", func(_ context.Context, e logger.Event) { closes <- e.(*WebSocketEvent) })
+
+	app := MustNew(OptLog(log))
+	app.GET("/ws", WebSocket(func(_ *Ctx, conn *WebSocketConn) error {
+		return conn.WriteText("bye")
+	}))
+	server := httptest.NewServer(app)
+	defer server.Close()
+
+	conn, _, res := dialTestWebSocket(t, server, "/ws")
+	defer conn.Close()
+	assert.Equal(http.StatusSwitchingProtocols, res.StatusCode)
+
+	select {
+	case opened := <-opens:
+		assert.Equal(FlagWebSocketOpen, opened.GetFlag())
+		assert.Equal("/ws", opened.Route)
+	case <-time.After(5 * time.Second):
+		assert.FailNow("should have received an open event")
+	}
+
+	select {
+	case closed := <-closes:
+		assert.Equal(FlagWebSocketClose, closed.GetFlag())
+		assert.Equal(WebSocketCloseNormal, closed.CloseCode)
+		assert.Nil(closed.Err)
+	case <-time.After(5 * time.Second):
+		assert.FailNow("should have received a close event")
+	}
+}
+
+func TestWebSocketEventMarshalJSON(t *testing.T) {
+	assert := assert.New(t)
+
+	e := NewWebSocketEvent(FlagWebSocketClose,
+		OptWebSocketEventRequest(webutil.NewMockRequest("GET", "/ws")),
+		OptWebSocketEventCloseCode(WebSocketCloseNormal),
+	)
+	contents, err := json.Marshal(e)
+	assert.Nil(err)
+
+	var decoded map[string]interface{}
+	assert.Nil(json.Unmarshal(contents, &decoded))
+	assert.Equal("/ws", decoded["path"])
+	assert.Equal(float64(WebSocketCloseNormal), decoded["closeCode"])
+
+	output := new(bytes.Buffer)
+	e.WriteText(logger.NewTextOutputFormatter(logger.OptTextNoColor()), output)
+	assert.Equal("/ws 1000", output.String())
+}
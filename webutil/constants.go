@@ -34,6 +34,8 @@ var (
 	HeaderXXSSProtection          = http.CanonicalHeaderKey("X-Xss-Protection")
 	HeaderXContentTypeOptions     = http.CanonicalHeaderKey("X-Content-Type-Options")
 	HeaderStrictTransportSecurity = http.CanonicalHeaderKey("Strict-Transport-Security")
+	HeaderXSignature              = http.CanonicalHeaderKey("X-Signature")
+	HeaderXSignatureTimestamp     = http.CanonicalHeaderKey("X-Signature-Timestamp")
 )
 
 /*
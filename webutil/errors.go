@@ -5,4 +5,17 @@ import "github.com/blend/go-sdk/ex"
 // Errors
 const (
 	ErrInvalidSameSite ex.Class = "invalid cookie same site string value"
+
+	ErrSignatureMissing ex.Class = "request signature missing"
+	ErrSignatureInvalid ex.Class = "request signature invalid"
+	ErrSignatureExpired  ex.Class = "request signature timestamp outside of tolerance"
+	ErrSignatureReplayed ex.Class = "request signature already used"
 )
+
+// IsErrSignature returns if an error is a request signature error.
+func IsErrSignature(err error) bool {
+	if err == nil {
+		return false
+	}
+	return ex.Is(err, ErrSignatureMissing) || ex.Is(err, ErrSignatureInvalid) || ex.Is(err, ErrSignatureExpired) || ex.Is(err, ErrSignatureReplayed)
+}
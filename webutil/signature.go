@@ -0,0 +1,89 @@
+package webutil
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/blend/go-sdk/crypto"
+	"github.com/blend/go-sdk/ex"
+)
+
+// DefaultSignatureTolerance is the default maximum difference between a signature timestamp and the current time.
+const DefaultSignatureTolerance = 5 * time.Minute
+
+// SignatureMessage returns the message an HMAC request signature is computed over.
+//
+// It is the unix timestamp, the method, the request uri (the path and query) and the body,
+// separated by newlines, so a signature cannot be replayed against a different endpoint.
+func SignatureMessage(timestamp int64, method, requestURI string, body []byte) []byte {
+	message := new(bytes.Buffer)
+	message.WriteString(strconv.FormatInt(timestamp, 10))
+	message.WriteByte('\n')
+	message.WriteString(method)
+	message.WriteByte('\n')
+	message.WriteString(requestURI)
+	message.WriteByte('\n')
+	message.Write(body)
+	return message.Bytes()
+}
+
+// Signature returns the hex encoded HMAC-SHA512 signature of a request.
+func Signature(key []byte, timestamp int64, method, requestURI string, body []byte) string {
+	return hex.EncodeToString(crypto.HMAC512(key, SignatureMessage(timestamp, method, requestURI, body)))
+}
+
+// SignRequest sets the `X-Signature` and `X-Signature-Timestamp` headers on a request
+// for a given key, body and timestamp.
+func SignRequest(req *http.Request, key, body []byte, timestamp time.Time) {
+	if req.Header == nil {
+		req.Header = make(http.Header)
+	}
+	unix := timestamp.Unix()
+	req.Header.Set(HeaderXSignatureTimestamp, strconv.FormatInt(unix, 10))
+	req.Header.Set(HeaderXSignature, Signature(key, unix, methodOrDefault(req.Method), req.URL.RequestURI(), body))
+}
+
+// VerifyRequestSignature verifies the signature headers of a request against a body.
+//
+// The signature must match one of the keys, which allows keys to be rotated, and the timestamp
+// must be within the tolerance of the current time, or `DefaultSignatureTolerance` if the tolerance is not positive.
+// It does not record signatures, so callers reject replays within the tolerance, e.g. `web.HMACSignatureVerifier`.
+func VerifyRequestSignature(req *http.Request, body []byte, now time.Time, tolerance time.Duration, keys ...[]byte) error {
+	signature := req.Header.Get(HeaderXSignature)
+	timestampValue := req.Header.Get(HeaderXSignatureTimestamp)
+	if signature == "" || timestampValue == "" {
+		return ex.New(ErrSignatureMissing)
+	}
+	timestamp, err := strconv.ParseInt(timestampValue, 10, 64)
+	if err != nil {
+		return ex.New(ErrSignatureInvalid, ex.OptMessage("invalid timestamp"))
+	}
+	if tolerance <= 0 {
+		tolerance = DefaultSignatureTolerance
+	}
+	if delta := now.Sub(time.Unix(timestamp, 0)); delta > tolerance || delta < -tolerance {
+		return ex.New(ErrSignatureExpired, ex.OptMessagef("timestamp: %d", timestamp))
+	}
+	decoded, err := hex.DecodeString(signature)
+	if err != nil {
+		return ex.New(ErrSignatureInvalid)
+	}
+	message := SignatureMessage(timestamp, methodOrDefault(req.Method), req.URL.RequestURI(), body)
+	for _, key := range keys {
+		if hmac.Equal(decoded, crypto.HMAC512(key, message)) {
+			return nil
+		}
+	}
+	return ex.New(ErrSignatureInvalid)
+}
+
+func methodOrDefault(method string) string {
+	if method == "" {
+		return http.MethodGet
+	}
+	return method
+}
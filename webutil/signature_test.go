@@ -0,0 +1,46 @@
+package webutil
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/ex"
+)
+
+func TestVerifyRequestSignature(t *testing.T) {
+	assert := assert.New(t)
+
+	key := []byte("current-key")
+	body := []byte(`{"event":"created"}`)
+	now := time.Date(2019, 10, 10, 12, 00, 00, 00, time.UTC)
+
+	req := &http.Request{Method: http.MethodPost, URL: &url.URL{Path: "/webhooks", RawQuery: "source=partner"}}
+	SignRequest(req, key, body, now)
+	assert.Equal("1570708800", req.Header.Get(HeaderXSignatureTimestamp))
+	assert.NotEmpty(req.Header.Get(HeaderXSignature))
+
+	assert.Nil(VerifyRequestSignature(req, body, now.Add(time.Minute), DefaultSignatureTolerance, key))
+	// keys can be rotated.
+	assert.Nil(VerifyRequestSignature(req, body, now, DefaultSignatureTolerance, []byte("next-key"), key))
+
+	assert.True(ex.Is(VerifyRequestSignature(req, body, now, DefaultSignatureTolerance, []byte("wrong-key")), ErrSignatureInvalid))
+	assert.True(ex.Is(VerifyRequestSignature(req, []byte(`{"event":"deleted"}`), now, DefaultSignatureTolerance, key), ErrSignatureInvalid))
+	assert.True(ex.Is(VerifyRequestSignature(req, body, now.Add(10*time.Minute), DefaultSignatureTolerance, key), ErrSignatureExpired))
+	assert.True(ex.Is(VerifyRequestSignature(req, body, now.Add(-10*time.Minute), DefaultSignatureTolerance, key), ErrSignatureExpired))
+
+	// the signature covers the path.
+	replayed := &http.Request{Method: http.MethodPost, URL: &url.URL{Path: "/other"}, Header: req.Header}
+	assert.True(ex.Is(VerifyRequestSignature(replayed, body, now, DefaultSignatureTolerance, key), ErrSignatureInvalid))
+
+	// tolerances that are not positive use the default.
+	assert.Nil(VerifyRequestSignature(req, body, now.Add(time.Minute), 0, key))
+	assert.True(ex.Is(VerifyRequestSignature(req, body, now.Add(10*time.Minute), 0, key), ErrSignatureExpired))
+
+	unsigned := &http.Request{Method: http.MethodPost, URL: &url.URL{Path: "/webhooks"}, Header: http.Header{}}
+	err := VerifyRequestSignature(unsigned, body, now, DefaultSignatureTolerance, key)
+	assert.True(ex.Is(err, ErrSignatureMissing))
+	assert.True(IsErrSignature(err))
+}